@@ -0,0 +1,362 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/theme"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+// checkpointView is the state of the `wt _checkpoints` browser: a
+// worktree's checkpoints, newest first, and the files changed between two of
+// them with their diffs.
+type checkpointView struct {
+	path, alias string
+
+	list    []worktree.Checkpoint
+	cursor  int
+	top     int
+	mark    int    // N of the checkpoint diffs start from, 0 for the previous one
+	confirm string // "restore" or "delete" waiting for y
+	err     string
+	status  string // outcome of the last action, shown until the next key
+
+	open     bool // showing the files changed up to the checkpoint at cursor
+	from, to string
+	diff     diffView
+}
+
+// runCheckpoints lists a worktree's checkpoints with their diffstats, diffs
+// them against each other, takes new ones, and restores or deletes them.
+// Args: <path> --alias=<alias>
+func runCheckpoints(args []string) {
+	v := &checkpointView{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--alias=") {
+			v.alias = strings.TrimPrefix(arg, "--alias=")
+		} else if v.path == "" {
+			v.path = arg
+		}
+	}
+	if v.path == "" || v.alias == "" {
+		fmt.Fprintln(os.Stderr, "usage: wt _checkpoints <path> --alias=<alias>")
+		os.Exit(1)
+	}
+	v.load()
+	run_raw_view("_checkpoints", v.draw, v.handle_key)
+}
+
+// load reads the checkpoints, keeping the cursor on the same one when it's
+// still there.
+func (v *checkpointView) load() {
+	selected := 0
+	if c := v.checkpoint(); c != nil {
+		selected = c.N
+	}
+	v.err = ""
+	list, err := worktree.ListCheckpoints(v.path, v.alias)
+	if err != nil {
+		v.err = err.Error()
+	}
+	v.list = list
+	v.cursor = 0
+	for i, c := range v.list {
+		if c.N == selected {
+			v.cursor = i
+		}
+	}
+	if v.find(v.mark) == nil {
+		v.mark = 0
+	}
+}
+
+// checkpoint returns the checkpoint under the cursor, nil when there are none.
+func (v *checkpointView) checkpoint() *worktree.Checkpoint {
+	if v.cursor < 0 || v.cursor >= len(v.list) {
+		return nil
+	}
+	return &v.list[v.cursor]
+}
+
+func (v *checkpointView) find(n int) *worktree.Checkpoint {
+	for i := range v.list {
+		if v.list[i].N == n {
+			return &v.list[i]
+		}
+	}
+	return nil
+}
+
+// open_changes lists the files changed up to the selected checkpoint: from
+// the marked one, else from the one before it.
+func (v *checkpointView) open_changes() {
+	c := v.checkpoint()
+	if c == nil {
+		return
+	}
+	from, label := c.From, "the previous one"
+	if v.cursor == len(v.list)-1 {
+		label = "HEAD when taken"
+	}
+	if m := v.find(v.mark); m != nil && m.N != c.N {
+		from, label = m.SHA, fmt.Sprintf("#%d", m.N)
+	}
+	v.open = true
+	v.from, v.to = label, fmt.Sprintf("#%d", c.N)
+
+	to := c.SHA
+	v.diff = diffView{path: v.path}
+	v.diff.file_diff = func(f worktree.FileChange) (string, error) {
+		return worktree.CheckpointFileDiff(v.path, from, to, f)
+	}
+	var err error
+	if v.diff.files, err = worktree.CheckpointFiles(v.path, from, to); err != nil {
+		v.diff.err = fmt.Sprintf("git diff failed: %v", err)
+	}
+}
+
+// take checkpoints the working tree now.
+func (v *checkpointView) take() {
+	c, err := worktree.CreateCheckpoint(v.path, v.alias, "Checkpoint")
+	if err != nil {
+		v.status = "Checkpoint failed: " + err.Error()
+		return
+	}
+	v.load()
+	v.cursor = 0
+	v.status = fmt.Sprintf("Took checkpoint #%d", c.N)
+}
+
+// apply runs the confirmed restore or delete on the selected checkpoint.
+func (v *checkpointView) apply(action string) {
+	c := v.checkpoint()
+	if c == nil {
+		return
+	}
+	switch action {
+	case "restore":
+		saved, err := worktree.RestoreCheckpoint(v.path, v.alias, *c)
+		switch {
+		case err != nil && saved.N == 0:
+			v.status = "Restore failed: " + err.Error()
+		case err != nil:
+			v.status = fmt.Sprintf("Restore failed: %v (the files before it are in #%d)", err, saved.N)
+		default:
+			v.status = fmt.Sprintf("Restored #%d, the files before it are in #%d", c.N, saved.N)
+		}
+	case "delete":
+		if err := worktree.DeleteCheckpoint(v.path, *c); err != nil {
+			v.status = "Delete failed: " + err.Error()
+		} else {
+			v.status = fmt.Sprintf("Deleted #%d", c.N)
+		}
+	}
+	status := v.status
+	v.load()
+	v.status = status
+}
+
+// handle_key applies a key press; it returns false to quit.
+func (v *checkpointView) handle_key(k string) bool {
+	v.status = ""
+	if v.confirm != "" {
+		action := v.confirm
+		v.confirm = ""
+		if k == "y" || k == "Y" {
+			v.apply(action)
+		}
+		return true
+	}
+	if k == "q" || k == "\x03" {
+		return false
+	}
+	if v.open && v.diff.open {
+		return v.diff.handle_key(k)
+	}
+	if v.open {
+		d := &v.diff
+		switch k {
+		case "\x1b[A", "k":
+			d.cursor = max(d.cursor-1, 0)
+		case "\x1b[B", "j":
+			d.cursor = min(d.cursor+1, max(len(d.files)-1, 0))
+		case "\r", "\n", "l", "\x1b[C":
+			d.open_file(d.cursor)
+		case "\x1b", "h", "\x1b[D":
+			v.open = false
+		}
+		return true
+	}
+
+	last := max(len(v.list)-1, 0)
+	switch k {
+	case "\x1b[A", "k":
+		v.cursor = max(v.cursor-1, 0)
+	case "\x1b[B", "j":
+		v.cursor = min(v.cursor+1, last)
+	case "g":
+		v.cursor = 0
+	case "G":
+		v.cursor = last
+	case "\r", "\n", "l", "\x1b[C":
+		v.open_changes()
+	case " ":
+		if c := v.checkpoint(); c != nil && v.mark != c.N {
+			v.mark = c.N
+		} else {
+			v.mark = 0
+		}
+	case "c":
+		v.take()
+	case "R":
+		if v.checkpoint() != nil {
+			v.confirm = "restore"
+		}
+	case "x":
+		if v.checkpoint() != nil {
+			v.confirm = "delete"
+		}
+	case "r":
+		v.load()
+	case "\x1b":
+		return false
+	}
+	return true
+}
+
+func (v *checkpointView) draw() {
+	tw, th := termSize()
+	var lines []string
+	switch {
+	case v.open && v.diff.open:
+		lines = v.diff.render_diff(tw, th)
+	case v.open:
+		lines = v.render_changes(tw, th)
+	default:
+		lines = v.render_list(tw, th)
+	}
+	fmt.Print("\033[2J\033[H\033[?25l")
+	fmt.Print(strings.Join(lines, "\r\n"))
+}
+
+// footer is the key hints, a pending confirmation, or the outcome of the
+// last action.
+func (v *checkpointView) footer(hints string) string {
+	t := theme.Current()
+	if c := v.checkpoint(); c != nil && v.confirm == "restore" {
+		return " " + theme.FG(t.Hint) + fmt.Sprintf("Restore the files to #%d? The current ones are checkpointed first. y/n", c.N) + ansiReset
+	}
+	if c := v.checkpoint(); c != nil && v.confirm == "delete" {
+		return " " + theme.FG(t.Hint) + fmt.Sprintf("Delete checkpoint #%d? y/n", c.N) + ansiReset
+	}
+	if v.status != "" {
+		return " " + theme.FG(t.Hint) + v.status + ansiReset
+	}
+	return " " + ansiDim + hints + ansiReset
+}
+
+// render_list renders the header, one checkpoint per line and the key hints.
+func (v *checkpointView) render_list(tw, th int) []string {
+	t := theme.Current()
+	header := ansiCyan + "Checkpoints" + ansiReset + " " + pickBold + filepath.Base(v.path) + ansiReset
+	if m := v.find(v.mark); m != nil {
+		header += ansiDim + fmt.Sprintf("  diffs from #%d", m.N) + ansiReset
+	}
+	lines := []string{" " + header, ""}
+
+	if v.err != "" {
+		lines = append(lines, " "+theme.FG(t.Error)+v.err+ansiReset)
+	} else if len(v.list) == 0 {
+		lines = append(lines, " "+pickDim+"No checkpoints yet — c takes one"+ansiReset)
+	} else {
+		rows := max(th-4, 1)
+		if v.cursor < v.top {
+			v.top = v.cursor
+		} else if v.cursor >= v.top+rows {
+			v.top = v.cursor - rows + 1
+		}
+		for i := v.top; i < len(v.list) && i < v.top+rows; i++ {
+			lines = append(lines, v.checkpoint_row(v.list[i], i == v.cursor, tw))
+		}
+	}
+
+	for len(lines) < th-1 {
+		lines = append(lines, "")
+	}
+	return append(lines, v.footer("↑↓ select  Enter changes  ␣ diff from  c take  R restore  x delete  r refresh  q quit"))
+}
+
+// checkpoint_row renders one checkpoint: number, age, diffstat and message.
+func (v *checkpointView) checkpoint_row(c worktree.Checkpoint, selected bool, tw int) string {
+	t := theme.Current()
+	prefix := "  "
+	if selected {
+		prefix = ansiCyan + "▸ " + ansiReset
+	}
+	number := fmt.Sprintf("#%-3d", c.N)
+	if c.N == v.mark {
+		number = theme.FG(t.Focus) + number + ansiReset
+	} else {
+		number = theme.FG(t.Hint) + number + ansiReset
+	}
+	stat := fmt.Sprintf("%-9s", plural_files(c.Files))
+	counts := theme.FG(t.Running) + fmt.Sprintf("+%-5d", c.Added) + ansiReset + " " +
+		theme.FG(t.Stopped) + fmt.Sprintf("−%-5d", c.Deleted) + ansiReset
+	age := fmt.Sprintf("%-8s", ui.FormatAge(c.Date))
+	room := tw - 3 - 5 - 9 - 1 - 9 - 1 - 14 - 1
+	message := c.Message
+	if r := []rune(message); room > 1 && len(r) > room {
+		message = string(r[:room-1]) + "…"
+	}
+	if selected {
+		message = pickBold + message + ansiReset
+	}
+	return " " + prefix + number + " " + ansiDim + age + " " + stat + ansiReset + " " + counts + " " + message
+}
+
+// render_changes renders the files changed between the two checkpoints
+// compared.
+func (v *checkpointView) render_changes(tw, th int) []string {
+	t := theme.Current()
+	c := v.checkpoint()
+	lines := []string{
+		" " + ansiCyan + "Checkpoint" + ansiReset + " " + pickBold + v.to + ansiReset + ansiDim + " vs " + v.from + ansiReset,
+		" " + ansiDim + "Taken  " + ansiReset + c.Date.Format("Mon Jan 2 15:04 2006") + ansiDim + " · " + ui.FormatAge(c.Date) + " · " + c.Message + ansiReset,
+		"",
+	}
+
+	d := &v.diff
+	if d.err != "" {
+		lines = append(lines, " "+theme.FG(t.Error)+d.err+ansiReset)
+	} else if len(d.files) == 0 {
+		lines = append(lines, " "+pickDim+"No file changes"+ansiReset)
+	} else {
+		added, deleted := 0, 0
+		for _, f := range d.files {
+			added += f.Added
+			deleted += f.Deleted
+		}
+		lines = append(lines, " "+pickDim+plural_files(len(d.files))+ansiReset+"  "+
+			theme.FG(t.Running)+fmt.Sprintf("+%d", added)+ansiReset+" "+
+			theme.FG(t.Stopped)+fmt.Sprintf("−%d", deleted)+ansiReset)
+
+		rows := max(th-1-len(lines), 1)
+		if d.cursor < d.list_top {
+			d.list_top = d.cursor
+		} else if d.cursor >= d.list_top+rows {
+			d.list_top = d.cursor - rows + 1
+		}
+		for i := d.list_top; i < len(d.files) && i < d.list_top+rows; i++ {
+			lines = append(lines, d.file_row(d.files[i], i == d.cursor, tw))
+		}
+	}
+
+	for len(lines) < th-1 {
+		lines = append(lines, "")
+	}
+	return append(lines[:th-1], v.footer("↑↓ select  Enter diff  Esc checkpoints  q quit"))
+}
@@ -0,0 +1,443 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unicode"
+
+	"github.com/elvisnm/wt/internal/theme"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	"golang.org/x/term"
+)
+
+// diffView is the state of the `wt _diff` viewer: the changed files, and the
+// diff of the one opened.
+type diffView struct {
+	path, base, merge_base string
+
+	files    []worktree.FileChange
+	cursor   int
+	list_top int
+	err      string
+
+	open      bool // showing the diff of files[cursor]
+	file_diff func(worktree.FileChange) (string, error)
+	lines     []string
+	hunks     []int
+	top       int
+}
+
+// runDiff shows what a worktree changed against the merge-base with its base
+// ref, committed or not: a file list, and a highlighted diff per file with
+// hunk navigation. Args: <path> [--base=<ref>]
+func runDiff(args []string) {
+	v := &diffView{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--base=") {
+			v.base = strings.TrimPrefix(arg, "--base=")
+		} else if v.path == "" {
+			v.path = arg
+		}
+	}
+	if v.path == "" {
+		fmt.Fprintln(os.Stderr, "usage: wt _diff <path> [--base=<ref>]")
+		os.Exit(1)
+	}
+	if v.base == "" {
+		v.base = worktree.ResolveBaseRef(v.path, nil)
+	}
+	v.base = strings.TrimPrefix(strings.TrimPrefix(v.base, "refs/remotes/"), "refs/heads/")
+	v.file_diff = func(f worktree.FileChange) (string, error) {
+		return worktree.FileDiff(v.path, v.merge_base, f)
+	}
+	v.load()
+	run_raw_view("_diff", v.draw, v.handle_key)
+}
+
+// run_raw_view puts the terminal in raw mode and redraws after every key
+// press and resize until handle_key returns false.
+func run_raw_view(name string, draw func(), handle_key func(string) bool) {
+	old_state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wt %s: failed to set raw mode: %v\n", name, err)
+		os.Exit(1)
+	}
+	defer func() {
+		term.Restore(int(os.Stdin.Fd()), old_state)
+		fmt.Print("\033[2J\033[H\033[?25h")
+	}()
+
+	keys := make(chan string, 8)
+	go func() {
+		buf := make([]byte, 8)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- string(buf[:n])
+		}
+	}()
+	resize := make(chan os.Signal, 1)
+	signal.Notify(resize, syscall.SIGWINCH)
+
+	draw()
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok || !handle_key(k) {
+				return
+			}
+		case <-resize:
+		}
+		draw()
+	}
+}
+
+// load reads the merge-base and the changed files.
+func (v *diffView) load() {
+	v.err = ""
+	v.files = nil
+	if v.base == "" {
+		v.err = "No base branch found (origin/main, main, origin/master, master)"
+		return
+	}
+	mb, err := worktree.MergeBase(v.path, v.base)
+	if err != nil {
+		v.err = fmt.Sprintf("No merge-base with %s", v.base)
+		return
+	}
+	v.merge_base = mb
+	if v.files, err = worktree.ChangedFiles(v.path, mb); err != nil {
+		v.err = fmt.Sprintf("git diff failed: %v", err)
+	}
+	if v.cursor >= len(v.files) {
+		v.cursor = max(len(v.files)-1, 0)
+	}
+}
+
+// open_file loads the diff of files[i].
+func (v *diffView) open_file(i int) {
+	if i < 0 || i >= len(v.files) {
+		return
+	}
+	v.cursor = i
+	v.open = true
+	v.top = 0
+	out, err := v.file_diff(v.files[i])
+	if err != nil {
+		out = fmt.Sprintf("git diff failed: %v", err)
+	}
+	v.lines = strings.Split(strings.TrimRight(out, "\n"), "\n")
+	v.hunks = worktree.HunkStarts(v.lines)
+}
+
+// handle_key applies a key press; it returns false to quit.
+func (v *diffView) handle_key(k string) bool {
+	_, th := termSize()
+	page := max(th-4, 1)
+	if k == "q" || k == "\x03" {
+		return false
+	}
+
+	if !v.open {
+		switch k {
+		case "\x1b[A", "k":
+			v.cursor = max(v.cursor-1, 0)
+		case "\x1b[B", "j":
+			v.cursor = min(v.cursor+1, max(len(v.files)-1, 0))
+		case "\x1b[5~":
+			v.cursor = max(v.cursor-page, 0)
+		case "\x1b[6~":
+			v.cursor = min(v.cursor+page, max(len(v.files)-1, 0))
+		case "\r", "\n", "l", "\x1b[C":
+			v.open_file(v.cursor)
+		case "r":
+			v.load()
+		case "\x1b":
+			return false
+		}
+		return true
+	}
+
+	last := max(len(v.lines)-page, 0)
+	switch k {
+	case "\x1b[A", "k":
+		v.top = max(v.top-1, 0)
+	case "\x1b[B", "j":
+		v.top = min(v.top+1, last)
+	case "\x1b[5~", "b":
+		v.top = max(v.top-page, 0)
+	case "\x1b[6~", " ":
+		v.top = min(v.top+page, last)
+	case "g":
+		v.top = 0
+	case "G":
+		v.top = last
+	case "n":
+		for _, h := range v.hunks {
+			if h > v.top {
+				v.top = min(h, last)
+				break
+			}
+		}
+	case "N", "p":
+		for i := len(v.hunks) - 1; i >= 0; i-- {
+			if v.hunks[i] < v.top {
+				v.top = v.hunks[i]
+				break
+			}
+		}
+	case "]":
+		v.open_file(min(v.cursor+1, len(v.files)-1))
+	case "[":
+		v.open_file(max(v.cursor-1, 0))
+	case "\x1b", "h", "\x1b[D":
+		v.open = false
+	}
+	return true
+}
+
+// current_hunk returns the 1-based hunk at the top of the view, 0 above the first.
+func (v *diffView) current_hunk() int {
+	n := 0
+	for i, h := range v.hunks {
+		if h <= v.top {
+			n = i + 1
+		}
+	}
+	return n
+}
+
+func (v *diffView) draw() {
+	tw, th := termSize()
+	var lines []string
+	if v.open {
+		lines = v.render_diff(tw, th)
+	} else {
+		lines = v.render_files(tw, th)
+	}
+	fmt.Print("\033[2J\033[H\033[?25l")
+	fmt.Print(strings.Join(lines, "\r\n"))
+}
+
+// render_files renders the header, the changed files and the key hints.
+func (v *diffView) render_files(tw, th int) []string {
+	t := theme.Current()
+	header := ansiCyan + "Diff" + ansiReset + " " + pickBold + filepath.Base(v.path) + ansiReset
+	if v.base != "" {
+		header += ansiDim + " vs " + v.base + ansiReset
+	}
+	if len(v.merge_base) >= 7 {
+		header += ansiDim + " (" + v.merge_base[:7] + ")" + ansiReset
+	}
+	lines := []string{" " + header}
+
+	if v.err != "" {
+		lines = append(lines, "", " "+theme.FG(t.Error)+v.err+ansiReset)
+	} else if len(v.files) == 0 {
+		lines = append(lines, "", " "+pickDim+"No changes against "+v.base+ansiReset)
+	} else {
+		added, deleted := 0, 0
+		for _, f := range v.files {
+			added += f.Added
+			deleted += f.Deleted
+		}
+		lines = append(lines, " "+pickDim+plural_files(len(v.files))+ansiReset+"  "+
+			theme.FG(t.Running)+fmt.Sprintf("+%d", added)+ansiReset+" "+
+			theme.FG(t.Stopped)+fmt.Sprintf("−%d", deleted)+ansiReset, "")
+
+		rows := max(th-5, 1)
+		if v.cursor < v.list_top {
+			v.list_top = v.cursor
+		} else if v.cursor >= v.list_top+rows {
+			v.list_top = v.cursor - rows + 1
+		}
+		for i := v.list_top; i < len(v.files) && i < v.list_top+rows; i++ {
+			lines = append(lines, v.file_row(v.files[i], i == v.cursor, tw))
+		}
+	}
+
+	for len(lines) < th-1 {
+		lines = append(lines, "")
+	}
+	return append(lines, " "+ansiDim+"↑↓ select  Enter diff  r refresh  q quit"+ansiReset)
+}
+
+// file_row renders one changed file: status, line counts and path.
+func (v *diffView) file_row(f worktree.FileChange, selected bool, tw int) string {
+	t := theme.Current()
+	color := map[string]string{"M": t.Hint, "A": t.Running, "D": t.Stopped, "R": t.Focus, "C": t.Focus}[f.Status]
+	if color == "" {
+		color = t.DimText
+	}
+	prefix := "  "
+	if selected {
+		prefix = ansiCyan + "▸ " + ansiReset
+	}
+
+	counts := "bin"
+	if !f.Binary {
+		counts = fmt.Sprintf("+%d −%d", f.Added, f.Deleted)
+	}
+	name := f.Path
+	if f.OldPath != "" {
+		name = f.OldPath + " → " + f.Path
+	}
+	room := tw - 2 - 2 - 2 - 12
+	if r := []rune(name); room > 4 && len(r) > room {
+		name = "…" + string(r[len(r)-room+1:])
+	}
+	if selected {
+		name = pickBold + name + ansiReset
+	}
+	return " " + prefix + theme.FG(color) + f.Status + ansiReset + " " +
+		ansiDim + fmt.Sprintf("%-11s", counts) + ansiReset + " " + name
+}
+
+// render_diff renders the open file's diff from v.top, with the hunk position
+// in the header.
+func (v *diffView) render_diff(tw, th int) []string {
+	f := v.files[v.cursor]
+	header := fmt.Sprintf(" %s%s%s %s(%d/%d)", pickBold, f.Path, ansiReset, ansiDim, v.cursor+1, len(v.files))
+	if len(v.hunks) > 0 {
+		header += fmt.Sprintf("  hunk %d/%d", v.current_hunk(), len(v.hunks))
+	}
+	lines := []string{header + ansiReset}
+
+	rows := max(th-2, 1)
+	lang := filepath.Ext(f.Path)
+	for i := v.top; i < len(v.lines) && i < v.top+rows; i++ {
+		lines = append(lines, highlight_diff_line(truncate_cells(expand_tabs(v.lines[i]), tw-1), lang))
+	}
+	for len(lines) < th-1 {
+		lines = append(lines, "")
+	}
+	return append(lines, " "+ansiDim+"↑↓ scroll  n/N hunk  ]/[ file  Esc files  q quit"+ansiReset)
+}
+
+func plural_files(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
+
+func expand_tabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
+
+// truncate_cells cuts s to at most n runes.
+func truncate_cells(s string, n int) string {
+	if r := []rune(s); n > 0 && len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+// diffKeywords are highlighted in code lines, across the languages worktrees
+// usually hold (Go, JS/TS, Python, shell).
+var diffKeywords = map[string]bool{
+	"func": true, "return": true, "if": true, "else": true, "for": true, "range": true, "switch": true,
+	"case": true, "default": true, "break": true, "continue": true, "const": true, "var": true, "let": true,
+	"type": true, "struct": true, "interface": true, "package": true, "import": true, "export": true,
+	"from": true, "class": true, "extends": true, "new": true, "function": true, "async": true, "await": true,
+	"try": true, "catch": true, "finally": true, "throw": true, "def": true, "elif": true, "in": true,
+	"nil": true, "null": true, "undefined": true, "true": true, "false": true, "None": true, "True": true,
+	"False": true, "defer": true, "go": true, "chan": true, "map": true, "while": true, "do": true,
+	"then": true, "fi": true, "esac": true, "enum": true, "static": true, "public": true, "private": true,
+}
+
+// diffCodeExts are the file types whose lines get keyword, string and comment
+// highlighting; other files only get diff colors.
+var diffCodeExts = map[string]string{
+	".go": "//", ".js": "//", ".jsx": "//", ".ts": "//", ".tsx": "//", ".mjs": "//", ".cjs": "//",
+	".java": "//", ".c": "//", ".h": "//", ".cpp": "//", ".rs": "//", ".swift": "//", ".kt": "//",
+	".py": "#", ".rb": "#", ".sh": "#", ".bash": "#", ".zsh": "#", ".yml": "#", ".yaml": "#", ".toml": "#",
+}
+
+// highlight_diff_line colors a unified diff line: file headers dim, hunk
+// headers in the focus color, additions and removals green and red, and
+// keywords, strings and comments in code.
+func highlight_diff_line(line, ext string) string {
+	t := theme.Current()
+	switch {
+	case strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "),
+		strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "),
+		strings.HasPrefix(line, "new file"), strings.HasPrefix(line, "deleted file"),
+		strings.HasPrefix(line, "similarity "), strings.HasPrefix(line, "rename "),
+		strings.HasPrefix(line, `\`):
+		return ansiDim + line + ansiReset
+	case strings.HasPrefix(line, "@@"):
+		if end := strings.Index(line[2:], "@@"); end >= 0 {
+			return ansiCyan + line[:end+4] + ansiReset + pickDim + line[end+4:] + ansiReset
+		}
+		return ansiCyan + line + ansiReset
+	case line == "":
+		return ""
+	}
+
+	base := ""
+	switch line[0] {
+	case '+':
+		base = theme.FG(t.Running)
+	case '-':
+		base = theme.FG(t.Stopped)
+	}
+	return base + line[:1] + highlight_code(line[1:], ext, base) + ansiReset
+}
+
+// highlight_code colors the tokens of one line of code, going back to base
+// after each token.
+func highlight_code(code, ext, base string) string {
+	comment, ok := diffCodeExts[ext]
+	if !ok {
+		return code
+	}
+	t := theme.Current()
+	keyword, str, dim := theme.Bold(t.Focus), theme.FG(t.Hint), ansiDim
+
+	var b strings.Builder
+	r := []rune(code)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case strings.HasPrefix(string(r[i:]), comment):
+			b.WriteString(dim + string(r[i:]) + ansiReset + base)
+			return b.String()
+		case c == '"' || c == '\'' || c == '`':
+			j := i + 1
+			for j < len(r) && r[j] != c {
+				if r[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(r))
+			b.WriteString(str + string(r[i:j]) + ansiReset + base)
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(r) && (unicode.IsLetter(r[j]) || unicode.IsDigit(r[j]) || r[j] == '_') {
+				j++
+			}
+			word := string(r[i:j])
+			if diffKeywords[word] {
+				b.WriteString(ansiReset + keyword + word + ansiReset + base)
+			} else {
+				b.WriteString(word)
+			}
+			i = j
+		default:
+			b.WriteRune(c)
+			i++
+		}
+	}
+	return b.String()
+}
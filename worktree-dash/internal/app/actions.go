@@ -68,31 +68,14 @@ type MsgActionOutput struct {
 	Err    error
 }
 
+// actions_for_worktree returns the picker actions offered for wt, generated
+// from the action registry.
 func (m *Model) actions_for_worktree(wt worktree.Worktree) []ui.PickerAction {
 	var actions []ui.PickerAction
-	if wt.Type == worktree.TypeLocal {
-		if wt.Running {
-			actions = m.filter_local_running_actions()
-		} else {
-			actions = m.filter_switch_mode(ui.LocalActions)
+	for _, a := range worktree_actions {
+		if a.in_picker(m, wt) {
+			actions = append(actions, a.picker_action(wt))
 		}
-	} else if !wt.ContainerExists {
-		actions = m.filter_switch_mode(ui.LocalActions)
-	} else if wt.HostBuild {
-		if wt.Running {
-			actions = ui.HostBuildRunningActions
-		} else {
-			actions = ui.HostBuildStoppedActions
-		}
-	} else if wt.Running {
-		actions = ui.WorktreeActions
-	} else {
-		actions = ui.StoppedActions
-	}
-
-	// When claude auto-mode is OFF, add "Claude (Auto)" option after "Claude"
-	if !m.claude_auto_mode {
-		actions = insert_claude_auto(actions)
 	}
 	return actions
 }
@@ -111,51 +94,11 @@ func insert_claude_auto(actions []ui.PickerAction) []ui.PickerAction {
 	return actions
 }
 
-// filter_local_running_actions returns LocalRunningActions, excluding
-// "Start service" when all configured services are running, and
-// "Stop service" when no configured services are running.
-func (m *Model) filter_local_running_actions() []ui.PickerAction {
-	has_stopped, has_running := m.service_availability()
-	hide_mode := !m.has_modes()
-	if has_stopped && has_running && !hide_mode {
-		return ui.LocalRunningActions
-	}
-	actions := make([]ui.PickerAction, 0, len(ui.LocalRunningActions))
-	for _, a := range ui.LocalRunningActions {
-		if !has_stopped && a.Label == "Start service" {
-			continue
-		}
-		if !has_running && a.Label == "Stop service" {
-			continue
-		}
-		if hide_mode && a.Key == "m" {
-			continue
-		}
-		actions = append(actions, a)
-	}
-	return actions
-}
-
 // has_modes returns true when the config defines multiple service modes.
 func (m *Model) has_modes() bool {
 	return m.cfg != nil && len(m.cfg.Services.Modes) > 1
 }
 
-// filter_switch_mode removes the "Switch mode" action when no modes are configured.
-func (m *Model) filter_switch_mode(actions []ui.PickerAction) []ui.PickerAction {
-	if m.has_modes() {
-		return actions
-	}
-	filtered := make([]ui.PickerAction, 0, len(actions))
-	for _, a := range actions {
-		if a.Key == "m" {
-			continue
-		}
-		filtered = append(filtered, a)
-	}
-	return filtered
-}
-
 // service_availability checks configured services against running PM2 processes
 // and returns whether any are stopped and whether any are running.
 func (m *Model) service_availability() (has_stopped, has_running bool) {
@@ -13,12 +13,21 @@ import (
 
 // ── actions_for_worktree ─────────────────────────────────────────────────
 
+// action_keys joins the picker keys, in order.
+func action_keys(actions []ui.PickerAction) string {
+	keys := ""
+	for _, a := range actions {
+		keys += a.Key
+	}
+	return keys
+}
+
 func TestActionsForWorktree_DockerRunning(t *testing.T) {
 	m := &Model{claude_auto_mode: true} // auto-mode ON skips insert_claude_auto
 	wt := worktree.Worktree{Type: worktree.TypeDocker, Running: true, ContainerExists: true}
 	got := m.actions_for_worktree(wt)
-	if &got[0] != &ui.WorktreeActions[0] {
-		t.Error("expected WorktreeActions for running docker worktree")
+	if keys := action_keys(got); keys != "bzcgVGZrtPNEOx" {
+		t.Errorf("running docker worktree: got %q, want %q", keys, "bzcgVGZrtPNEOx")
 	}
 }
 
@@ -26,8 +35,8 @@ func TestActionsForWorktree_DockerStopped(t *testing.T) {
 	m := &Model{claude_auto_mode: true}
 	wt := worktree.Worktree{Type: worktree.TypeDocker, Running: false, ContainerExists: true}
 	got := m.actions_for_worktree(wt)
-	if &got[0] != &ui.StoppedActions[0] {
-		t.Error("expected StoppedActions for stopped docker worktree")
+	if keys := action_keys(got); keys != "uzcgVGZPNEOx" {
+		t.Errorf("stopped docker worktree: got %q, want %q", keys, "uzcgVGZPNEOx")
 	}
 }
 
@@ -143,8 +152,8 @@ func TestActionsForWorktree_HostBuildRunning(t *testing.T) {
 	m := &Model{claude_auto_mode: true}
 	wt := worktree.Worktree{Type: worktree.TypeDocker, Running: true, ContainerExists: true, HostBuild: true}
 	got := m.actions_for_worktree(wt)
-	if &got[0] != &ui.HostBuildRunningActions[0] {
-		t.Error("expected HostBuildRunningActions for running host-build worktree")
+	if keys := action_keys(got); keys != "ebzcgVGZrtPNEOx" {
+		t.Errorf("running host-build worktree: got %q, want %q", keys, "ebzcgVGZrtPNEOx")
 	}
 }
 
@@ -152,8 +161,11 @@ func TestActionsForWorktree_HostBuildStopped(t *testing.T) {
 	m := &Model{claude_auto_mode: true}
 	wt := worktree.Worktree{Type: worktree.TypeDocker, Running: false, ContainerExists: true, HostBuild: true}
 	got := m.actions_for_worktree(wt)
-	if &got[0] != &ui.HostBuildStoppedActions[0] {
-		t.Error("expected HostBuildStoppedActions for stopped host-build worktree")
+	if keys := action_keys(got); keys != "uzcgVGZPNEOx" {
+		t.Errorf("stopped host-build worktree: got %q, want %q", keys, "uzcgVGZPNEOx")
+	}
+	if got[0].Label != "Start + Build" {
+		t.Errorf("got[0].Label = %q, want %q", got[0].Label, "Start + Build")
 	}
 }
 
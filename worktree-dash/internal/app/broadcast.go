@@ -0,0 +1,315 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elvisnm/wt/internal/labels"
+	"github.com/elvisnm/wt/internal/terminal"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// broadcastPollInterval is how often shell tabs are checked for the exit marker.
+const broadcastPollInterval = time.Second
+
+// broadcastTimeout is how long a tab has to print its exit marker before
+// the run counts as failed, so a lost marker can't block later broadcasts.
+const broadcastTimeout = 15 * time.Minute
+
+// broadcastTimedOut is the exit of a run whose marker never appeared.
+const broadcastTimedOut = -2
+
+// broadcastRun tracks one broadcast command sent to a worktree's shell tab.
+type broadcastRun struct {
+	token string
+	alias string
+	exit  int // -1 while running
+	sent  time.Time
+}
+
+// MsgBroadcastPolled carries exit codes found in shell tabs, keyed by session ID.
+type MsgBroadcastPolled struct {
+	Exits map[int]int
+}
+
+// broadcast_marker returns the shell snippet appended to a broadcast command.
+// It prints "[wt <token> exit <code>]" once the command finishes; the typed
+// command line itself shows "%d", so only the real output matches. fish
+// keeps the last exit code in $status rather than $?.
+func broadcast_marker(token, shell string) string {
+	status := "$?"
+	if filepath.Base(shell) == "fish" {
+		status = "$status"
+	}
+	return fmt.Sprintf(`printf '\n[wt %s exit %%d]\n' %s`, token, status)
+}
+
+// broadcast_shell returns the shell a worktree's shell tab runs: the one
+// split_session_spec starts, or the last exec argument inside a container.
+func broadcast_shell(spec sessionSpec) string {
+	if spec.cmd == "docker" && len(spec.args) > 0 {
+		return spec.args[len(spec.args)-1]
+	}
+	return spec.cmd
+}
+
+// parse_broadcast_exit finds the exit code printed by broadcast_marker in text.
+func parse_broadcast_exit(text, token string) (int, bool) {
+	re := regexp.MustCompile(`\[wt ` + regexp.QuoteMeta(token) + ` exit (\d+)\]`)
+	found := re.FindAllStringSubmatch(text, -1)
+	if len(found) == 0 {
+		return 0, false
+	}
+	code, err := strconv.Atoi(found[len(found)-1][1])
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// broadcast_status returns the Active Tabs indicator for a session's broadcast.
+func (m Model) broadcast_status(session_id int) string {
+	run, ok := m.broadcasts[session_id]
+	if !ok {
+		return ""
+	}
+	switch run.exit {
+	case -1:
+		return ui.SpinFrames[m.spin_frame%len(ui.SpinFrames)]
+	case 0:
+		return "✓"
+	case broadcastTimedOut:
+		return "✗ timeout"
+	default:
+		return fmt.Sprintf("✗ %d", run.exit)
+	}
+}
+
+// toggle_group_sync turns synchronize-panes on or off for the active split group.
+func (m Model) toggle_group_sync() (tea.Model, tea.Cmd) {
+	g := m.term_mgr.ActiveGroup()
+	if g == nil {
+		return m, nil
+	}
+	on, err := m.term_mgr.ToggleSync(g.ID)
+	if err != nil {
+		return m.show_notification("Broadcast", err.Error())
+	}
+	if on {
+		return m.show_notification("Broadcast", fmt.Sprintf("On — typing goes to all %d panes", g.Count()))
+	}
+	return m.show_notification("Broadcast", "Off")
+}
+
+// broadcast_pending reports whether a broadcast has tabs still running it.
+// Only one runs at a time, so its exit markers are all collected.
+func (m Model) broadcast_pending() bool {
+	for _, run := range m.broadcasts {
+		if run.exit == -1 {
+			return true
+		}
+	}
+	return false
+}
+
+// open_broadcast_picker asks which worktrees should receive a broadcast command.
+func (m Model) open_broadcast_picker() (Model, tea.Cmd) {
+	wt := m.selected_worktree()
+	if wt == nil {
+		return m, nil
+	}
+	if m.broadcast_pending() {
+		return m.show_notification("Broadcast", "The last broadcast is still running")
+	}
+	listed := "worktree(s)"
+	if m.list_filtered() {
+		listed = "listed worktree(s)"
+	}
+	var actions []ui.PickerAction
+	// Marks come first, so the cursor starts on them
+	if marked := m.marked_worktrees(); len(marked) > 0 {
+		aliases := make([]string, len(marked))
+		for i, w := range marked {
+			aliases[i] = w.Alias
+		}
+		actions = append(actions, ui.PickerAction{Key: "m", Label: fmt.Sprintf("Marked (%d)", len(marked)), Desc: strings.Join(aliases, ", ")})
+	}
+	actions = append(actions,
+		ui.PickerAction{Key: "s", Label: "Selected", Desc: wt.Alias},
+		ui.PickerAction{Key: "r", Label: "Running", Desc: fmt.Sprintf("%d %s", len(m.broadcast_targets("r")), listed)},
+		ui.PickerAction{Key: "a", Label: "All", Desc: fmt.Sprintf("%d %s", len(m.broadcast_targets("a")), listed)},
+	)
+	return m.open_panel_picker("Broadcast", actions, pickerBroadcast)
+}
+
+// broadcast_targets returns the worktrees a broadcast picker key stands for.
+// Running and All cover the worktrees the list filter leaves visible.
+func (m Model) broadcast_targets(key string) []worktree.Worktree {
+	var targets []worktree.Worktree
+	switch key {
+	case "m":
+		targets = m.marked_worktrees()
+	case "s":
+		if wt := m.selected_worktree(); wt != nil {
+			targets = append(targets, *wt)
+		}
+	case "r":
+		for _, w := range m.visible_worktrees() {
+			if w.Running {
+				targets = append(targets, w)
+			}
+		}
+	case "a":
+		targets = m.visible_worktrees()
+	}
+	return targets
+}
+
+// execute_broadcast_action resolves the target worktrees and prompts for the command.
+func (m Model) execute_broadcast_action(action ui.PickerAction) (Model, tea.Cmd) {
+	targets := m.broadcast_targets(action.Key)
+	if len(targets) == 0 {
+		return m.show_notification("Broadcast", "No matching worktrees")
+	}
+
+	prompt := fmt.Sprintf("Run in %d worktree(s):", len(targets))
+	return m.open_panel_input("Broadcast", prompt, func(mdl *Model, val string) (Model, tea.Cmd) {
+		command := strings.TrimSpace(val)
+		if command == "" {
+			return *mdl, nil
+		}
+		return mdl.run_broadcast(targets, command)
+	})
+}
+
+// run_broadcast types command into each target's shell tab, opening tabs as needed,
+// and starts polling the tabs for the exit marker.
+func (m Model) run_broadcast(targets []worktree.Worktree, command string) (Model, tea.Cmd) {
+	if m.broadcast_pending() {
+		return m.show_notification("Broadcast", "The last broadcast is still running")
+	}
+	token := strconv.FormatInt(time.Now().UnixNano()%0xffffff, 16)
+	w, h := m.right_pane_dimensions()
+
+	m.broadcasts = make(map[int]*broadcastRun)
+	var failed []string
+	for _, wt := range targets {
+		label := labels.Tab(labels.Shell, wt.Alias)
+		var s *terminal.Session
+		for _, sess := range m.term_mgr.Sessions() {
+			if sess.Label == label && sess.IsAlive() {
+				s = sess
+				break
+			}
+		}
+		spec, _ := m.split_session_spec("b", wt.Alias, wt.Path)
+		if s == nil {
+			var err error
+			s, err = m.term_mgr.OpenNew(label, spec.cmd, spec.args, w, h, spec.dir)
+			if err != nil {
+				failed = append(failed, wt.Alias)
+				continue
+			}
+			s.SetWorktree(wt.Alias, wt.Path)
+		}
+		s.SendLine(command + "; " + broadcast_marker(token, broadcast_shell(spec)))
+		m.broadcasts[s.ID] = &broadcastRun{token: token, alias: wt.Alias, exit: -1, sent: time.Now()}
+	}
+
+	m.sync_tab_cursor_from_active()
+	m.activity = fmt.Sprintf("Broadcast to %d tab(s): %s", len(m.broadcasts), command)
+	if len(failed) > 0 {
+		m.activity += fmt.Sprintf(" (failed to open: %s)", strings.Join(failed, ", "))
+	}
+	return m, tea.Batch(tick_after(broadcastPollInterval, "broadcast"), tick_after(80*time.Millisecond, "spin"))
+}
+
+// cmd_poll_broadcasts captures each pending shell tab and looks for its exit marker.
+func (m Model) cmd_poll_broadcasts() tea.Cmd {
+	pending := make(map[int]string)
+	for id, run := range m.broadcasts {
+		if run.exit == -1 {
+			pending[id] = run.token
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	sessions := m.term_mgr.Sessions()
+	return func() tea.Msg {
+		exits := make(map[int]int)
+		for _, s := range sessions {
+			token, ok := pending[s.ID]
+			if !ok {
+				continue
+			}
+			text, err := s.CaptureScrollback()
+			if err != nil {
+				continue
+			}
+			if code, ok := parse_broadcast_exit(text, token); ok {
+				exits[s.ID] = code
+			}
+		}
+		return MsgBroadcastPolled{Exits: exits}
+	}
+}
+
+// expire_broadcasts fails the runs whose marker hasn't shown up within
+// broadcastTimeout of being sent.
+func (m Model) expire_broadcasts(now time.Time) {
+	for _, run := range m.broadcasts {
+		if run.exit == -1 && now.Sub(run.sent) > broadcastTimeout {
+			run.exit = broadcastTimedOut
+		}
+	}
+}
+
+// handle_broadcast_polled records exit codes and summarises once every tab has finished.
+func (m Model) handle_broadcast_polled(msg MsgBroadcastPolled) (Model, tea.Cmd) {
+	live := make(map[int]bool)
+	for _, s := range m.term_mgr.Sessions() {
+		live[s.ID] = true
+	}
+	for id, run := range m.broadcasts {
+		if code, ok := msg.Exits[id]; ok {
+			run.exit = code
+		}
+		if !live[id] {
+			delete(m.broadcasts, id)
+		}
+	}
+	m.expire_broadcasts(time.Now())
+
+	var ok_count int
+	var failed []string
+	for _, run := range m.broadcasts {
+		switch run.exit {
+		case -1:
+			return m, tick_after(broadcastPollInterval, "broadcast")
+		case 0:
+			ok_count++
+		case broadcastTimedOut:
+			failed = append(failed, run.alias+" (timed out)")
+		default:
+			failed = append(failed, fmt.Sprintf("%s (%d)", run.alias, run.exit))
+		}
+	}
+	if len(m.broadcasts) == 0 {
+		return m, nil
+	}
+
+	m.activity = ""
+	summary := fmt.Sprintf("%d succeeded", ok_count)
+	if len(failed) > 0 {
+		summary += fmt.Sprintf(", %d failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return m.show_notification("Broadcast", summary)
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elvisnm/wt/internal/terminal"
+)
+
+func TestBroadcastMarker_ShellOutput(t *testing.T) {
+	out, err := exec.Command("sh", "-c", "(exit 3); "+broadcast_marker("ab12", "/bin/sh")).Output()
+	if err != nil {
+		t.Fatalf("sh: %v", err)
+	}
+	code, ok := parse_broadcast_exit(string(out), "ab12")
+	if !ok || code != 3 {
+		t.Errorf("parse_broadcast_exit = %d, %v; want 3, true", code, ok)
+	}
+
+	if got := broadcast_marker("ab12", "/usr/local/bin/fish"); !strings.HasSuffix(got, " $status") {
+		t.Errorf("fish marker = %q, want it to read $status", got)
+	}
+	if got := broadcast_shell(sessionSpec{cmd: "docker", args: []string{"exec", "-it", "app-api", "bash"}}); got != "bash" {
+		t.Errorf("container shell = %q, want bash", got)
+	}
+}
+
+func TestParseBroadcastExit(t *testing.T) {
+	// The echoed command line contains the marker template, not a code
+	screen := "$ make test; printf '\\n[wt ab12 exit %d]\\n' $?\nok\n[wt ff00 exit 1]\n"
+	if _, ok := parse_broadcast_exit(screen, "ab12"); ok {
+		t.Error("command echo or another token should not match")
+	}
+
+	screen += "\n[wt ab12 exit 0]\n"
+	code, ok := parse_broadcast_exit(screen, "ab12")
+	if !ok || code != 0 {
+		t.Errorf("parse_broadcast_exit = %d, %v; want 0, true", code, ok)
+	}
+}
+
+func TestBroadcastPending(t *testing.T) {
+	m := list_model()
+	m.term_mgr = terminal.NewManager()
+	m.broadcasts = map[int]*broadcastRun{7: {token: "ab12", alias: "api", exit: -1}}
+
+	m, _ = m.open_broadcast_picker()
+	if m.picker_open || !m.notify_open {
+		t.Error("a running broadcast should keep another from starting")
+	}
+	m, _ = m.run_broadcast(m.worktrees[:1], "make test")
+	if len(m.broadcasts) != 1 || m.broadcasts[7].token != "ab12" {
+		t.Errorf("the running broadcast should still be tracked: %+v", m.broadcasts)
+	}
+
+	m.broadcasts[7].exit = 0
+	m.notify_open = false
+	if m, _ = m.open_broadcast_picker(); !m.picker_open {
+		t.Error("once it's done, a new broadcast can start")
+	}
+}
+
+func TestBroadcastTimeout(t *testing.T) {
+	m := list_model()
+	now := time.Now()
+	m.broadcasts = map[int]*broadcastRun{
+		7: {token: "ab12", alias: "api", exit: -1, sent: now.Add(-broadcastTimeout - time.Second)},
+		8: {token: "ab12", alias: "web", exit: -1, sent: now.Add(-time.Minute)},
+	}
+
+	m.expire_broadcasts(now)
+	if m.broadcasts[7].exit != broadcastTimedOut || m.broadcasts[8].exit != -1 {
+		t.Errorf("exits = %d, %d; want only the old run timed out", m.broadcasts[7].exit, m.broadcasts[8].exit)
+	}
+	if got := m.broadcast_status(7); got != "✗ timeout" {
+		t.Errorf("status = %q", got)
+	}
+
+	m.broadcasts[8].exit = 0
+	if m.broadcast_pending() {
+		t.Error("a timed-out run should not block the next broadcast")
+	}
+}
+
+func TestBroadcastTargets(t *testing.T) {
+	m := list_model()
+	m.term_mgr = terminal.NewManager()
+
+	m, _ = m.open_broadcast_picker()
+	if got := m.picker_actions[0].Key; got != "s" {
+		t.Errorf("with nothing marked the picker should start on Selected, got %q", got)
+	}
+
+	m.picker_open = false
+	m.marked = map[string]bool{"fix-crash": true, "feat-pay": true}
+	m, _ = m.open_broadcast_picker()
+	if a := m.picker_actions[m.picker_cursor]; a.Key != "m" || a.Label != "Marked (2)" {
+		t.Errorf("marks should be the default target, got %+v", a)
+	}
+	if got := row_names(m.broadcast_targets("m")); got != "crash pay " {
+		t.Errorf("marked targets = %q", got)
+	}
+
+	// Running and All follow the list filter
+	m.list_query = "feat"
+	if got := row_names(m.broadcast_targets("r")); got != "login " {
+		t.Errorf("running targets = %q, want only the listed one", got)
+	}
+	if got := row_names(m.broadcast_targets("a")); got != "login pay " {
+		t.Errorf("all targets = %q, want the listed ones", got)
+	}
+}
@@ -0,0 +1,580 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elvisnm/wt/internal/aws"
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/esbuild"
+	"github.com/elvisnm/wt/internal/labels"
+	"github.com/elvisnm/wt/internal/pm2"
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bulkParallelism bounds how many worktrees a bulk action works on at once.
+const bulkParallelism = 4
+
+// bulkOp is an action that can run on every marked worktree.
+type bulkOp struct {
+	key, id, label, desc string
+	status               string // shown on a worktree while its job runs
+	verb                 string // past tense for the summary
+	skip                 func(wt worktree.Worktree) string
+}
+
+// bulk_ops are the bulk actions by picker key. The keys match the single
+// worktree quick keys where there is one.
+var bulk_ops = []bulkOp{
+	{key: "t", id: "stop", label: "Stop", desc: "Stop containers and dev servers", status: "stopping...", verb: "Stopped", skip: skip_unless_running},
+	{key: "r", id: "restart", label: "Restart", desc: "Restart containers and dev servers", status: "restarting...", verb: "Restarted", skip: skip_unless_running},
+	{key: "g", id: "pull", label: labels.Pull, desc: "Pull latest changes", status: "pulling...", verb: "Pulled"},
+	{key: "b", id: "sync", label: "Sync with base", desc: "Rebase onto the base ref", status: "syncing...", verb: "Synced", skip: skip_unless_syncable},
+	{key: "s", id: "seed", label: labels.DBSeed, desc: "Seed each database", status: "seeding...", verb: "Seeded", skip: skip_unless_docker_running},
+	{key: "x", id: "remove", label: labels.Remove, desc: "Fails if dirty", status: "removing...", verb: "Removed"},
+	{key: "f", id: "force_remove", label: "Force remove", desc: "Even if dirty", status: "removing...", verb: "Removed"},
+	{key: "d", id: "remove_drop", label: "Remove + drop DB", desc: "Fails if dirty, then drops each own DB", status: "removing...", verb: "Removed"},
+}
+
+func skip_unless_running(wt worktree.Worktree) string {
+	if !wt.Running {
+		return "not running"
+	}
+	return ""
+}
+
+func skip_unless_docker_running(wt worktree.Worktree) string {
+	if wt.Type != worktree.TypeDocker || !wt.Running {
+		return "needs a running Docker worktree"
+	}
+	return ""
+}
+
+// bulkRun tracks a bulk action in progress. Jobs are started bulkParallelism
+// at a time; each finished job starts the next queued one.
+type bulkRun struct {
+	op       bulkOp
+	queue    []worktree.Worktree          // not started yet
+	jobs     map[string]worktree.Worktree // started, by name
+	progress map[string]string            // per-worktree state shown in the panel
+	results  []bulkResult                 // failed and skipped worktrees
+	total    int                          // worktrees with a job
+	done     int                          // finished jobs
+	aws_once sync.Once                    // refreshes AWS credentials once per restart
+
+	// Sync only
+	fetch_once sync.Once    // fetches the base ref once for all worktrees
+	base       string       // the base ref synced with, set by the fetch
+	fetch_err  string       // why the fetch failed; the ref is used as last fetched
+	synced     []bulkResult // updated or already up to date, with what was done
+	state      bool         // conflicts were recorded or cleared
+}
+
+// bulkResult is a worktree the bulk action failed on or skipped, and why.
+// A sync that stopped on conflicts is a failure with conflict set.
+type bulkResult struct {
+	name, alias, reason string
+	skipped, conflict   bool
+}
+
+// MsgBulkItemDone reports that one worktree's bulk job finished.
+type MsgBulkItemDone struct {
+	Name   string
+	Output string
+	Err    error
+}
+
+// bulk_job runs one worktree's part of a bulk action, off the UI goroutine.
+// Tests replace it.
+var bulk_job = run_bulk_job
+
+// ── Marks ───────────────────────────────────────────────────────────────
+
+// toggle_mark marks or unmarks the worktree under the cursor and moves down.
+func (m Model) toggle_mark() (tea.Model, tea.Cmd) {
+	wt := m.selected_worktree()
+	if wt == nil {
+		return m, nil
+	}
+	marked := make(map[string]bool, len(m.marked)+1)
+	for name := range m.marked {
+		marked[name] = true
+	}
+	if marked[wt.Name] {
+		delete(marked, wt.Name)
+	} else {
+		marked[wt.Name] = true
+	}
+	m.marked = marked
+	if m.cursor >= len(m.visible_worktrees())-1 {
+		return m, nil
+	}
+	m.cursor++
+	m.details_scroll = 0
+	m.close_preview()
+	m.services = nil
+	m.service_cursor = 0
+	return m, m.refresh_services()
+}
+
+// mark_all marks every listed worktree, or unmarks them when all already are.
+// Marks on worktrees hidden by the filter are kept.
+func (m Model) mark_all() (tea.Model, tea.Cmd) {
+	rows := m.visible_worktrees()
+	all := len(rows) > 0
+	for _, wt := range rows {
+		all = all && m.marked[wt.Name]
+	}
+	marked := make(map[string]bool, len(m.marked)+len(rows))
+	for name := range m.marked {
+		marked[name] = true
+	}
+	for _, wt := range rows {
+		if all {
+			delete(marked, wt.Name)
+		} else {
+			marked[wt.Name] = true
+		}
+	}
+	m.marked = marked
+	return m, nil
+}
+
+// marked_worktrees returns the marked worktrees in panel order, followed by
+// marked worktrees the filter hides.
+func (m Model) marked_worktrees() []worktree.Worktree {
+	var result []worktree.Worktree
+	listed := map[string]bool{}
+	for _, wt := range m.visible_worktrees() {
+		listed[wt.Name] = true
+		if m.marked[wt.Name] {
+			result = append(result, wt)
+		}
+	}
+	for _, wt := range worktree.SortWorktrees(m.worktrees) {
+		if m.marked[wt.Name] && !listed[wt.Name] {
+			result = append(result, wt)
+		}
+	}
+	return result
+}
+
+// prune_marks drops marks on worktrees that no longer exist.
+func (m *Model) prune_marks() {
+	if len(m.marked) == 0 {
+		return
+	}
+	exists := make(map[string]bool, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		exists[wt.Name] = true
+	}
+	marked := make(map[string]bool, len(m.marked))
+	for name := range m.marked {
+		if exists[name] {
+			marked[name] = true
+		}
+	}
+	m.marked = marked
+}
+
+// bulk_progress returns the per-worktree state of the running bulk action.
+func (m Model) bulk_progress() map[string]string {
+	if m.bulk == nil {
+		return nil
+	}
+	return m.bulk.progress
+}
+
+// ── Menu ────────────────────────────────────────────────────────────────
+
+// open_bulk_menu offers the bulk actions for the marked worktrees.
+func (m Model) open_bulk_menu() (Model, tea.Cmd) {
+	if m.bulk != nil {
+		return m.show_notification("Bulk", fmt.Sprintf("%s is still running", m.bulk.op.label))
+	}
+	wts := m.marked_worktrees()
+	actions := make([]ui.PickerAction, len(bulk_ops))
+	for i, op := range bulk_ops {
+		desc := op.desc
+		if op.id == "sync" && m.cfg != nil && m.cfg.SyncMerges() {
+			desc = "Merge the base ref in"
+		}
+		if n := len(bulk_targets(op, wts)); n < len(wts) {
+			desc = fmt.Sprintf("%d of %d, %s", n, len(wts), strings.ToLower(desc[:1])+desc[1:])
+		}
+		actions[i] = ui.PickerAction{Key: op.key, Label: op.label, Desc: desc}
+	}
+	return m.open_panel_picker("Bulk", actions, pickerBulk)
+}
+
+// bulk_targets returns the worktrees op applies to.
+func bulk_targets(op bulkOp, wts []worktree.Worktree) []worktree.Worktree {
+	var result []worktree.Worktree
+	for _, wt := range wts {
+		if op.skip == nil || op.skip(wt) == "" {
+			result = append(result, wt)
+		}
+	}
+	return result
+}
+
+// execute_bulk_action confirms and starts the chosen bulk action.
+func (m Model) execute_bulk_action(action ui.PickerAction) (Model, tea.Cmd) {
+	for _, op := range bulk_ops {
+		if op.key != action.Key {
+			continue
+		}
+		wts := m.marked_worktrees()
+		n := len(bulk_targets(op, wts))
+		if len(wts) == 0 {
+			return m, nil
+		}
+		if n == 0 {
+			return m.show_notification("Bulk "+op.label, fmt.Sprintf("None of the %d marked worktrees qualify (%s)", len(wts), op.skip(wts[0])))
+		}
+		prompt := fmt.Sprintf("%s %d worktrees?", op.label, n)
+		if skipped := len(wts) - n; skipped > 0 {
+			prompt = fmt.Sprintf("%s %d worktrees? %d skipped", op.label, n, skipped)
+		}
+		return m.open_panel_confirm("Bulk "+op.label, prompt,
+			func(mdl *Model) (Model, tea.Cmd) { return mdl.start_bulk(op, wts) })
+	}
+	return m, nil
+}
+
+// ── Running ─────────────────────────────────────────────────────────────
+
+// start_bulk queues op for every worktree it applies to and starts the first
+// bulkParallelism jobs. Tabs are closed here, on the UI goroutine.
+func (m Model) start_bulk(op bulkOp, wts []worktree.Worktree) (Model, tea.Cmd) {
+	run := &bulkRun{
+		op:       op,
+		jobs:     map[string]worktree.Worktree{},
+		progress: map[string]string{},
+	}
+	if m.actions_pending == nil {
+		m.actions_pending = make(map[string]bool)
+	}
+	for _, wt := range wts {
+		if op.skip != nil {
+			if reason := op.skip(wt); reason != "" {
+				run.results = append(run.results, bulkResult{name: wt.Name, alias: wt.Alias, reason: reason, skipped: true})
+				continue
+			}
+		}
+		switch op.id {
+		case "stop":
+			m.close_dev_tabs(wt.Alias)
+			m.close_worktree_logs(wt)
+			m.term_mgr.CloseByLabel(labels.Tab(labels.Build, wt.Alias))
+			m.close_set_tabs(wt)
+		case "restart":
+			if wt.HostBuild {
+				m.term_mgr.CloseByLabel(labels.Tab(labels.Build, wt.Alias))
+			}
+		case "remove", "force_remove", "remove_drop":
+			m.close_worktree_tabs(wt)
+			m.forget_push(wt)
+		}
+		run.queue = append(run.queue, wt)
+		run.progress[wt.Name] = "queued"
+		m.actions_pending[wt.Name] = true
+	}
+	run.total = len(run.queue)
+	m.close_preview()
+	if m.term_mgr.Count() == 0 && m.focus == PanelTerminal {
+		m.focus = PanelWorktrees
+	}
+	m.bulk = run
+	if run.total == 0 {
+		return m.finish_bulk()
+	}
+
+	cmds := []tea.Cmd{tick_after(80*time.Millisecond, "spin")}
+	for i := 0; i < bulkParallelism; i++ {
+		if cmd := m.next_bulk_job(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	m.activity = m.bulk_activity()
+	m.spin_frame = 0
+	return m, tea.Batch(cmds...)
+}
+
+// next_bulk_job starts the next queued worktree, or returns nil when none are left.
+func (m *Model) next_bulk_job() tea.Cmd {
+	run := m.bulk
+	if len(run.queue) == 0 {
+		return nil
+	}
+	wt := run.queue[0]
+	run.queue = run.queue[1:]
+	run.jobs[wt.Name] = wt
+	run.progress[wt.Name] = strings.TrimSuffix(run.op.status, "...")
+
+	op, repo_root, cfg, base_refs := run.op.id, m.repo_root, m.cfg, m.base_refs()
+	return func() tea.Msg {
+		if op == "sync" {
+			run.fetch_once.Do(func() {
+				var err error
+				if run.base, err = sync_fetch(repo_root, base_refs); err != nil {
+					debug_log("[bulk] fetching %s failed: %v", run.base, err)
+					run.fetch_err = err.Error()
+				}
+			})
+		}
+		if op == "restart" && cfg != nil && cfg.FeatureEnabled("awsCredentials") {
+			run.aws_once.Do(func() {
+				if err := aws.Refresh(cfg.AwsSsoProfile()); err != nil {
+					debug_log("[bulk] AWS refresh failed: %v", err)
+				}
+			})
+		}
+		out, err := bulk_job(op, wt, repo_root, cfg)
+		return MsgBulkItemDone{Name: wt.Name, Output: out, Err: err}
+	}
+}
+
+// handle_bulk_item_done records one finished job and starts the next.
+// Restarted local dev servers and host builds are reopened here.
+func (m Model) handle_bulk_item_done(msg MsgBulkItemDone) (Model, tea.Cmd) {
+	run := m.bulk
+	if run == nil {
+		return m, nil
+	}
+	wt, ok := run.jobs[msg.Name]
+	if !ok {
+		return m, nil
+	}
+	delete(run.jobs, msg.Name)
+	delete(m.actions_pending, msg.Name)
+	run.done++
+
+	var cmds []tea.Cmd
+	if run.op.id == "sync" {
+		m.record_sync(run, wt, msg)
+	} else if msg.Err != nil {
+		reason := msg.Err.Error()
+		if msg.Output != "" {
+			reason = last_line(msg.Output)
+		}
+		debug_log("[bulk] %s %s failed: %v\n%s", run.op.id, wt.Name, msg.Err, msg.Output)
+		run.results = append(run.results, bulkResult{name: wt.Name, alias: wt.Alias, reason: reason})
+		run.progress[wt.Name] = "failed"
+	} else {
+		run.progress[wt.Name] = "done"
+		if run.op.id == "restart" {
+			var cmd tea.Cmd
+			switch {
+			case wt.Type == worktree.TypeLocal:
+				m, cmd = m.start_dev_server(wt)
+			case wt.HostBuild:
+				m, cmd = m.open_esbuild_watch(wt)
+			}
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	if run.done == run.total {
+		mdl, cmd := m.finish_bulk()
+		return mdl, tea.Batch(append(cmds, cmd)...)
+	}
+	cmds = append(cmds, m.next_bulk_job())
+	m.activity = m.bulk_activity()
+	return m, tea.Batch(cmds...)
+}
+
+// bulk_activity describes the progress of the running bulk action.
+func (m Model) bulk_activity() string {
+	run := m.bulk
+	failed := 0
+	for _, r := range run.results {
+		if !r.skipped {
+			failed++
+		}
+	}
+	s := fmt.Sprintf("Bulk %s %d/%d", strings.ToLower(run.op.label), run.done, run.total)
+	if failed > 0 {
+		s += fmt.Sprintf(", %d failed", failed)
+	}
+	return s
+}
+
+// finish_bulk ends the bulk action: worktrees it succeeded on are unmarked,
+// failed and skipped ones stay marked for a retry. Failures and skips are
+// listed in a picker; selecting one moves the cursor to that worktree.
+func (m Model) finish_bulk() (Model, tea.Cmd) {
+	run := m.bulk
+	m.bulk = nil
+	m.activity = ""
+
+	if run.state {
+		if err := state.Save(m.repo_root, m.repo_state); err != nil {
+			debug_log("[bulk] saving sync conflicts failed: %v", err)
+		}
+		m.apply_repo_state()
+	}
+
+	kept := map[string]bool{}
+	failed := 0
+	for _, r := range run.results {
+		kept[r.name] = true
+		if !r.skipped {
+			failed++
+		}
+	}
+	marked := make(map[string]bool, len(kept))
+	for name := range m.marked {
+		if kept[name] {
+			marked[name] = true
+		}
+	}
+	m.marked = marked
+
+	refresh := tea.Batch(m.cmd_discover(), m.refresh_services())
+	if run.op.id == "sync" {
+		mdl, cmd := m.open_sync_summary(run)
+		return mdl, tea.Batch(refresh, m.cmd_refresh_synced(run), cmd)
+	}
+	succeeded := run.total - failed
+	if len(run.results) == 0 {
+		mdl, cmd := m.show_notification("Bulk "+run.op.label, fmt.Sprintf("%s %d worktrees", run.op.verb, succeeded))
+		return mdl, tea.Batch(refresh, cmd)
+	}
+
+	m.bulk_summary = fmt.Sprintf("%s: %d ok, %d failed, %d skipped", run.op.label, succeeded, failed, len(run.results)-failed)
+	m.bulk_results = run.results
+	actions := make([]ui.PickerAction, len(run.results))
+	for i, r := range run.results {
+		desc := r.reason
+		if r.skipped {
+			desc = "skipped: " + desc
+		}
+		alias := r.alias
+		if alias == "" {
+			alias = r.name
+		}
+		actions[i] = ui.PickerAction{Key: fmt.Sprintf("%d", i+1), Label: alias, Desc: desc}
+	}
+	mdl, cmd := m.open_panel_picker("Bulk", actions, pickerBulkResult)
+	return mdl, tea.Batch(refresh, cmd)
+}
+
+// execute_bulk_result moves the cursor to a failed or skipped worktree.
+func (m Model) execute_bulk_result(action ui.PickerAction) (Model, tea.Cmd) {
+	idx := m.picker_cursor
+	if idx < 0 || idx >= len(m.bulk_results) {
+		return m, nil
+	}
+	return m.focus_worktree(m.bulk_results[idx].name, "Bulk")
+}
+
+// run_bulk_job performs op on one worktree and returns its output.
+func run_bulk_job(op string, wt worktree.Worktree, repo_root string, cfg *config.Config) (string, error) {
+	scripts := flow_scripts_dir(repo_root, cfg)
+	switch op {
+	case "stop":
+		if len(wt.Set) > 0 {
+			if out, err := stop_set_members(wt, cfg); err != nil {
+				return out, err
+			}
+		}
+		if wt.Type == worktree.TypeLocal {
+			return stop_local_services(wt, cfg)
+		}
+		return run_docker("stop", wt.Container)
+	case "restart":
+		if wt.Type == worktree.TypeLocal {
+			// The dev server is started again once the job reports back
+			kill_local_dev_processes(wt.Path)
+			if wt.IsolatedPM2 {
+				pm2.Kill(wt.PM2Home())
+			}
+			return "", nil
+		}
+		return run_docker("restart", wt.Container)
+	case "pull":
+		return run_host_cmd_env_dir(wt.Path, nil, "node", filepath.Join(scripts, "dc-pull.js"), "--repo", repo_root, "--worktree", wt.Path)
+	case "seed":
+		return run_host_cmd_env_dir(repo_root, nil, "node", filepath.Join(scripts, "dc-seed.js"), wt.Name)
+	case "sync":
+		var base_refs []string
+		if cfg != nil {
+			base_refs = cfg.Repo.BaseRefs
+		}
+		base := short_base_ref(worktree.ResolveBaseRef(repo_root, base_refs))
+		if base == "" {
+			return "", fmt.Errorf("no base branch found")
+		}
+		args := []string{filepath.Join(scripts, "dc-pull.js"), "--repo", repo_root, "--worktree", wt.Path, "--onto", base}
+		if cfg != nil && cfg.SyncMerges() {
+			args = append(args, "--merge")
+		}
+		return run_host_cmd_env_dir(wt.Path, nil, "node", args...)
+	case "remove", "force_remove":
+		args := []string{filepath.Join(scripts, "dc-worktree-down.js"), wt.Name, "--remove"}
+		if op == "force_remove" {
+			args = append(args, "--force")
+		}
+		out, err := run_host_cmd("node", args...)
+		if err != nil {
+			return out, err
+		}
+		drop_checkpoints(repo_root, wt)
+		if len(wt.Set) == 0 {
+			return out, nil
+		}
+		return remove_set_members(wt, cfg, op == "force_remove")
+	case "remove_drop":
+		// Drop after removing, so a dirty worktree that can't be removed keeps its DB
+		out, err := run_host_cmd("node", filepath.Join(scripts, "dc-worktree-down.js"), wt.Name, "--remove")
+		if err == nil {
+			drop_checkpoints(repo_root, wt)
+		}
+		if err == nil && len(wt.Set) > 0 {
+			out, err = remove_set_members(wt, cfg, false)
+		}
+		db := own_db(wt, cfg)
+		if err != nil || db == "" {
+			return out, err
+		}
+		return run_host_cmd_env_dir(repo_root, nil, "node", filepath.Join(scripts, "dc-seed.js"), "--db="+db, "--drop")
+	}
+	return "", fmt.Errorf("unknown bulk action %q", op)
+}
+
+// stop_local_services stops a local worktree's dev server without a UI:
+// processes for non-pm2 managers, the isolated PM2 daemon, or the worktree's
+// entries in the shared PM2.
+func stop_local_services(wt worktree.Worktree, cfg *config.Config) (string, error) {
+	manager := "pm2"
+	if cfg != nil {
+		manager = cfg.ServiceManager()
+	}
+	if manager != "pm2" {
+		kill_local_dev_processes(wt.Path)
+		return "", nil
+	}
+	esbuild.Stop(wt.PM2Home())
+	if wt.IsolatedPM2 {
+		return run_host_cmd_env(pm2.HomeEnv(wt.PM2Home()), "pm2", "kill")
+	}
+	var last_out string
+	var last_err error
+	for _, svc := range pm2.FetchServices(wt.Path) {
+		if svc.Name == "__all" {
+			continue
+		}
+		if out, err := run_host_cmd("pm2", "delete", svc.Name); err != nil {
+			last_out, last_err = out, err
+		}
+	}
+	return last_out, last_err
+}
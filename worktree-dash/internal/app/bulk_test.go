@@ -0,0 +1,159 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/terminal"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bulk_model returns seven worktrees; the first six are running.
+func bulk_model() Model {
+	m := test_model()
+	m.term_mgr = terminal.NewManager()
+	m.worktrees = nil
+	for i := 0; i < 7; i++ {
+		m.worktrees = append(m.worktrees, worktree.Worktree{
+			Name: fmt.Sprintf("wt-%d", i), Alias: fmt.Sprintf("wt%d", i), Path: fmt.Sprintf("/wt/%d", i),
+			Type: worktree.TypeDocker, Running: i < 6, Container: fmt.Sprintf("c%d", i),
+		})
+	}
+	return m
+}
+
+// bulk_done runs cmd and any commands it batches, collecting finished bulk jobs.
+func bulk_done(cmd tea.Cmd) []MsgBulkItemDone {
+	if cmd == nil {
+		return nil
+	}
+	switch msg := cmd().(type) {
+	case MsgBulkItemDone:
+		return []MsgBulkItemDone{msg}
+	case tea.BatchMsg:
+		var out []MsgBulkItemDone
+		for _, c := range msg {
+			out = append(out, bulk_done(c)...)
+		}
+		return out
+	}
+	return nil
+}
+
+func TestMarkKeys(t *testing.T) {
+	m := list_model()
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	m = type_keys(m, space, space)
+	if !m.marked["api"] || !m.marked["feat-login"] || len(m.marked) != 2 || m.cursor != 2 {
+		t.Fatalf("space twice: marked=%v cursor=%d", m.marked, m.cursor)
+	}
+	m.cursor = 0
+	m = type_keys(m, space)
+	if m.marked["api"] || len(m.marked) != 1 {
+		t.Errorf("space on a marked worktree should unmark it: %v", m.marked)
+	}
+
+	// * marks everything the filter lists, and unmarks when all are marked
+	m.marked = nil
+	m.list_query = "feat"
+	m = type_keys(m, runes("*"))
+	if !m.marked["feat-login"] || !m.marked["feat-pay"] || len(m.marked) != 2 {
+		t.Fatalf("mark all with filter: %v", m.marked)
+	}
+	m.list_query = ""
+	m.marked["api"] = true
+	m.list_query = "feat"
+	m = type_keys(m, runes("*"))
+	if len(m.marked) != 1 || !m.marked["api"] {
+		t.Errorf("second * should unmark the listed ones only: %v", m.marked)
+	}
+
+	// Enter opens the bulk menu while anything is marked; Esc clears the marks
+	m.list_query = ""
+	m = type_keys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.picker_open || m.picker_context != pickerBulk {
+		t.Fatalf("enter with marks: picker_open=%v context=%q", m.picker_open, m.picker_context)
+	}
+	m.picker_open = false
+	m = type_keys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if len(m.marked) != 0 {
+		t.Errorf("esc should clear marks: %v", m.marked)
+	}
+}
+
+func TestBulkRun(t *testing.T) {
+	calls := map[string]string{}
+	bulk_job = func(op string, wt worktree.Worktree, repo_root string, cfg *config.Config) (string, error) {
+		calls[wt.Name] = op
+		if wt.Name == "wt-2" {
+			return "Stopping c2\nError: no such container", errors.New("exit status 1")
+		}
+		return "", nil
+	}
+	defer func() { bulk_job = run_bulk_job }()
+
+	m := bulk_model()
+	m.marked = map[string]bool{}
+	for _, wt := range m.worktrees {
+		m.marked[wt.Name] = true
+	}
+
+	op := bulk_ops[0] // stop
+	m, cmd := m.start_bulk(op, m.marked_worktrees())
+	if m.bulk == nil || m.bulk.total != 6 || len(m.bulk.results) != 1 || !m.bulk.results[0].skipped {
+		t.Fatalf("start: %+v", m.bulk)
+	}
+	pending := bulk_done(cmd)
+	if len(pending) != bulkParallelism {
+		t.Fatalf("started %d jobs, want %d", len(pending), bulkParallelism)
+	}
+	if !m.actions_pending["wt-5"] || m.bulk_progress()["wt-5"] != "queued" {
+		t.Errorf("queued worktree: pending=%v progress=%q", m.actions_pending["wt-5"], m.bulk_progress()["wt-5"])
+	}
+
+	for len(pending) > 0 && m.bulk != nil {
+		if n := len(m.bulk.jobs); n > bulkParallelism {
+			t.Fatalf("%d jobs running, limit %d", n, bulkParallelism)
+		}
+		msg := pending[0]
+		pending = pending[1:]
+		m, cmd = m.handle_bulk_item_done(msg)
+		if m.bulk != nil {
+			pending = append(pending, bulk_done(cmd)...)
+		}
+	}
+
+	if m.bulk != nil || len(calls) != 6 || calls["wt-6"] != "" {
+		t.Fatalf("run did not finish over the running worktrees: bulk=%v calls=%v", m.bulk, calls)
+	}
+	if len(m.actions_pending) != 0 {
+		t.Errorf("actions_pending not cleared: %v", m.actions_pending)
+	}
+	if !m.picker_open || m.picker_context != pickerBulkResult || len(m.picker_actions) != 2 {
+		t.Fatalf("summary picker: open=%v context=%q actions=%v", m.picker_open, m.picker_context, m.picker_actions)
+	}
+	if m.bulk_summary != "Stop: 5 ok, 1 failed, 1 skipped" {
+		t.Errorf("summary = %q", m.bulk_summary)
+	}
+	var descs []string
+	for _, a := range m.picker_actions {
+		descs = append(descs, a.Label+": "+a.Desc)
+	}
+	if got := strings.Join(descs, "; "); got != "wt6: skipped: not running; wt2: Error: no such container" {
+		t.Errorf("results = %q", got)
+	}
+	if len(m.marked) != 2 || !m.marked["wt-2"] || !m.marked["wt-6"] {
+		t.Errorf("only failed and skipped worktrees should stay marked: %v", m.marked)
+	}
+
+	m = type_keys(m, runes("2"))
+	if wt := m.selected_worktree(); wt == nil || wt.Name != "wt-2" {
+		t.Errorf("selecting a result should move the cursor to it: %v", wt)
+	}
+}
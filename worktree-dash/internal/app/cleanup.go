@@ -0,0 +1,350 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/docker"
+	"github.com/elvisnm/wt/internal/settings"
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cleanupCandidate is a worktree the cleanup view suggests removing.
+type cleanupCandidate struct {
+	name, alias  string
+	reasons      []string
+	disk         int64 // bytes on disk, -1 when du failed
+	volumes      int
+	volume_bytes int64
+	db           string // own database dropped with the worktree, or ""
+	changes      int    // uncommitted files, which make a normal remove fail
+}
+
+// MsgCleanupScanned carries the cleanup candidates, and the git status read
+// for worktrees that didn't have one cached yet.
+type MsgCleanupScanned struct {
+	Candidates []cleanupCandidate
+	Status     map[string]worktree.GitStatus
+}
+
+// record_start saves when a worktree was started; a worktree that isn't
+// committed to or started for stale_days is suggested for cleanup.
+func (m *Model) record_start(wt worktree.Worktree) {
+	m.repo_state.SetStarted(wt.Name, time.Now())
+	m.apply_repo_state()
+	if err := state.Save(m.repo_root, m.repo_state); err != nil {
+		debug_log("[cleanup] saving start of %s: %v", wt.Name, err)
+	}
+}
+
+// forget_push drops the upstream a removed worktree was seen pushed to, so
+// a new worktree reusing its name doesn't start out with its upstream gone.
+func (m *Model) forget_push(wt worktree.Worktree) {
+	if !m.repo_state.ForgetPushed(wt.Name) {
+		return
+	}
+	if err := state.Save(m.repo_root, m.repo_state); err != nil {
+		debug_log("[cleanup] forgetting the upstream of %s: %v", wt.Name, err)
+	}
+}
+
+// own_db returns the worktree's own database, or "" when it has none or
+// uses the shared one (which dc-seed refuses to drop).
+func own_db(wt worktree.Worktree, cfg *config.Config) string {
+	if cfg == nil || cfg.Database == nil || cfg.Database.Type == "" {
+		return ""
+	}
+	if wt.DBName == "" || wt.DBName == cfg.Database.DefaultDb {
+		return ""
+	}
+	return wt.DBName
+}
+
+// stale_reasons returns why a worktree looks done with: its branch is merged
+// into the base ref, its upstream branch was deleted, or it hasn't had a
+// commit or a start in days. A branch nobody committed to yet is level
+// with the base, so never_committed keeps it from counting as merged.
+func stale_reasons(wt worktree.Worktree, never_committed bool, started time.Time, days int, now time.Time) []string {
+	var reasons []string
+	s := wt.Git
+	on_base := s.Base == wt.Branch || strings.HasSuffix(s.Base, "/"+wt.Branch)
+	if s.Base != "" && s.BaseAhead == 0 && wt.Branch != "" && !wt.Detached && !on_base && !never_committed {
+		reasons = append(reasons, "merged into "+s.Base)
+	}
+	if s.UpstreamGone {
+		reasons = append(reasons, fmt.Sprintf("upstream %s is gone", s.Upstream))
+	}
+	last := s.CommitTime
+	if started.After(last) {
+		last = started
+	}
+	if !wt.Running && !last.IsZero() && now.Sub(last) > time.Duration(days)*24*time.Hour {
+		reasons = append(reasons, fmt.Sprintf("no commit or start in %d days", int(now.Sub(last).Hours()/24)))
+	}
+	return reasons
+}
+
+// open_cleanup scans for merged and stale worktrees in the background and
+// lists them once the scan is done.
+func (m Model) open_cleanup() (Model, tea.Cmd) {
+	if m.cleanup_scanning {
+		return m, nil
+	}
+	days := m.stale_days
+	if days <= 0 {
+		days = settings.DefaultStaleDays
+	}
+	m.cleanup_scanning = true
+	m.activity = "Scanning for merged and stale worktrees..."
+	return m, cmd_scan_cleanup(m.repo_root, m.base_refs(), m.cfg, m.worktrees, m.repo_state, days)
+}
+
+// cmd_scan_cleanup finds the cleanup candidates. Pinned, locked and prunable
+// worktrees are left out: pins mean keep, locks block removal and prunable
+// ones are cleaned up with `git worktree prune`. Git status is read where it
+// isn't cached yet, and disk and volume usage only for candidates.
+func cmd_scan_cleanup(repo_root string, base_refs []string, cfg *config.Config, wts []worktree.Worktree, rs state.State, days int) tea.Cmd {
+	var scan []worktree.Worktree
+	for _, wt := range wts {
+		if !wt.Pinned && !wt.Locked && !wt.Prunable {
+			scan = append(scan, wt)
+		}
+	}
+	// Copied because the scan runs off the UI goroutine
+	starts := make(map[string]time.Time, len(rs.Started))
+	for name, t := range rs.Started {
+		starts[name] = t
+	}
+	pushed := make(map[string]string, len(rs.Pushed))
+	for name, u := range rs.Pushed {
+		pushed[name] = u
+	}
+	return func() tea.Msg {
+		base := ""
+		results := make([]*cleanupCandidate, len(scan))
+		read := make([]bool, len(scan))
+		now := time.Now()
+
+		var once sync.Once
+		var volumes map[string]int64
+		var wg sync.WaitGroup
+		for w := 0; w < gitStatusParallelism && w < len(scan); w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := w; i < len(scan); i += gitStatusParallelism {
+					wt := &scan[i]
+					if !wt.Git.Known() {
+						once.Do(func() { base = worktree.ResolveBaseRef(repo_root, base_refs) })
+						wt.Git = worktree.ReadGitStatus(wt.Path, base)
+						wt.Git.MarkGone(pushed[wt.Name])
+						read[i] = true
+					}
+					never := wt.Git.Base != "" && wt.Git.BaseAhead == 0 && worktree.NeverCommitted(wt.Path, wt.Branch)
+					reasons := stale_reasons(*wt, never, starts[wt.Name], days, now)
+					if len(reasons) == 0 {
+						continue
+					}
+					c := &cleanupCandidate{
+						name: wt.Name, alias: wt.Alias, reasons: reasons,
+						db: own_db(*wt, cfg), changes: wt.Git.Changes(),
+					}
+					var err error
+					if c.disk, err = worktree.DiskUsage(wt.Path); err != nil {
+						c.disk = -1
+					}
+					results[i] = c
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		msg := MsgCleanupScanned{Status: map[string]worktree.GitStatus{}}
+		for i, c := range results {
+			if read[i] {
+				msg.Status[scan[i].Path] = scan[i].Git
+			}
+			if c == nil {
+				continue
+			}
+			if scan[i].Type == worktree.TypeDocker && cfg != nil {
+				if volumes == nil {
+					volumes = docker.FetchVolumeSizes()
+				}
+				c.volumes, c.volume_bytes = docker.VolumeUsage(volumes, cfg.VolumePrefix(scan[i].Alias))
+			}
+			msg.Candidates = append(msg.Candidates, *c)
+		}
+		return msg
+	}
+}
+
+// handle_cleanup_scanned caches any git status the scan read and lists the
+// candidates.
+func (m Model) handle_cleanup_scanned(msg MsgCleanupScanned) (Model, tea.Cmd) {
+	m.cleanup_scanning = false
+	m.activity = ""
+	m.cleanup = msg.Candidates
+	var relist tea.Cmd
+	if len(msg.Status) > 0 {
+		if m.git_status == nil {
+			m.git_status = map[string]worktree.GitStatus{}
+		}
+		for path, s := range msg.Status {
+			m.git_status[path] = s
+		}
+		selected := m.selected_name()
+		m.apply_git_status()
+		m, relist = m.relist(selected)
+	}
+	var cmd tea.Cmd
+	if len(m.cleanup) == 0 {
+		m, cmd = m.show_notification("Cleanup", "Nothing to clean up: no merged, orphaned or stale worktrees")
+	} else {
+		m, cmd = m.open_panel_picker("Cleanup", m.cleanup_actions(), pickerCleanup)
+	}
+	return m, tea.Batch(relist, cmd)
+}
+
+// cleanup_actions lists the candidates after the remove-all and mark-all
+// actions. Each shows why it's listed and what removing it frees.
+func (m Model) cleanup_actions() []ui.PickerAction {
+	dbs := 0
+	for _, c := range m.cleanup {
+		if c.db != "" {
+			dbs++
+		}
+	}
+	remove_desc := "Fails if dirty"
+	if dbs > 0 {
+		remove_desc = fmt.Sprintf("Drops %s · fails if dirty", plural(dbs, "database"))
+	}
+	actions := []ui.PickerAction{
+		{Key: "x", Label: fmt.Sprintf("Remove all %d", len(m.cleanup)), Desc: remove_desc},
+		{Key: "m", Label: "Mark all", Desc: "For another bulk action"},
+	}
+	for i, c := range m.cleanup {
+		parts := []string{strings.Join(c.reasons, ", ")}
+		if c.disk >= 0 {
+			parts = append(parts, format_size(c.disk))
+		}
+		if c.volumes > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", plural(c.volumes, "volume"), format_size(c.volume_bytes)))
+		}
+		if c.db != "" {
+			parts = append(parts, "db "+c.db)
+		}
+		if c.changes > 0 {
+			parts = append(parts, plural(c.changes, "uncommitted file"))
+		}
+		alias := c.alias
+		if alias == "" {
+			alias = c.name
+		}
+		actions = append(actions, ui.PickerAction{Key: fmt.Sprintf("%d", i+1), Label: alias, Desc: strings.Join(parts, " · ")})
+	}
+	return actions
+}
+
+// cleanup_title shows how much the candidates take up.
+func (m Model) cleanup_title() string {
+	var total int64
+	for _, c := range m.cleanup {
+		if c.disk > 0 {
+			total += c.disk
+		}
+		total += c.volume_bytes
+	}
+	return fmt.Sprintf("Cleanup — %d worktrees, %s", len(m.cleanup), format_size(total))
+}
+
+// cleanup_worktrees returns the candidates that still exist.
+func (m Model) cleanup_worktrees() []worktree.Worktree {
+	by_name := make(map[string]worktree.Worktree, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		by_name[wt.Name] = wt
+	}
+	var result []worktree.Worktree
+	for _, c := range m.cleanup {
+		if wt, ok := by_name[c.name]; ok {
+			result = append(result, wt)
+		}
+	}
+	return result
+}
+
+// execute_cleanup_action removes every candidate in one confirmed bulk
+// action, marks them, or moves the cursor to the one selected.
+func (m Model) execute_cleanup_action(action ui.PickerAction) (Model, tea.Cmd) {
+	wts := m.cleanup_worktrees()
+	switch action.Key {
+	case "x":
+		if len(wts) == 0 {
+			return m, nil
+		}
+		if m.bulk != nil {
+			return m.show_notification("Cleanup", fmt.Sprintf("%s is still running", m.bulk.op.label))
+		}
+		op := bulk_op("remove_drop")
+		prompt := fmt.Sprintf("Remove %s?", plural(len(wts), "worktree"))
+		dbs := 0
+		for _, wt := range wts {
+			if own_db(wt, m.cfg) != "" {
+				dbs++
+			}
+		}
+		if dbs > 0 {
+			prompt = fmt.Sprintf("Remove %s and drop %s?", plural(len(wts), "worktree"), plural(dbs, "database"))
+		}
+		return m.open_panel_confirm("Cleanup", prompt,
+			func(mdl *Model) (Model, tea.Cmd) { return mdl.start_bulk(op, wts) })
+	case "m":
+		marked := make(map[string]bool, len(m.marked)+len(wts))
+		for name := range m.marked {
+			marked[name] = true
+		}
+		for _, wt := range wts {
+			marked[wt.Name] = true
+		}
+		m.marked = marked
+		m.activity = fmt.Sprintf("Marked %s", plural(len(wts), "worktree"))
+		return m, tick_after(3*time.Second, "clear-activity")
+	}
+
+	// The candidates follow the actions on all of them
+	idx := m.picker_cursor - (len(m.picker_actions) - len(m.cleanup))
+	if idx < 0 || idx >= len(m.cleanup) {
+		return m, nil
+	}
+	return m.focus_worktree(m.cleanup[idx].name, "Cleanup")
+}
+
+// bulk_op returns the bulk action with the given id.
+func bulk_op(id string) bulkOp {
+	for _, op := range bulk_ops {
+		if op.id == id {
+			return op
+		}
+	}
+	return bulkOp{}
+}
+
+// format_size formats bytes as "512 MB" or "1.5 GB".
+func format_size(n int64) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.1f GB", float64(n)/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.0f MB", float64(n)/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.0f kB", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d B", n)
+}
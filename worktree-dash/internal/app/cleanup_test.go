@@ -0,0 +1,164 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/terminal"
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+func TestStaleReasons(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-45 * 24 * time.Hour)
+	wt := worktree.Worktree{Branch: "feat/login", Git: worktree.GitStatus{
+		Base: "origin/main", Upstream: "origin/feat/login", UpstreamGone: true, CommitTime: old,
+	}}
+
+	got := strings.Join(stale_reasons(wt, false, time.Time{}, 30, now), "; ")
+	if got != "merged into origin/main; upstream origin/feat/login is gone; no commit or start in 45 days" {
+		t.Errorf("reasons = %q", got)
+	}
+
+	// A recent start, a running worktree or a branch with no commits of its own don't count
+	if r := stale_reasons(wt, true, now.Add(-time.Hour), 30, now); len(r) != 1 || !strings.HasPrefix(r[0], "upstream") {
+		t.Errorf("recently started, never committed: %v", r)
+	}
+	wt.Running = true
+	wt.Git.UpstreamGone = false
+	wt.Git.BaseAhead = 2
+	if r := stale_reasons(wt, false, time.Time{}, 30, now); len(r) != 0 {
+		t.Errorf("running with unmerged commits: %v", r)
+	}
+
+	// The base branch's own checkout isn't merged into itself
+	main := worktree.Worktree{Branch: "main", Git: worktree.GitStatus{Base: "origin/main"}}
+	if r := stale_reasons(main, false, time.Time{}, 30, now); len(r) != 0 {
+		t.Errorf("base branch checkout: %v", r)
+	}
+}
+
+func TestUpstreamGone(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := list_model()
+	wt := m.worktrees[1]
+	missing := worktree.GitStatus{Upstream: "origin/" + wt.Branch, UpstreamMissing: true, Fetched: time.Now()}
+	m.git_status = map[string]worktree.GitStatus{wt.Path: missing}
+	m.apply_git_status()
+	if m.worktrees[1].Git.UpstreamGone {
+		t.Fatal("an upstream set before the first push isn't gone")
+	}
+
+	m.git_status[wt.Path] = worktree.GitStatus{Upstream: missing.Upstream, Fetched: time.Now()}
+	m.apply_git_status()
+	m.git_status[wt.Path] = missing
+	m.apply_git_status()
+	if !m.worktrees[1].Git.UpstreamGone || state.Load(m.repo_root).PushedTo(wt.Name) != missing.Upstream {
+		t.Fatalf("an upstream seen pushed and then missing is gone: %+v", m.worktrees[1].Git)
+	}
+
+	// Removing the worktree forgets it, for the next one with its name
+	m.forget_push(wt)
+	m.apply_git_status()
+	if m.worktrees[1].Git.UpstreamGone {
+		t.Error("a removed worktree's upstream should be forgotten")
+	}
+}
+
+func TestOwnDB(t *testing.T) {
+	wt := worktree.Worktree{DBName: "db_feat_login"}
+	if own_db(wt, &config.Config{}) != "" {
+		t.Error("no database configured should mean nothing to drop")
+	}
+	cfg := &config.Config{Database: &config.DatabaseConfig{Type: "mongodb", DefaultDb: "db"}}
+	if own_db(wt, cfg) != "db_feat_login" {
+		t.Error("a per-worktree database should be dropped")
+	}
+	if own_db(worktree.Worktree{DBName: "db"}, cfg) != "" {
+		t.Error("the shared database must never be dropped")
+	}
+}
+
+func TestCleanupPicker(t *testing.T) {
+	m := list_model()
+	m.term_mgr = terminal.NewManager()
+	m.cfg.Database = &config.DatabaseConfig{Type: "mongodb", DefaultDb: "db"}
+	m.worktrees[1].DBName = "db_login"
+
+	result, _ := m.Update(MsgCleanupScanned{Candidates: []cleanupCandidate{
+		{name: "feat-login", alias: "login", reasons: []string{"merged into origin/main"}, disk: 300e6, volumes: 2, volume_bytes: 1.5e9, db: "db_login"},
+		{name: "fix-crash", alias: "crash", reasons: []string{"no commit or start in 40 days"}, disk: -1, changes: 1},
+	}})
+	m = result.(Model)
+	if !m.picker_open || m.picker_context != pickerCleanup {
+		t.Fatalf("picker: open=%v context=%q", m.picker_open, m.picker_context)
+	}
+	var descs []string
+	for _, a := range m.picker_actions {
+		descs = append(descs, a.Key+" "+a.Label+": "+a.Desc)
+	}
+	want := "x Remove all 2: Drops 1 database · fails if dirty; m Mark all: For another bulk action; " +
+		"1 login: merged into origin/main · 300 MB · 2 volumes 1.5 GB · db db_login; " +
+		"2 crash: no commit or start in 40 days · 1 uncommitted file"
+	if got := strings.Join(descs, "; "); got != want {
+		t.Errorf("actions =\n%s\nwant\n%s", got, want)
+	}
+	if got := m.cleanup_title(); got != "Cleanup — 2 worktrees, 1.8 GB" {
+		t.Errorf("title = %q", got)
+	}
+
+	// Selecting a candidate moves the cursor to it
+	m = type_keys(m, runes("2"))
+	if wt := m.selected_worktree(); wt == nil || wt.Name != "fix-crash" {
+		t.Errorf("selected %v", wt)
+	}
+
+	m, _ = m.execute_cleanup_action(m.picker_actions[1])
+	if len(m.marked) != 2 || !m.marked["feat-login"] || !m.marked["fix-crash"] {
+		t.Errorf("mark all: %v", m.marked)
+	}
+
+	// Remove all is one confirmed bulk action that drops the databases
+	ops := map[string]string{}
+	bulk_job = func(op string, wt worktree.Worktree, repo_root string, cfg *config.Config) (string, error) {
+		ops[wt.Name] = op
+		return "", nil
+	}
+	defer func() { bulk_job = run_bulk_job }()
+	m, _ = m.execute_cleanup_action(m.picker_actions[0])
+	if !m.confirm_open || m.confirm_prompt != "Remove 2 worktrees and drop 1 database?" {
+		t.Fatalf("confirm: open=%v prompt=%q", m.confirm_open, m.confirm_prompt)
+	}
+	m, cmd := m.confirm_action(&m)
+	for _, msg := range bulk_done(cmd) {
+		m, _ = m.handle_bulk_item_done(msg)
+	}
+	if ops["feat-login"] != "remove_drop" || ops["fix-crash"] != "remove_drop" || m.bulk != nil {
+		t.Errorf("bulk ops = %v, running=%v", ops, m.bulk != nil)
+	}
+}
+
+func TestCleanupNothingToDo(t *testing.T) {
+	m := list_model()
+	result, _ := m.Update(MsgCleanupScanned{Status: map[string]worktree.GitStatus{
+		"/wt/api": {Base: "origin/main", Fetched: time.Now()},
+	}})
+	m = result.(Model)
+	if m.picker_open || !m.notify_open || m.cleanup_scanning {
+		t.Errorf("empty scan: picker=%v notify=%v", m.picker_open, m.notify_open)
+	}
+	if !m.worktrees[0].Git.Known() {
+		t.Error("status read by the scan should be cached")
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{0: "0 B", 2048: "2 kB", 300e6: "300 MB", 1.25e9: "1.2 GB"} {
+		if got := format_size(n); got != want {
+			t.Errorf("format_size(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
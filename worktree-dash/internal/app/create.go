@@ -0,0 +1,673 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/aws"
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// createPlan is what the quick-create flow collected before it runs.
+type createPlan struct {
+	branch string
+	from   string // base ref a new branch starts from, or ""
+	remote string // remote branch checked out ("origin/feat/login"), or ""
+	fetch  string // remote branch fetched first, or ""
+	alias  string
+	env    string // "docker", "host_build" or "local"
+	mode   string
+	db     string // "isolated", "seed", "clone" or "shared"; "" without a local database
+
+	// Duplicating a worktree (see duplicate.go)
+	source    string // path of the worktree duplicated, "" for a plain create
+	source_db string // its isolated database, offered to clone
+	changes   string // "carry" or "leave" its uncommitted changes; "" until asked
+	stash     string // stash commit holding the changes, made as the create starts
+
+	// Worktree set (see sets.go): "set" or "single"; "" until asked, and
+	// without related repos in config
+	set       string
+	set_repos []setRepo // planned as the create starts
+
+	// Sparse checkout: a sparseProfiles name, "full", or "" until asked (and
+	// without profiles in config); sparse_dirs are the directories checked out
+	sparse      string
+	sparse_dirs []string
+}
+
+// createStep is one step of a quick create: commands run in order in the
+// repo root. An optional step that fails is shown as skipped.
+type createStep struct {
+	label    string
+	optional bool
+	cmds     [][]string
+	detail   string // shown once the step is done
+}
+
+// createRun tracks a quick create while its steps run.
+type createRun struct {
+	plan    createPlan
+	steps   []createStep
+	state   []ui.Step
+	current int
+	failed  bool
+}
+
+// MsgCreateStep reports that step Index of the running quick create finished.
+type MsgCreateStep struct {
+	Index  int
+	Output string
+	Err    error
+}
+
+// create_exec runs one command of a create step. Tests replace it.
+var create_exec = run_host_cmd_env_dir
+
+var (
+	branch_name_re = regexp.MustCompile(`^[a-zA-Z0-9/_-]+$`)
+	alias_re       = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	alias_junk_re  = regexp.MustCompile(`[^a-zA-Z0-9-]`)
+)
+
+// branch_prefixes returns repo.branchPrefixes without trailing slashes.
+func branch_prefixes(cfg *config.Config) []string {
+	if cfg == nil {
+		return nil
+	}
+	var prefixes []string
+	for _, p := range cfg.Repo.BranchPrefixes {
+		if p = strings.Trim(p, "/"); p != "" {
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes
+}
+
+// validate_branch returns why name can't be a new branch, or "" when it can.
+// With repo.branchPrefixes configured, the name must start with one of them.
+func validate_branch(name string, prefixes []string) string {
+	if !branch_name_re.MatchString(name) {
+		return "Branch name contains invalid characters"
+	}
+	if len(prefixes) == 0 {
+		return ""
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(name, p+"/") && len(name) > len(p)+1 {
+			return ""
+		}
+	}
+	return "Branch must start with " + strings.Join(prefixes, "/, ") + "/"
+}
+
+// validate_alias returns why alias can't be used, or "" when it can.
+func validate_alias(alias string, wts []worktree.Worktree) string {
+	if !alias_re.MatchString(alias) {
+		return "Must be lowercase alphanumeric with hyphens"
+	}
+	if len(alias) > 30 {
+		return "Must be 30 characters or less"
+	}
+	for _, wt := range wts {
+		if wt.Alias == alias {
+			return fmt.Sprintf("Alias %q is already in use by another worktree", alias)
+		}
+	}
+	return ""
+}
+
+// auto_alias derives a short alias from a branch name the way dc-create
+// does: the prefix is dropped and the first two words kept.
+func auto_alias(branch string, prefixes []string) string {
+	stripped := branch
+	for _, p := range prefixes {
+		if len(branch) > len(p)+1 && strings.EqualFold(branch[:len(p)+1], p+"/") {
+			stripped = branch[len(p)+1:]
+			break
+		}
+	}
+	clean := strings.ToLower(alias_junk_re.ReplaceAllString(strings.ReplaceAll(stripped, "/", "-"), "-"))
+	var parts []string
+	for _, part := range strings.Split(clean, "-") {
+		if part != "" && len(parts) < 2 {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		if len(clean) > 20 {
+			return clean[:20]
+		}
+		return clean
+	}
+	return strings.Join(parts, "-")
+}
+
+// worktree_dir_for returns where dc-worktree-up puts a branch's worktree.
+func worktree_dir_for(worktrees_dir, branch string) string {
+	return filepath.Join(worktrees_dir, strings.ReplaceAll(branch, "/", "-"))
+}
+
+// branch_taken returns why a new branch can't be created under this name:
+// the branch or its worktree directory already exists.
+func branch_taken(repo_root, worktrees_dir, branch string) string {
+	if _, err := run_host_cmd("git", "-C", repo_root, "show-ref", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		return fmt.Sprintf("Branch %s already exists", branch)
+	}
+	if _, err := os.Stat(worktree_dir_for(worktrees_dir, branch)); err == nil {
+		return fmt.Sprintf("%s already exists", worktree_dir_for(worktrees_dir, branch))
+	}
+	return ""
+}
+
+// create_entries builds the quick-create picker: a new branch from each base
+// ref that exists, then local branches and remote branches without a local
+// counterpart, most recently committed first. Branches checked out in a
+// worktree are left out since git won't check them out twice.
+func create_entries(branches []worktree.Branch, checked_out map[string]bool, base_refs []string) []paletteEntry {
+	local := map[string]bool{}
+	exists := map[string]bool{}
+	for _, b := range branches {
+		if !b.Remote {
+			local[b.Name] = true
+			exists["refs/heads/"+b.Name] = true
+		} else {
+			exists["refs/remotes/"+b.Name] = true
+		}
+	}
+
+	var entries []paletteEntry
+	seen := map[string]bool{}
+	for _, ref := range worktree.BaseRefCandidates(base_refs) {
+		short := strings.TrimPrefix(strings.TrimPrefix(ref, "refs/remotes/"), "refs/heads/")
+		if !exists[ref] || seen[short] {
+			continue
+		}
+		seen[short] = true
+		base := createPlan{from: short}
+		if strings.HasPrefix(ref, "refs/remotes/") {
+			base.fetch = short
+		}
+		entries = append(entries, paletteEntry{
+			title: "New branch from " + short,
+			hint:  "base",
+			run:   func(m Model) (tea.Model, tea.Cmd) { return m.prompt_new_branch(base, "", "") },
+		})
+	}
+
+	for _, b := range branches {
+		b := b
+		plan := createPlan{branch: b.Name}
+		hint := "local"
+		if b.Remote {
+			_, name, ok := strings.Cut(b.Name, "/")
+			if !ok || local[name] {
+				continue
+			}
+			plan = createPlan{branch: name, remote: b.Name, fetch: b.Name}
+			hint = "remote"
+		}
+		if checked_out[plan.branch] {
+			continue
+		}
+		if !b.CommitTime.IsZero() {
+			hint += " · " + ui.FormatAge(b.CommitTime)
+		}
+		entries = append(entries, paletteEntry{
+			title: b.Name,
+			hint:  hint,
+			run:   func(m Model) (tea.Model, tea.Cmd) { return m.prompt_alias(plan, "", "") },
+		})
+	}
+
+	entries = append(entries, paletteEntry{
+		title: "Interactive wizard",
+		hint:  "dc-create",
+		run:   func(m Model) (tea.Model, tea.Cmd) { return m.open_create(nil) },
+	})
+	return entries
+}
+
+// open_quick_create lists branches to create a worktree from, in a fuzzy
+// picker built on the command palette.
+func (m Model) open_quick_create() (Model, tea.Cmd) {
+	if m.create != nil && !m.create.done() {
+		return m.show_notification("New worktree", fmt.Sprintf("Still creating %s", m.create.plan.branch))
+	}
+	branches, err := worktree.ListBranches(m.repo_root)
+	if err != nil {
+		return m.show_notification("New worktree", fmt.Sprintf("Listing branches failed: %v", err))
+	}
+	checked_out := map[string]bool{}
+	if gwts, err := worktree.ListGitWorktrees(m.repo_root); err == nil {
+		for _, g := range gwts {
+			if g.Branch != "" {
+				checked_out[g.Branch] = true
+			}
+		}
+	}
+
+	m.create = nil
+	m.palette_open = true
+	m.palette_title = "New worktree"
+	m.palette_query = ""
+	m.palette_cursor = 0
+	m.palette_index = create_entries(branches, checked_out, m.base_refs())
+	m.palette_history = nil
+	m.palette_results = rank_palette(m.palette_index, "", nil)
+	m.recalc_layout()
+	return m, nil
+}
+
+// prompt_new_branch asks for the name of a branch to create from base.from.
+// A rejected name is asked for again, with the problem in the prompt.
+func (m Model) prompt_new_branch(base createPlan, value, problem string) (Model, tea.Cmd) {
+	prompt := fmt.Sprintf("New branch from %s:", base.from)
+	if problem != "" {
+		prompt = fmt.Sprintf("%s — new branch from %s:", problem, base.from)
+	}
+	m, cmd := m.open_panel_input("New worktree", prompt, func(mdl *Model, value string) (Model, tea.Cmd) {
+		branch := strings.TrimSpace(value)
+		problem := validate_branch(branch, branch_prefixes(mdl.cfg))
+		if problem == "" {
+			problem = branch_taken(mdl.repo_root, mdl.worktrees_dir, branch)
+		}
+		if problem != "" {
+			return mdl.prompt_new_branch(base, value, problem)
+		}
+		plan := base
+		plan.branch = branch
+		return mdl.prompt_alias(plan, "", "")
+	})
+	m.input_value = value
+	return m, cmd
+}
+
+// prompt_alias asks for the worktree's alias, prefilled with one derived
+// from the branch name.
+func (m Model) prompt_alias(plan createPlan, value, problem string) (Model, tea.Cmd) {
+	if value == "" {
+		value = auto_alias(plan.branch, branch_prefixes(m.cfg))
+	}
+	prompt := fmt.Sprintf("Alias for %s:", plan.branch)
+	if problem != "" {
+		prompt = fmt.Sprintf("%s — alias for %s:", problem, plan.branch)
+	}
+	m, cmd := m.open_panel_input("New worktree", prompt, func(mdl *Model, value string) (Model, tea.Cmd) {
+		alias := strings.TrimSpace(value)
+		if problem := validate_alias(alias, mdl.worktrees); problem != "" {
+			return mdl.prompt_alias(plan, value, problem)
+		}
+		plan.alias = alias
+		mdl.create_plan = plan
+		return mdl.continue_create()
+	})
+	m.input_value = value
+	return m, cmd
+}
+
+// has_local_db reports whether the project runs its own database, which a
+// Docker worktree can get an isolated copy of.
+func has_local_db(cfg *config.Config) bool {
+	return cfg != nil && cfg.Database != nil && (cfg.Database.Host != "" || cfg.Database.ContainerHost != "")
+}
+
+// create_modes returns the service modes, the default first.
+func create_modes(cfg *config.Config) []string {
+	if cfg == nil {
+		return nil
+	}
+	var modes []string
+	for mode := range cfg.Services.Modes {
+		modes = append(modes, mode)
+	}
+	def := cfg.Services.DefaultMode
+	sort.Slice(modes, func(i, j int) bool {
+		if (modes[i] == def) != (modes[j] == def) {
+			return modes[i] == def
+		}
+		return modes[i] < modes[j]
+	})
+	return modes
+}
+
+// continue_create asks for the next option the plan is missing: environment
+// (with Docker configured), service mode (with more than one), and database
+// (Docker with a local database). Then it confirms the plan.
+func (m Model) continue_create() (Model, tea.Cmd) {
+	plan := &m.create_plan
+	if plan.env == "" {
+		if m.cfg == nil || m.cfg.Docker.ComposeStrategy == "" {
+			plan.env = "local"
+		} else {
+			actions := []ui.PickerAction{{Key: "d", Label: "Docker", Desc: "App in a container"}}
+			if m.cfg.Features.HostBuild {
+				actions = append(actions, ui.PickerAction{Key: "h", Label: "Docker + host build", Desc: "Container + esbuild on host"})
+			}
+			actions = append(actions, ui.PickerAction{Key: "l", Label: "Local", Desc: "Services on the host"})
+			return m.open_panel_picker("Environment", actions, pickerCreateEnv)
+		}
+	}
+
+	modes := create_modes(m.cfg)
+	if plan.mode == "" {
+		if len(modes) > 1 {
+			var actions []ui.PickerAction
+			for i, mode := range modes {
+				desc := strings.Join(m.cfg.Services.Modes[mode], ", ")
+				if desc == "" {
+					desc = "All services"
+				}
+				actions = append(actions, ui.PickerAction{Key: fmt.Sprintf("%d", i+1), Label: mode, Desc: desc})
+			}
+			return m.open_panel_picker("Service mode", actions, pickerCreateMode)
+		}
+		if len(modes) == 1 {
+			plan.mode = modes[0]
+		}
+	}
+
+	if plan.sparse == "" && m.cfg != nil && len(m.cfg.SparseProfiles) > 0 {
+		actions := []ui.PickerAction{{Key: "f", Label: "Full checkout", Desc: "Every file"}}
+		for i, name := range m.cfg.SparseProfileNames() {
+			actions = append(actions, ui.PickerAction{Key: fmt.Sprintf("%d", i+1), Label: name, Desc: strings.Join(m.cfg.SparseProfiles[name], ", ")})
+		}
+		return m.open_panel_picker("Checkout", actions, pickerCreateSparse)
+	}
+
+	if plan.db == "" && has_local_db(m.cfg) {
+		if plan.env == "local" {
+			plan.db = "shared"
+		} else {
+			var actions []ui.PickerAction
+			if plan.source_db != "" {
+				actions = append(actions, ui.PickerAction{Key: "c", Label: "Clone", Desc: "Copy of " + plan.source_db})
+			}
+			actions = append(actions, ui.PickerAction{Key: "i", Label: "Isolated", Desc: m.cfg.DbName(plan.alias)})
+			if m.cfg.Database.SeedCommand != "" {
+				actions = append(actions, ui.PickerAction{Key: "e", Label: "Isolated + seed", Desc: "Seeded from " + m.cfg.Database.DefaultDb})
+			}
+			actions = append(actions, ui.PickerAction{Key: "s", Label: "Shared", Desc: m.cfg.Database.DefaultDb})
+			return m.open_panel_picker("Database", actions, pickerCreateDB)
+		}
+	}
+
+	if plan.source != "" && plan.changes == "" {
+		return m.open_changes_picker()
+	}
+
+	if plan.set == "" && m.cfg != nil && len(m.cfg.Repo.Related) > 0 {
+		var names []string
+		for _, r := range m.cfg.Repo.Related {
+			names = append(names, r.Name)
+		}
+		return m.open_panel_picker("Worktree set", []ui.PickerAction{
+			{Key: "s", Label: "Set", Desc: plan.branch + " in " + strings.Join(names, ", ") + " too"},
+			{Key: "o", Label: "This repo only"},
+		}, pickerCreateSet)
+	}
+
+	return m.open_panel_confirm("New worktree", create_summary(m.create_plan), func(mdl *Model) (Model, tea.Cmd) {
+		return mdl.start_create(mdl.create_plan)
+	})
+}
+
+// execute_create_choice records an option picked in continue_create and
+// asks for the next one.
+func (m Model) execute_create_choice(action ui.PickerAction) (Model, tea.Cmd) {
+	switch m.picker_context {
+	case pickerCreateEnv:
+		m.create_plan.env = map[string]string{"d": "docker", "h": "host_build", "l": "local"}[action.Key]
+	case pickerCreateMode:
+		m.create_plan.mode = action.Label
+	case pickerCreateDB:
+		m.create_plan.db = map[string]string{"c": "clone", "i": "isolated", "e": "seed", "s": "shared"}[action.Key]
+	case pickerCreateChanges:
+		m.create_plan.changes = map[string]string{"c": "carry", "l": "leave"}[action.Key]
+	case pickerCreateSet:
+		m.create_plan.set = map[string]string{"s": "set", "o": "single"}[action.Key]
+	case pickerCreateSparse:
+		m.create_plan.sparse, m.create_plan.sparse_dirs = "full", nil
+		if action.Key != "f" {
+			m.create_plan.sparse, m.create_plan.sparse_dirs = action.Label, m.cfg.SparseProfiles[action.Label]
+		}
+	}
+	return m.continue_create()
+}
+
+// create_summary is the confirm prompt for a plan.
+func create_summary(plan createPlan) string {
+	source := "from " + plan.from
+	if plan.remote != "" {
+		source = "tracking " + plan.remote
+	} else if plan.from == "" {
+		source = "existing branch"
+	}
+	opts := []string{strings.ReplaceAll(plan.env, "_", " ")}
+	if plan.mode != "" {
+		opts = append(opts, plan.mode)
+	}
+	if len(plan.sparse_dirs) > 0 {
+		opts = append(opts, "sparse "+plan.sparse)
+	}
+	switch plan.db {
+	case "isolated":
+		opts = append(opts, "isolated db")
+	case "seed":
+		opts = append(opts, "seeded db")
+	case "clone":
+		opts = append(opts, "cloned db")
+	case "shared":
+		opts = append(opts, "shared db")
+	}
+	if plan.changes == "carry" {
+		opts = append(opts, "uncommitted changes")
+	}
+	if plan.set == "set" {
+		opts = append(opts, "worktree set")
+	}
+	return fmt.Sprintf("Create %s as %s (%s, %s)?", plan.branch, plan.alias, source, strings.Join(opts, ", "))
+}
+
+// create_steps turns a plan into the steps that carry it out. The git
+// worktree is added here; dc-worktree-up --skip-git does the env setup.
+func create_steps(plan createPlan, repo_root, worktrees_dir, scripts_dir string) []createStep {
+	git := func(args ...string) []string { return append([]string{"git", "-C", repo_root}, args...) }
+	path := worktree_dir_for(worktrees_dir, plan.branch)
+	var steps []createStep
+
+	// A failed fetch (offline) isn't fatal: the branch starts from the ref as last fetched
+	if remote, branch, ok := strings.Cut(plan.fetch, "/"); ok {
+		steps = append(steps, createStep{label: "Fetch " + plan.fetch, optional: true, cmds: [][]string{git("fetch", remote, branch)}})
+	}
+
+	add := createStep{label: "Add worktree", detail: path, cmds: [][]string{git("worktree", "prune")}}
+	add_args := []string{"worktree", "add"}
+	if len(plan.sparse_dirs) > 0 {
+		// Checked out once the cone is set, so files outside it are never
+		// written (nor, in a partial clone, their blobs fetched)
+		add_args = append(add_args, "--no-checkout")
+	}
+	switch {
+	case plan.remote != "":
+		add.cmds = append(add.cmds, git(append(add_args, "--track", "-b", plan.branch, path, plan.remote)...))
+	case plan.from != "":
+		add.cmds = append(add.cmds, git(append(add_args, "-b", plan.branch, path, plan.from)...))
+	default:
+		add.cmds = append(add.cmds, git(append(add_args, path, plan.branch)...))
+	}
+	steps = append(steps, add)
+
+	if len(plan.sparse_dirs) > 0 {
+		steps = append(steps, createStep{label: "Sparse checkout " + plan.sparse, detail: strings.Join(plan.sparse_dirs, ", "), cmds: [][]string{
+			append([]string{"git", "-C", path, "sparse-checkout", "set", "--cone"}, plan.sparse_dirs...),
+			{"git", "-C", path, "checkout"},
+		}})
+	}
+	steps = append(steps, set_steps(plan.set_repos, plan.branch)...)
+
+	if plan.stash != "" {
+		steps = append(steps, createStep{label: "Apply uncommitted changes", cmds: [][]string{
+			{"git", "-C", path, "stash", "apply", plan.stash},
+		}})
+	}
+
+	if plan.from != "" {
+		steps = append(steps, createStep{label: "Track origin/" + plan.branch, cmds: [][]string{
+			git("config", "branch."+plan.branch+".remote", "origin"),
+			git("config", "branch."+plan.branch+".merge", "refs/heads/"+plan.branch),
+		}})
+	}
+
+	up := []string{"node", filepath.Join(scripts_dir, "dc-worktree-up.js"), plan.branch, "--skip-git", "--alias=" + plan.alias}
+	if plan.mode != "" {
+		up = append(up, "--mode="+plan.mode)
+	}
+	switch plan.env {
+	case "host_build":
+		up = append(up, "--host-build")
+	case "local":
+		up = append(up, "--no-docker")
+	}
+	if plan.db == "shared" {
+		up = append(up, "--shared-db")
+	}
+	label := "Set up Docker"
+	if plan.env == "local" {
+		label = "Set up local env"
+	}
+	steps = append(steps, createStep{label: label, cmds: [][]string{up}})
+
+	switch plan.db {
+	case "seed":
+		steps = append(steps, createStep{label: "Seed database", cmds: [][]string{
+			{"node", filepath.Join(scripts_dir, "dc-seed.js"), plan.branch},
+		}})
+	case "clone":
+		steps = append(steps, createStep{label: "Clone database", detail: "from " + plan.source_db, cmds: [][]string{
+			{"node", filepath.Join(scripts_dir, "dc-seed.js"), plan.branch, "--from=" + plan.source_db},
+		}})
+	}
+	return steps
+}
+
+// start_create runs a confirmed plan's steps one after another, showing
+// their progress in the notification area.
+func (m Model) start_create(plan createPlan) (Model, tea.Cmd) {
+	if plan.changes == "carry" {
+		stash, err := stash_changes(plan.source)
+		if err != nil {
+			return m.show_notification("New worktree", fmt.Sprintf("Stashing the changes in %s failed: %v", filepath.Base(plan.source), err))
+		}
+		plan.stash = stash
+	}
+	if plan.set == "set" {
+		plan.set_repos = set_repos(m.cfg, plan.branch, plan.from)
+	}
+
+	// Refresh AWS credentials so the setup scripts inherit the latest keys
+	aws.Refresh(m.sso_profile())
+
+	steps := create_steps(plan, m.repo_root, m.worktrees_dir, flow_scripts_dir(m.repo_root, m.cfg))
+	run := &createRun{plan: plan, steps: steps, state: make([]ui.Step, len(steps))}
+	for i, s := range steps {
+		run.state[i] = ui.Step{Label: s.label}
+	}
+	run.state[0].State = ui.StepRunning
+	m.create = run
+	m.create_plan = createPlan{}
+	m.activity = fmt.Sprintf("Creating %s...", plan.branch)
+	m.recalc_layout()
+	return m, cmd_create_step(0, steps[0], m.repo_root)
+}
+
+// cmd_create_step runs a step's commands, stopping at the first that fails.
+func cmd_create_step(index int, step createStep, repo_root string) tea.Cmd {
+	return func() tea.Msg {
+		var out string
+		var err error
+		for _, c := range step.cmds {
+			// WT_INNER keeps dc-worktree-up from starting dev servers; the
+			// dashboard starts them once the worktree is discovered
+			if out, err = create_exec(repo_root, []string{"WT_INNER=1"}, c[0], c[1:]...); err != nil {
+				break
+			}
+		}
+		return MsgCreateStep{Index: index, Output: out, Err: err}
+	}
+}
+
+// done reports whether the run has finished, successfully or not.
+func (r *createRun) done() bool {
+	return r.failed || r.current >= len(r.steps)
+}
+
+// handle_create_step records a finished step and starts the next. A failed
+// step stops the run and its panel stays up until dismissed; a finished run
+// is discovered like a dc-create one.
+func (m Model) handle_create_step(msg MsgCreateStep) (Model, tea.Cmd) {
+	run := m.create
+	if run == nil || run.done() || msg.Index != run.current {
+		return m, nil
+	}
+	state := &run.state[msg.Index]
+	step := run.steps[msg.Index]
+	switch {
+	case msg.Err != nil && step.optional:
+		state.State = ui.StepSkipped
+		state.Detail = first_nonempty(last_line(msg.Output), msg.Err.Error())
+	case msg.Err != nil:
+		debug_log("[create] %s failed: %v\n%s", step.label, msg.Err, msg.Output)
+		state.State = ui.StepFailed
+		state.Detail = first_nonempty(last_line(msg.Output), msg.Err.Error())
+		run.failed = true
+		m.activity = ""
+		return m, nil
+	default:
+		state.State = ui.StepDone
+		state.Detail = step.detail
+	}
+
+	run.current++
+	if run.current < len(run.steps) {
+		run.state[run.current].State = ui.StepRunning
+		return m, cmd_create_step(run.current, run.steps[run.current], m.repo_root)
+	}
+
+	plan := run.plan
+	m.create = nil
+	m.activity = ""
+	if plan.env == "local" {
+		m.pending_dev_alias = plan.alias
+	} else {
+		m.pending_esbuild_alias = plan.alias
+	}
+	m, cmd := m.show_notification("New worktree", fmt.Sprintf("Created %s as %s", plan.branch, plan.alias))
+	return m, tea.Batch(cmd, m.cmd_discover())
+}
+
+// first_nonempty returns the first of values that isn't empty.
+func first_nonempty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// create_title is the title of the quick-create progress panel.
+func (m Model) create_title() string {
+	if m.create.failed {
+		return fmt.Sprintf("Creating %s failed — Esc to close", m.create.plan.branch)
+	}
+	return fmt.Sprintf("Creating %s", m.create.plan.branch)
+}
@@ -0,0 +1,233 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestValidateBranch(t *testing.T) {
+	prefixes := []string{"feat", "fix"}
+	for name, want := range map[string]string{
+		"feat/login":  "",
+		"fix/a_b-1":   "",
+		"feat/":       "Branch must start with feat/, fix/",
+		"chore/x":     "Branch must start with feat/, fix/",
+		"feat/log in": "Branch name contains invalid characters",
+		"":            "Branch name contains invalid characters",
+	} {
+		if got := validate_branch(name, prefixes); got != want {
+			t.Errorf("validate_branch(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if got := validate_branch("anything", nil); got != "" {
+		t.Errorf("no prefixes configured: %q", got)
+	}
+}
+
+func TestAutoAlias(t *testing.T) {
+	prefixes := []string{"feat", "fix"}
+	for branch, want := range map[string]string{
+		"feat/login-page-v2": "login-page",
+		"Fix/Crash":          "crash",
+		"ops/deploy/blue":    "ops-deploy",
+		"feat/a_b":           "a-b",
+	} {
+		if got := auto_alias(branch, prefixes); got != want {
+			t.Errorf("auto_alias(%q) = %q, want %q", branch, got, want)
+		}
+	}
+}
+
+func TestValidateAlias(t *testing.T) {
+	wts := list_model().worktrees
+	if got := validate_alias("login", wts); got != `Alias "login" is already in use by another worktree` {
+		t.Errorf("taken alias: %q", got)
+	}
+	if got := validate_alias("Login", wts); got == "" {
+		t.Error("uppercase alias should be rejected")
+	}
+	if got := validate_alias(strings.Repeat("a", 31), wts); got != "Must be 30 characters or less" {
+		t.Errorf("long alias: %q", got)
+	}
+	if got := validate_alias("signup", wts); got != "" {
+		t.Errorf("free alias: %q", got)
+	}
+}
+
+func TestCreateEntries(t *testing.T) {
+	now := time.Now()
+	branches := []worktree.Branch{
+		{Name: "feat/a", CommitTime: now.Add(-2 * time.Hour)},
+		{Name: "origin/feat/a", Remote: true},
+		{Name: "origin/feat/b", Remote: true, CommitTime: now.Add(-3 * 24 * time.Hour)},
+		{Name: "main"},
+		{Name: "origin/main", Remote: true},
+		{Name: "fix/c"},
+	}
+	entries := create_entries(branches, map[string]bool{"main": true, "fix/c": true}, nil)
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.title+" ("+e.hint+")")
+	}
+	want := "New branch from origin/main (base); New branch from main (base); feat/a (local · 2h ago); " +
+		"origin/feat/b (remote · 3d ago); Interactive wizard (dc-create)"
+	if strings.Join(got, "; ") != want {
+		t.Errorf("entries =\n%s\nwant\n%s", strings.Join(got, "; "), want)
+	}
+	for _, e := range entries {
+		if e.id != "" {
+			t.Errorf("%q has a palette history id", e.title)
+		}
+	}
+}
+
+func TestCreateSteps(t *testing.T) {
+	plan := createPlan{branch: "feat/signup", from: "origin/main", fetch: "origin/main", alias: "signup", env: "local", mode: "full", db: "shared"}
+	var got []string
+	for _, s := range create_steps(plan, "/repo", "/wts", "/flow") {
+		var cmds []string
+		for _, c := range s.cmds {
+			cmds = append(cmds, strings.Join(c, " "))
+		}
+		got = append(got, s.label+": "+strings.Join(cmds, " && "))
+	}
+	want := []string{
+		"Fetch origin/main: git -C /repo fetch origin main",
+		"Add worktree: git -C /repo worktree prune && git -C /repo worktree add -b feat/signup /wts/feat-signup origin/main",
+		"Track origin/feat/signup: git -C /repo config branch.feat/signup.remote origin && git -C /repo config branch.feat/signup.merge refs/heads/feat/signup",
+		"Set up local env: node /flow/dc-worktree-up.js feat/signup --skip-git --alias=signup --mode=full --no-docker --shared-db",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("steps =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A remote branch is checked out tracking it, then set up and seeded
+	plan = createPlan{branch: "feat/b", remote: "origin/feat/b", fetch: "origin/feat/b", alias: "b", env: "host_build", db: "seed"}
+	steps := create_steps(plan, "/repo", "/wts", "/flow")
+	if len(steps) != 4 || strings.Join(steps[1].cmds[1], " ") != "git -C /repo worktree add --track -b feat/b /wts/feat-b origin/feat/b" ||
+		strings.Join(steps[2].cmds[0], " ") != "node /flow/dc-worktree-up.js feat/b --skip-git --alias=b --host-build" ||
+		steps[3].label != "Seed database" {
+		t.Errorf("remote branch steps: %+v", steps)
+	}
+}
+
+// submit_input enters value in the open input and delivers the result.
+func submit_input(t *testing.T, m Model, value string) Model {
+	t.Helper()
+	if !m.input_active {
+		t.Fatalf("no input open for %q", value)
+	}
+	cmd := m.input_callback(value)
+	m.input_active = false
+	result, _ := m.Update(cmd())
+	return result.(Model)
+}
+
+func TestQuickCreateFlow(t *testing.T) {
+	m := list_model()
+	m.repo_root = t.TempDir()
+	m.worktrees_dir = t.TempDir()
+	m.cfg.Docker.ComposeStrategy = "generate"
+	m.cfg.Features.HostBuild = true
+	m.cfg.Services.Modes = map[string][]string{"full": nil, "api": {"api", "worker"}}
+	m.cfg.Services.DefaultMode = "full"
+	m.cfg.Database = &config.DatabaseConfig{Type: "mongodb", Host: "localhost", DefaultDb: "db", DbNamePrefix: "db_", SeedCommand: "seed"}
+
+	// A branch without a configured prefix is asked for again
+	m, _ = m.prompt_new_branch(createPlan{from: "origin/main", fetch: "origin/main"}, "", "")
+	m = submit_input(t, m, "signup")
+	if m.input_prompt != "Branch must start with feat/, fix/ — new branch from origin/main:" || m.input_value != "signup" {
+		t.Fatalf("re-prompt = %q value=%q", m.input_prompt, m.input_value)
+	}
+	m = submit_input(t, m, "feat/signup-form")
+	if m.input_value != "signup-form" {
+		t.Fatalf("alias prefill = %q", m.input_value)
+	}
+	m = submit_input(t, m, "login")
+	if !strings.HasPrefix(m.input_prompt, `Alias "login" is already in use`) {
+		t.Fatalf("alias re-prompt = %q", m.input_prompt)
+	}
+	m = submit_input(t, m, "signup")
+
+	pick := func(context, key string) {
+		t.Helper()
+		if !m.picker_open || m.picker_context != context {
+			t.Fatalf("expected %s picker, open=%v context=%q", context, m.picker_open, m.picker_context)
+		}
+		m.picker_open = false
+		m, _ = m.dispatch_picker(ui.PickerAction{Key: key, Label: map[string]string{"2": "api"}[key]})
+	}
+	pick(pickerCreateEnv, "h")
+	if m.picker_actions[0].Label != "full" {
+		t.Errorf("default mode should come first: %+v", m.picker_actions)
+	}
+	pick(pickerCreateMode, "2")
+	pick(pickerCreateDB, "e")
+	if !m.confirm_open || m.confirm_prompt != "Create feat/signup-form as signup (from origin/main, host build, api, seeded db)?" {
+		t.Fatalf("confirm: open=%v prompt=%q", m.confirm_open, m.confirm_prompt)
+	}
+
+	var ran []string
+	create_exec = func(dir string, env []string, name string, args ...string) (string, error) {
+		ran = append(ran, name+" "+args[len(args)-1])
+		if name == "git" && args[2] == "fetch" {
+			return "fatal: unable to access", errors.New("exit status 128")
+		}
+		return "ok", nil
+	}
+	defer func() { create_exec = run_host_cmd_env_dir }()
+
+	m.confirm_open = false
+	m, cmd := m.confirm_action(&m)
+	for m.create != nil && !m.create.done() {
+		if m.create.state[m.create.current].State != ui.StepRunning {
+			t.Fatalf("step %d isn't shown running", m.create.current)
+		}
+		result, next := m.Update(cmd())
+		m, cmd = result.(Model), next
+		if m.create != nil && m.create.current == 1 && m.create.state[0].State != ui.StepSkipped {
+			t.Errorf("a failed fetch should be skipped, got %+v", m.create.state[0])
+		}
+	}
+	if m.create != nil || m.pending_esbuild_alias != "signup" || !m.notify_open {
+		t.Errorf("after create: run=%v pending=%q notify=%v", m.create, m.pending_esbuild_alias, m.notify_open)
+	}
+	if len(ran) != 7 {
+		t.Errorf("commands run: %v", ran)
+	}
+}
+
+func TestQuickCreateStepFailure(t *testing.T) {
+	m := list_model()
+	create_exec = func(dir string, env []string, name string, args ...string) (string, error) {
+		if name == "node" {
+			return "Installing...\nInvalid service mode: x", errors.New("exit status 1")
+		}
+		return "", nil
+	}
+	defer func() { create_exec = run_host_cmd_env_dir }()
+
+	m, cmd := m.start_create(createPlan{branch: "feat/x", alias: "x", env: "local", mode: "x"})
+	for !m.create.done() {
+		m, cmd = m.handle_create_step(cmd().(MsgCreateStep))
+	}
+	if cmd != nil || !m.create.failed || m.create.state[1].State != ui.StepFailed || m.create.state[1].Detail != "Invalid service mode: x" {
+		t.Fatalf("failed run: %+v", m.create.state)
+	}
+	if m.notify_height() != ui.NotifyHeight(ui.NotifySteps, 2) {
+		t.Errorf("the steps should stay shown: height %d", m.notify_height())
+	}
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m = result.(Model); m.create != nil {
+		t.Error("Esc should dismiss a failed create")
+	}
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/elvisnm/wt/internal/labels"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// git_view_args returns the arguments of a `wt _diff` or `wt _log` view of
+// wt: its path and the base ref from its cached git status, else the
+// configured one.
+func (m Model) git_view_args(command string, wt worktree.Worktree) []string {
+	base := wt.Git.Base
+	if base == "" {
+		base = worktree.ResolveBaseRef(m.repo_root, m.base_refs())
+	}
+	args := []string{command, wt.Path}
+	if base != "" {
+		args = append(args, "--base="+base)
+	}
+	return args
+}
+
+// open_git_view runs a git view of wt (`wt <args>`) in a right-pane tab, or
+// focuses the one already open.
+func (m Model) open_git_view(label string, args []string, wt worktree.Worktree) (Model, tea.Cmd) {
+	w, h := m.right_pane_dimensions()
+	s, err := m.term_mgr.Open(labels.Tab(label, wt.Alias), wt_executable(), args, w, h, wt.Path)
+	if err != nil {
+		m.terminal_output = fmt.Sprintf("Error: %v", err)
+		return m, nil
+	}
+	s.SetWorktree(wt.Alias, wt.Path)
+	m.prev_focus = m.focus
+	m.focus = PanelTerminal
+	if m.pane_layout != nil {
+		m.pane_layout.FocusRight()
+	}
+	return m, tick_after(100*time.Millisecond, "render")
+}
+
+// open_diff shows what wt changed against its base branch.
+func (m Model) open_diff(wt worktree.Worktree) (Model, tea.Cmd) {
+	return m.open_git_view(labels.Diff, m.git_view_args("_diff", wt), wt)
+}
+
+// open_commits browses wt's commits as a graph against its base branch.
+func (m Model) open_commits(wt worktree.Worktree) (Model, tea.Cmd) {
+	return m.open_git_view(labels.Commits, m.git_view_args("_log", wt), wt)
+}
+
+// open_checkpoints browses wt's checkpoints: snapshots of its files kept on
+// hidden refs, to diff, restore or take more.
+func (m Model) open_checkpoints(wt worktree.Worktree) (Model, tea.Cmd) {
+	return m.open_git_view(labels.Checkpoints, []string{"_checkpoints", wt.Path, "--alias=" + wt.Alias}, wt)
+}
+
+// drop_checkpoints deletes a removed worktree's checkpoints.
+func drop_checkpoints(repo_root string, wt worktree.Worktree) {
+	if err := worktree.DeleteCheckpoints(repo_root, wt.Alias); err != nil {
+		debug_log("[checkpoint] deleting the checkpoints of %s: %v", wt.Alias, err)
+	}
+}
+
+// MsgCheckpoint reports the "Before Claude" checkpoint, taken in the
+// background. Claude's tab opens in Wt once it's done, so the checkpoint
+// can't race claude's first edits.
+type MsgCheckpoint struct {
+	Wt         worktree.Worktree
+	AutoMode   bool
+	Checkpoint worktree.Checkpoint
+	Err        error
+}
+
+// cmd_claude_checkpoint checkpoints wt's files before Claude opens in it.
+func cmd_claude_checkpoint(wt worktree.Worktree, auto_mode bool) tea.Cmd {
+	return func() tea.Msg {
+		c, err := worktree.CreateCheckpoint(wt.Path, wt.Alias, "Before Claude")
+		return MsgCheckpoint{Wt: wt, AutoMode: auto_mode, Checkpoint: c, Err: err}
+	}
+}
+
+// handle_checkpoint opens Claude once its checkpoint is taken. A failed
+// checkpoint is reported, but doesn't keep Claude from opening.
+func (m Model) handle_checkpoint(msg MsgCheckpoint) (Model, tea.Cmd) {
+	m.activity = ""
+	m, cmd := m.launch_claude(msg.Wt, msg.AutoMode)
+	if msg.Err != nil {
+		var note tea.Cmd
+		m, note = m.show_notification("Checkpoint", fmt.Sprintf("Checkpointing %s failed: %v", msg.Wt.Alias, msg.Err))
+		return m, tea.Batch(cmd, note)
+	}
+	debug_log("[checkpoint] %s #%d %s", msg.Wt.Alias, msg.Checkpoint.N, msg.Checkpoint.Short())
+	return m, cmd
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/elvisnm/wt/internal/terminal"
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+func TestClaudeCheckpointFirst(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "-q", "-b", "main").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	m := list_model()
+	m.term_mgr = terminal.NewManager()
+	m.claude_checkpoint = true
+	wt := worktree.Worktree{Path: dir, Name: "api", Alias: "api"}
+	m, cmd := m.open_claude_with_flags(wt, false)
+	if m.term_mgr.Count() != 0 || cmd == nil {
+		t.Fatal("Claude's tab should wait for the checkpoint")
+	}
+
+	msg, ok := cmd().(MsgCheckpoint)
+	if !ok || msg.Err != nil || msg.Wt.Path != dir {
+		t.Fatalf("checkpoint: %+v", msg)
+	}
+	if list, err := worktree.ListCheckpoints(dir, "api"); err != nil || len(list) != 1 || list[0].Message != "Before Claude" {
+		t.Errorf("checkpoints = %+v, %v", list, err)
+	}
+}
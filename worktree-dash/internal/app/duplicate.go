@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stash_changes records a worktree's staged and unstaged changes as a stash
+// commit without touching its working tree or stash list. Untracked files
+// aren't included. Returns "" when there is nothing to stash.
+func stash_changes(worktree_path string) (string, error) {
+	out, err := exec.Command("git", "-C", worktree_path, "stash", "create", "wt duplicate").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s", last_line(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// duplicate_plan starts a quick-create plan for a new branch at wt's
+// commit, set up like wt: same environment and service mode, and its
+// isolated database offered to clone.
+func (m Model) duplicate_plan(wt worktree.Worktree) createPlan {
+	plan := createPlan{from: wt.Branch, mode: wt.Mode, source: wt.Path, env: "docker"}
+	switch {
+	case wt.Type == worktree.TypeLocal:
+		plan.env = "local"
+	case wt.HostBuild:
+		plan.env = "host_build"
+	}
+	if has_local_db(m.cfg) && wt.DBName != "" && wt.DBName != m.cfg.Database.DefaultDb {
+		plan.source_db = wt.DBName
+	}
+	if s := wt.Git; s.Known() && s.Staged+s.Unstaged == 0 {
+		plan.changes = "leave"
+	}
+	if len(wt.Sparse) > 0 {
+		plan.sparse, plan.sparse_dirs = wt.SparseProfile, wt.Sparse
+	} else {
+		plan.sparse = "full"
+	}
+	return plan
+}
+
+// open_duplicate forks a worktree: it asks for the new branch's name and
+// alias, then goes through quick create with the source's settings.
+func (m Model) open_duplicate(wt worktree.Worktree) (Model, tea.Cmd) {
+	if m.create != nil && !m.create.done() {
+		return m.show_notification("Duplicate", fmt.Sprintf("Still creating %s", m.create.plan.branch))
+	}
+	m.create = nil
+	return m.prompt_new_branch(m.duplicate_plan(wt), wt.Branch+"-2", "")
+}
+
+// open_changes_picker asks whether the source's uncommitted changes come
+// along to the duplicate.
+func (m Model) open_changes_picker() (Model, tea.Cmd) {
+	desc := "Staged and unstaged changes, not untracked files"
+	for _, wt := range m.worktrees {
+		if s := wt.Git; wt.Path == m.create_plan.source && s.Known() {
+			desc = fmt.Sprintf("%d staged, %d unstaged (not untracked files)", s.Staged, s.Unstaged)
+		}
+	}
+	return m.open_panel_picker("Uncommitted changes", []ui.PickerAction{
+		{Key: "c", Label: "Carry them over", Desc: desc},
+		{Key: "l", Label: "Leave them", Desc: "Start from the last commit"},
+	}, pickerCreateChanges)
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+func TestDuplicateFlow(t *testing.T) {
+	m := list_model()
+	m.repo_root = t.TempDir()
+	m.worktrees_dir = t.TempDir()
+	m.cfg.Docker.ComposeStrategy = "generate"
+	m.cfg.Database = &config.DatabaseConfig{Type: "mongodb", Host: "localhost", DefaultDb: "db", DbNamePrefix: "db_"}
+	m.cfg.Paths.FlowScripts = "/flow"
+	login := &m.worktrees[1]
+	login.HostBuild, login.Mode, login.DBName = true, "api", "db_login"
+	login.Git = worktree.GitStatus{Staged: 1, Unstaged: 2, Untracked: 4, Fetched: time.Now()}
+
+	m, _ = m.open_duplicate(*login)
+	if m.input_prompt != "New branch from feat/login:" || m.input_value != "feat/login-2" {
+		t.Fatalf("branch prompt = %q value=%q", m.input_prompt, m.input_value)
+	}
+	m = submit_input(t, m, "feat/login-v2")
+	m = submit_input(t, m, "login-v2")
+
+	pick := func(context, key string) {
+		t.Helper()
+		if !m.picker_open || m.picker_context != context {
+			t.Fatalf("expected %s picker, open=%v context=%q", context, m.picker_open, m.picker_context)
+		}
+		m.picker_open = false
+		m, _ = m.dispatch_picker(ui.PickerAction{Key: key})
+	}
+	// The environment and mode come from the source, so the database is asked first
+	if m.picker_actions[0].Desc != "Copy of db_login" {
+		t.Errorf("clone should be offered first: %+v", m.picker_actions)
+	}
+	pick(pickerCreateDB, "c")
+	if m.picker_actions[0].Desc != "1 staged, 2 unstaged (not untracked files)" {
+		t.Errorf("changes picker: %+v", m.picker_actions)
+	}
+	pick(pickerCreateChanges, "c")
+	if !m.confirm_open || m.confirm_prompt != "Create feat/login-v2 as login-v2 (from feat/login, host build, api, cloned db, uncommitted changes)?" {
+		t.Fatalf("confirm: open=%v prompt=%q", m.confirm_open, m.confirm_prompt)
+	}
+
+	plan := m.create_plan
+	plan.stash = "abc123"
+	var got []string
+	for _, s := range create_steps(plan, "/repo", "/wts", "/flow") {
+		got = append(got, s.label+": "+strings.Join(s.cmds[len(s.cmds)-1], " "))
+	}
+	want := []string{
+		"Add worktree: git -C /repo worktree add -b feat/login-v2 /wts/feat-login-v2 feat/login",
+		"Apply uncommitted changes: git -C /wts/feat-login-v2 stash apply abc123",
+		"Track origin/feat/login-v2: git -C /repo config branch.feat/login-v2.merge refs/heads/feat/login-v2",
+		"Set up Docker: node /flow/dc-worktree-up.js feat/login-v2 --skip-git --alias=login-v2 --mode=api --host-build",
+		"Clone database: node /flow/dc-seed.js feat/login-v2 --from=db_login",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("steps =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A clean worktree on the shared database goes straight to the confirm
+	crash := m.worktrees[2]
+	crash.Git = worktree.GitStatus{Untracked: 1, Fetched: time.Now()}
+	if plan := m.duplicate_plan(crash); plan.env != "local" || plan.source_db != "" || plan.changes != "leave" {
+		t.Errorf("clean local plan: %+v", plan)
+	}
+}
+
+func TestStashChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Sam", "GIT_AUTHOR_EMAIL=sam@example.com",
+			"GIT_COMMITTER_NAME=Sam", "GIT_COMMITTER_EMAIL=sam@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	file := filepath.Join(dir, "a.txt")
+	git("init", "-q")
+	os.WriteFile(file, []byte("one\n"), 0644)
+	git("add", "a.txt")
+	git("commit", "-qm", "one")
+
+	if stash, err := stash_changes(dir); err != nil || stash != "" {
+		t.Errorf("clean worktree: %q, %v", stash, err)
+	}
+	os.WriteFile(file, []byte("two\n"), 0644)
+	stash, err := stash_changes(dir)
+	if err != nil || stash == "" {
+		t.Fatalf("dirty worktree: %q, %v", stash, err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "two\n" || git("stash", "list") != "" {
+		t.Errorf("the worktree and stash list should be untouched: %q", data)
+	}
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elvisnm/wt/internal/labels"
+	"github.com/elvisnm/wt/internal/settings"
+	"github.com/elvisnm/wt/internal/terminal"
+	"github.com/elvisnm/wt/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// label_type returns the prefix of a tab label ("Logs — api" → "Logs").
+func label_type(label string) string {
+	prefix, _, _ := strings.Cut(label, labels.Sep)
+	return prefix
+}
+
+// close_on_exit applies the configured exit policy to a finished session.
+// Create and Settings tabs are closed by their own sentinel handlers.
+func (m Model) close_on_exit(label string, exit_code int) bool {
+	t := label_type(label)
+	if t == labels.Create || t == labels.Settings {
+		return false
+	}
+	switch m.exit_policies[t] {
+	case settings.ExitClose:
+		return true
+	case settings.ExitCloseOnSuccess:
+		return exit_code == 0
+	}
+	return false
+}
+
+// session_by_id finds a session across all groups.
+func (m Model) session_by_id(id int) *terminal.Session {
+	for _, s := range m.term_mgr.Sessions() {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// open_finished_picker offers rerun/edit/close for the finished session under the cursor.
+func (m Model) open_finished_picker() (tea.Model, tea.Cmd) {
+	s := m.cursor_session()
+	if s == nil {
+		return m, nil
+	}
+	if s.IsAlive() {
+		return m.show_notification("Rerun", s.Label+" is still running")
+	}
+	m.finished_session_id = s.ID
+	actions := []ui.PickerAction{
+		{Key: "r", Label: "Rerun", Desc: s.CommandLine()},
+		{Key: "e", Label: "Edit & rerun", Desc: "change the command first"},
+		{Key: "x", Label: "Close", Desc: "close the tab"},
+	}
+	return m.open_panel_picker("Finished", actions, pickerFinished)
+}
+
+// finished_title describes the finished session for the picker title.
+func (m Model) finished_title() string {
+	s := m.session_by_id(m.finished_session_id)
+	if s == nil {
+		return "Finished"
+	}
+	for _, l := range m.term_mgr.TabLabels() {
+		if l.SessionID == s.ID {
+			return fmt.Sprintf("%s — %s", s.Label, ui.ExitSummary(l.ExitCode, l.Runtime))
+		}
+	}
+	return s.Label
+}
+
+// execute_finished_action reruns, edits or closes the finished session.
+func (m Model) execute_finished_action(action ui.PickerAction) (Model, tea.Cmd) {
+	s := m.session_by_id(m.finished_session_id)
+	if s == nil {
+		return m, nil
+	}
+	switch action.Key {
+	case "r":
+		s.Rerun()
+		return m, tick_after(100*time.Millisecond, "render")
+	case "e":
+		id := s.ID
+		m2, cmd := m.open_panel_input("Rerun", "Command:", func(mdl *Model, val string) (Model, tea.Cmd) {
+			line := strings.TrimSpace(val)
+			sess := mdl.session_by_id(id)
+			if line == "" || sess == nil {
+				return *mdl, nil
+			}
+			sess.RerunLine(line)
+			return *mdl, tick_after(100*time.Millisecond, "render")
+		})
+		m2.input_value = s.CommandLine()
+		return m2, cmd
+	case "x":
+		m.term_mgr.CloseBySessionID(s.ID)
+		m.focus_worktrees_if_empty()
+		m.sync_tab_cursor_from_active()
+	}
+	return m, nil
+}
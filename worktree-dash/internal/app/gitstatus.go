@@ -0,0 +1,168 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gitStatusInterval limits how often git status runs over every worktree.
+const gitStatusInterval = 30 * time.Second
+
+// gitStatusParallelism is how many worktrees are read at once.
+const gitStatusParallelism = 4
+
+// MsgGitStatus carries git status by worktree path. Full is set for a pass
+// over every worktree, which replaces the cache; otherwise entries are merged.
+type MsgGitStatus struct {
+	Status map[string]worktree.GitStatus
+	Full   bool
+}
+
+// base_refs returns repo.baseRefs from the config.
+func (m Model) base_refs() []string {
+	if m.cfg == nil {
+		return nil
+	}
+	return m.cfg.Repo.BaseRefs
+}
+
+// apply_git_status copies the cached git status onto the worktrees, and
+// the overlaps found in it. Upstreams seen on the remote are saved to the
+// repo state, so a missing one later counts as gone (see GitStatus.MarkGone).
+func (m *Model) apply_git_status() {
+	pushed := false
+	for i := range m.worktrees {
+		wt := &m.worktrees[i]
+		wt.Git = m.git_status[wt.Path]
+		if wt.Git.Upstream != "" && !wt.Git.UpstreamMissing {
+			pushed = m.repo_state.SetPushed(wt.Name, wt.Git.Upstream) || pushed
+		}
+		wt.Git.MarkGone(m.repo_state.PushedTo(wt.Name))
+	}
+	if pushed {
+		if err := state.Save(m.repo_root, m.repo_state); err != nil {
+			debug_log("[git] saving pushed upstreams: %v", err)
+		}
+	}
+	m.apply_overlaps()
+}
+
+// cmd_refresh_git_status reads git status for every worktree in the
+// background, at most once per gitStatusInterval.
+func (m *Model) cmd_refresh_git_status() tea.Cmd {
+	if m.git_fetching || time.Since(m.git_fetched) < gitStatusInterval || len(m.worktrees) == 0 {
+		return nil
+	}
+	m.git_fetching = true
+	paths := make([]string, len(m.worktrees))
+	for i, wt := range m.worktrees {
+		paths[i] = wt.Path
+	}
+	return cmd_read_git_status(m.repo_root, m.base_refs(), paths, true)
+}
+
+// cmd_read_git_status reads git status for the given worktrees,
+// gitStatusParallelism at a time.
+func cmd_read_git_status(repo_root string, base_refs, paths []string, full bool) tea.Cmd {
+	return func() tea.Msg {
+		base := worktree.ResolveBaseRef(repo_root, base_refs)
+		results := make([]worktree.GitStatus, len(paths))
+		var wg sync.WaitGroup
+		for w := 0; w < gitStatusParallelism && w < len(paths); w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := w; i < len(paths); i += gitStatusParallelism {
+					results[i] = worktree.ReadGitStatus(paths[i], base)
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		status := make(map[string]worktree.GitStatus, len(paths))
+		for i, p := range paths {
+			status[p] = results[i]
+		}
+		return MsgGitStatus{Status: status, Full: full}
+	}
+}
+
+// handle_git_status stores git status, keeping the selection. A single
+// worktree's refresh also updates the remove picker if it's waiting on it.
+func (m Model) handle_git_status(msg MsgGitStatus) (tea.Model, tea.Cmd) {
+	if msg.Full {
+		m.git_status = msg.Status
+		m.git_fetched = time.Now()
+		m.git_fetching = false
+	} else {
+		if m.git_status == nil {
+			m.git_status = map[string]worktree.GitStatus{}
+		}
+		for path, s := range msg.Status {
+			m.git_status[path] = s
+		}
+	}
+	selected := m.selected_name()
+	m.apply_git_status()
+	m.clear_resolved_conflicts()
+	m, cmd := m.relist(selected)
+	if m.picker_open && m.picker_context == pickerRemove {
+		if wt := m.selected_worktree(); wt != nil {
+			m.picker_actions = remove_actions(*wt)
+		}
+	}
+	return m, cmd
+}
+
+// remove_warning describes what removing a worktree would lose or leave
+// behind, or "" when it's clean and pushed. Locked worktrees can't be
+// removed until unlocked.
+func remove_warning(wt worktree.Worktree) string {
+	var parts []string
+	if wt.Locked {
+		parts = append(parts, "locked")
+	}
+	s := wt.Git
+	if n := s.Changes(); n > 0 {
+		parts = append(parts, plural(n, "uncommitted file"))
+	}
+	if n := s.Unpushed(); n > 0 {
+		parts = append(parts, plural(n, "unpushed commit"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// remove_actions returns the remove picker, warning about uncommitted files
+// (lost by a force remove) and unpushed commits (left only on the local branch).
+// A worktree set's members are named, as they're removed too.
+func remove_actions(wt worktree.Worktree) []ui.PickerAction {
+	actions := append([]ui.PickerAction(nil), ui.RemoveActions...)
+	var notes []string
+	if len(wt.Set) > 0 {
+		notes = append(notes, "with "+strings.Join(wt.SetNames(), ", "))
+	}
+	if warning := remove_warning(wt); warning != "" {
+		notes = append(notes, warning)
+	}
+	for i := range actions {
+		for _, note := range notes {
+			actions[i].Desc += " · " + note
+		}
+	}
+	return actions
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
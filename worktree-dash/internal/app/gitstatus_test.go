@@ -0,0 +1,38 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+func TestRemovePickerWarnsFromGitStatus(t *testing.T) {
+	m := list_model()
+	m.cursor = 2
+	m, cmd := m.remove_worktree(m.worktrees[2])
+	if cmd == nil {
+		t.Fatal("opening remove should re-read the worktree's git status")
+	}
+	if m.picker_actions[0].Desc != "Fails if dirty" {
+		t.Errorf("no status yet: %q", m.picker_actions[0].Desc)
+	}
+
+	// A fresh read for one worktree is merged and updates the open picker
+	result, _ := m.Update(MsgGitStatus{Status: map[string]worktree.GitStatus{
+		"/wt/fix-crash": {Unstaged: 2, Upstream: "origin/fix/crash", Ahead: 1, Fetched: time.Now()},
+	}})
+	m = result.(Model)
+	if got := m.picker_actions[1].Desc; got != "Even if dirty · 2 uncommitted files, 1 unpushed commit" {
+		t.Errorf("force desc = %q", got)
+	}
+	if !m.worktrees[2].Git.Dirty() || m.git_fetching || !m.git_fetched.IsZero() {
+		t.Error("a single read should be applied without counting as a full pass")
+	}
+
+	// Discovery keeps the cached status on the rediscovered worktrees
+	m.update_worktrees(list_model().worktrees)
+	if m.worktrees[2].Git.Unstaged != 2 {
+		t.Error("git status lost after discovery")
+	}
+}
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/settings"
+	"github.com/elvisnm/wt/internal/terminal"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Key scopes: sets of keys handled together, where one key can only mean one thing.
+// Dashboard-wide keys are checked before any panel handler, so they share a
+// scope with every panel and with the worktree action picker.
+const (
+	scopeGlobal   = "global"
+	scopeWorktree = "worktree" // quick keys in the worktrees panel
+	scopePicker   = "picker"   // keys in the worktree action picker
+	scopeTerminal = "terminal" // active tabs panel
+	scopeTmuxRoot = "tmux-root"
+	scopeTmux     = "tmux" // keys after the tmux prefix
+)
+
+var dashboard_scopes = []string{scopeGlobal, scopeWorktree, scopePicker, scopeTerminal}
+
+// MsgSettingsProblems reports user settings that were ignored at load time.
+type MsgSettingsProblems struct {
+	Title    string
+	Problems []string
+}
+
+// default_keys is Keys before user keybindings are applied.
+var default_keys = Keys
+
+// keymap_bindings maps keybinding IDs to the Keys fields they configure.
+func keymap_bindings(km *KeyMap) map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":              &km.Up,
+		"down":            &km.Down,
+		"page_up":         &km.PageUp,
+		"page_down":       &km.PageDown,
+		"enter":           &km.Enter,
+		"tab":             &km.Tab,
+		"shift_tab":       &km.ShiftTab,
+		"escape":          &km.Escape,
+		"quit":            &km.Quit,
+		"help":            &km.Help,
+		"panel_left":      &km.PanelLeft,
+		"panel_right":     &km.PanelRight,
+		"tab_prev":        &km.TabPrev,
+		"tab_next":        &km.TabNext,
+		"palette":         &km.Palette,
+		"focus_tabs":      &km.FocusTabs,
+		"focus_worktrees": &km.FocusWorktrees,
+		"focus_services":  &km.FocusServices,
+		"aws":             &km.Aws,
+		"database":        &km.Database,
+		"details":         &km.Details,
+		"admin":           &km.Admin,
+		"lan":             &km.Lan,
+		"maintenance":     &km.Maintenance,
+		"skip_worktree":   &km.SkipWorktree,
+		"usage":           &km.Usage,
+		"tasks":           &km.Tasks,
+		"search":          &km.Search,
+		"filter":          &km.Filter,
+		"list_view":       &km.ListView,
+		"mark":            &km.Mark,
+		"mark_all":        &km.MarkAll,
+	}
+}
+
+// worktree_panel_keys are keymap IDs only handled in the worktrees panel.
+var worktree_panel_keys = map[string]bool{"filter": true, "list_view": true, "mark": true, "mark_all": true}
+
+// tmux keybinding IDs and their defaults, in bubbletea key syntax.
+var tmux_defaults = map[string]string{
+	"tmux.prefix":     "ctrl+]",
+	"tmux.dashboard":  "q",
+	"tmux.fullscreen": "f",
+	"tmux.palette":    "p",
+}
+
+// key_defs lists every remappable action with its default keys and scopes,
+// plus fixed keys that remapped actions must not collide with.
+func key_defs() []settings.KeyDef {
+	var defs []settings.KeyDef
+	for id, b := range keymap_bindings(&default_keys) {
+		scopes := dashboard_scopes
+		if worktree_panel_keys[id] {
+			scopes = []string{scopeWorktree}
+		}
+		defs = append(defs, settings.KeyDef{ID: id, Keys: b.Keys(), Scopes: scopes})
+	}
+	for _, a := range worktree_actions {
+		var scopes []string
+		if a.quick != nil {
+			scopes = append(scopes, scopeWorktree)
+		}
+		if a.picker != nil {
+			scopes = append(scopes, scopePicker)
+		}
+		defs = append(defs, settings.KeyDef{
+			ID:     "worktree." + a.id,
+			Keys:   []string{worktree_default_keys[a.id]},
+			Scopes: scopes,
+		})
+	}
+	for id, k := range tmux_defaults {
+		scope := scopeTmux
+		if id == "tmux.prefix" {
+			scope = scopeTmuxRoot
+		}
+		defs = append(defs, settings.KeyDef{ID: id, Keys: []string{k}, Scopes: []string{scope}})
+	}
+
+	// Fixed keys: tab jumps 1-9, Ctrl+C, S (settings), H, the active tabs panel
+	// keys and tmux's own root binding for Ctrl+K
+	reserved := append(strings.Split("123456789", ""), "ctrl+c", "S", "H")
+	defs = append(defs,
+		settings.KeyDef{ID: "reserved", Keys: reserved, Scopes: dashboard_scopes, Fixed: true},
+		settings.KeyDef{ID: "active tabs", Keys: tab_panel_keys(), Scopes: []string{scopeTerminal}, Fixed: true},
+		settings.KeyDef{ID: "tmux tab jumps", Keys: strings.Split("123456789", ""), Scopes: []string{scopeTmux}, Fixed: true},
+		settings.KeyDef{ID: "tmux ctrl+k", Keys: []string{"ctrl+k"}, Scopes: []string{scopeTmuxRoot}, Fixed: true},
+	)
+	sort.Slice(defs, func(i, j int) bool { return defs[i].ID < defs[j].ID })
+	return defs
+}
+
+// ApplyKeybindings resolves the user's keybindings against the defaults and
+// applies them to Keys and the worktree action registry. It returns the tmux
+// bindings to configure and a description of every rejected override.
+// Calling it again starts from the defaults, so reloads don't accumulate.
+func ApplyKeybindings(overrides map[string]settings.KeyList) (terminal.TmuxBindings, []string) {
+	effective, problems := settings.ResolveKeys(key_defs(), overrides)
+
+	Keys = default_keys
+	for id, b := range keymap_bindings(&Keys) {
+		keys := effective[id]
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+	for i := range worktree_actions {
+		// Picker entries show a single key, so only the first one is used
+		worktree_actions[i].key = effective["worktree."+worktree_actions[i].id][0]
+	}
+
+	tmux := terminal.DefaultTmuxBindings()
+	tmux.Prefix = tmux_key(effective["tmux.prefix"][0])
+	tmux.Dashboard = tmux_key(effective["tmux.dashboard"][0])
+	tmux.Fullscreen = tmux_key(effective["tmux.fullscreen"][0])
+	tmux.Palette = tmux_key(effective["tmux.palette"][0])
+	tmux.PaletteKey = tmux_key(effective["palette"][0])
+	return tmux, problems
+}
+
+// cmd_settings_problems reports ignored settings once the UI is up.
+func cmd_settings_problems(title string, problems []string) tea.Cmd {
+	if len(problems) == 0 {
+		return nil
+	}
+	return func() tea.Msg { return MsgSettingsProblems{Title: title, Problems: problems} }
+}
+
+// tmux_key converts a bubbletea key name ("ctrl+]", "alt+x") to tmux syntax
+// ("C-]", "M-x"). Keys already in tmux syntax pass through.
+func tmux_key(k string) string {
+	for _, p := range []struct{ from, to string }{{"ctrl+", "C-"}, {"alt+", "M-"}, {"shift+", "S-"}} {
+		if strings.HasPrefix(k, p.from) {
+			return p.to + tmux_key(strings.TrimPrefix(k, p.from))
+		}
+	}
+	switch k {
+	case "esc":
+		return "Escape"
+	case "enter":
+		return "Enter"
+	case "tab":
+		return "Tab"
+	case "space", " ":
+		return "Space"
+	}
+	return k
+}
+
+// DisplayKey formats a key for the help page: "W" → "Shift+W",
+// "ctrl+p" → "Ctrl+P", "esc" → "Esc", " " → "Space".
+func DisplayKey(k string) string {
+	if k == " " {
+		return "Space"
+	}
+	if r := []rune(k); len(r) == 1 && r[0] >= 'A' && r[0] <= 'Z' {
+		return "Shift+" + k
+	}
+	parts := strings.Split(k, "+")
+	for i, p := range parts {
+		if len(p) > 1 || i == len(parts)-1 && i > 0 {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+// arrow_keys are left out of KeyHelp when an action has other keys too.
+var arrow_keys = map[string]bool{
+	"up": true, "down": true, "left": true, "right": true,
+	"pgup": true, "pgdown": true, "shift+up": true, "shift+down": true,
+}
+
+// KeyHelp returns the current keys for a keybinding ID, formatted for the
+// help page and joined with " / ".
+func KeyHelp(id string) string {
+	var keys []string
+	if b, ok := keymap_bindings(&Keys)[id]; ok {
+		keys = b.Keys()
+	} else if strings.HasPrefix(id, "worktree.") {
+		if a, ok := find_worktree_action_by_id(strings.TrimPrefix(id, "worktree.")); ok {
+			keys = []string{a.key}
+		}
+	}
+	var shown []string
+	for _, k := range keys {
+		if !arrow_keys[k] {
+			shown = append(shown, DisplayKey(k))
+		}
+	}
+	if len(shown) == 0 {
+		for _, k := range keys {
+			shown = append(shown, DisplayKey(k))
+		}
+	}
+	return strings.Join(shown, " / ")
+}
+
+// TmuxKeyHelp formats a tmux key for the help page ("C-]" → "Ctrl+]").
+func TmuxKeyHelp(k string) string {
+	for _, p := range []struct{ from, to string }{{"C-", "Ctrl+"}, {"M-", "Alt+"}, {"S-", "Shift+"}} {
+		if strings.HasPrefix(k, p.from) {
+			return p.to + TmuxKeyHelp(strings.TrimPrefix(k, p.from))
+		}
+	}
+	return k
+}
+
+// find_worktree_action_by_id looks up a registry entry by its keybinding ID.
+func find_worktree_action_by_id(id string) (worktreeAction, bool) {
+	for _, a := range worktree_actions {
+		if a.id == id {
+			return a, true
+		}
+	}
+	return worktreeAction{}, false
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/elvisnm/wt/internal/settings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDefaultKeybindingsNoProblems(t *testing.T) {
+	tmux, problems := ApplyKeybindings(nil)
+	if len(problems) != 0 {
+		t.Errorf("default keybindings conflict: %v", problems)
+	}
+	if tmux.Prefix != "C-]" || tmux.Dashboard != "q" || tmux.PaletteKey != ":" {
+		t.Errorf("default tmux bindings = %+v", tmux)
+	}
+}
+
+func TestApplyKeybindings(t *testing.T) {
+	defer ApplyKeybindings(nil)
+
+	tmux, problems := ApplyKeybindings(map[string]settings.KeyList{
+		"quit":           {"Q"},
+		"worktree.shell": {"y"},
+		"tmux.prefix":    {"ctrl+a"},
+		"worktree.pull":  {"Q"}, // quit is global, so this conflicts
+	})
+	if len(problems) != 2 {
+		t.Errorf("problems = %v, want the quit/pull conflict reported for both", problems)
+	}
+	if tmux.Prefix != "C-a" {
+		t.Errorf("tmux prefix = %q, want C-a", tmux.Prefix)
+	}
+	q := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}
+	if !key.Matches(q, Keys.Quit) {
+		t.Error("quit should keep q after its override conflicted")
+	}
+	if a, _ := find_worktree_action_by_id("shell"); a.key != "y" {
+		t.Errorf("shell key = %q, want y", a.key)
+	}
+	if got := KeyHelp("worktree.shell"); got != "y" {
+		t.Errorf("KeyHelp(worktree.shell) = %q", got)
+	}
+
+	// Reapplying starts from defaults
+	ApplyKeybindings(nil)
+	if a, _ := find_worktree_action_by_id("shell"); a.key != "b" {
+		t.Errorf("shell key after reset = %q, want b", a.key)
+	}
+}
+
+func TestDisplayKey(t *testing.T) {
+	cases := map[string]string{
+		"W":      "Shift+W",
+		"b":      "b",
+		"ctrl+p": "Ctrl+P",
+		"esc":    "Esc",
+		":":      ":",
+	}
+	for in, want := range cases {
+		if got := DisplayKey(in); got != want {
+			t.Errorf("DisplayKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := KeyHelp("up"); got != "k" {
+		t.Errorf("KeyHelp(up) = %q, want k", got)
+	}
+}
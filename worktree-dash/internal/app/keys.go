@@ -18,6 +18,30 @@ type KeyMap struct {
 	PanelRight key.Binding
 	TabPrev    key.Binding
 	TabNext    key.Binding
+	Palette    key.Binding
+
+	// Panel jumps
+	FocusTabs      key.Binding
+	FocusWorktrees key.Binding
+	FocusServices  key.Binding
+
+	// Global operations
+	Aws          key.Binding
+	Database     key.Binding
+	Details      key.Binding
+	Admin        key.Binding
+	Lan          key.Binding
+	Maintenance  key.Binding
+	SkipWorktree key.Binding
+	Usage        key.Binding
+	Tasks        key.Binding
+	Search       key.Binding
+
+	// Worktree list
+	Filter   key.Binding
+	ListView key.Binding
+	Mark     key.Binding
+	MarkAll  key.Binding
 }
 
 var Keys = KeyMap{
@@ -80,4 +104,76 @@ var Keys = KeyMap{
 		key.WithKeys(">"),
 		key.WithHelp(">", "next panel"),
 	),
+	Palette: key.NewBinding(
+		key.WithKeys(":", "ctrl+p"),
+		key.WithHelp(":/ctrl+p", "command palette"),
+	),
+	FocusTabs: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "active tabs"),
+	),
+	FocusWorktrees: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "worktrees"),
+	),
+	FocusServices: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "services"),
+	),
+	Aws: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "AWS keys"),
+	),
+	Database: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "database picker"),
+	),
+	Details: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "details toggle"),
+	),
+	Admin: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "admin toggle"),
+	),
+	Lan: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "LAN toggle"),
+	),
+	Maintenance: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "maintenance"),
+	),
+	SkipWorktree: key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K", "skip-worktree"),
+	),
+	Usage: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "Claude usage"),
+	),
+	Tasks: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "tasks"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "search scrollback"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter worktrees"),
+	),
+	ListView: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filters & sort"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "mark worktree"),
+	),
+	MarkAll: key.NewBinding(
+		key.WithKeys("*"),
+		key.WithHelp("*", "mark all listed"),
+	),
 }
@@ -2,14 +2,34 @@ package app
 
 // Picker context constants (app-internal state).
 const (
-	pickerWorktree     = "worktree"
-	pickerDB           = "db"
-	pickerMaintenance  = "maintenance"
-	pickerRemove       = "remove"
-	pickerStartService = "start_service"
-	pickerStopService  = "stop_service"
-	pickerSplitH       = "split_h"
-	pickerSplitV       = "split_v"
-	pickerMergeTarget  = "merge_target"
-	pickerMergeDir     = "merge_dir"
+	pickerWorktree      = "worktree"
+	pickerDB            = "db"
+	pickerMaintenance   = "maintenance"
+	pickerRemove        = "remove"
+	pickerStartService  = "start_service"
+	pickerStopService   = "stop_service"
+	pickerSplitH        = "split_h"
+	pickerSplitV        = "split_v"
+	pickerMergeTarget   = "merge_target"
+	pickerMergeDir      = "merge_dir"
+	pickerCapture       = "capture"
+	pickerSearch        = "search"
+	pickerRecordings    = "recordings"
+	pickerTemplate      = "template"
+	pickerBroadcast     = "broadcast"
+	pickerFinished      = "finished"
+	pickerListView      = "list_view"
+	pickerBulk          = "bulk"
+	pickerBulkResult    = "bulk_result"
+	pickerNote          = "note"
+	pickerCleanup       = "cleanup"
+	pickerOverlaps      = "overlaps"
+	pickerCreateEnv     = "create_env"
+	pickerCreateMode    = "create_mode"
+	pickerCreateDB      = "create_db"
+	pickerCreateChanges = "create_changes"
+	pickerCreateSet     = "create_set"
+	pickerCreateSparse  = "create_sparse"
+	pickerSparse        = "sparse"
+	pickerRename        = "rename"
 )
@@ -134,7 +134,10 @@ func (m Model) start_dev_server(wt worktree.Worktree) (Model, tea.Cmd) {
 // stop_dev_server stops PM2 services for a local worktree (with confirmation)
 func (m Model) stop_dev_server(wt worktree.Worktree) (Model, tea.Cmd) {
 	return m.open_panel_confirm("Stop", fmt.Sprintf("Stop dev server on %s?", wt.Alias),
-		func(mdl *Model) (Model, tea.Cmd) { return mdl.run_stop_dev_server(wt) })
+		func(mdl *Model) (Model, tea.Cmd) {
+			next, cmd := mdl.run_stop_dev_server(wt)
+			return next.stop_set(wt, cmd)
+		})
 }
 
 func (m Model) run_stop_dev_server(wt worktree.Worktree) (Model, tea.Cmd) {
@@ -243,7 +246,11 @@ func (m Model) is_static_local(wt worktree.Worktree) bool {
 }
 
 // start_worktree dispatches to the appropriate start method based on worktree type.
+// The start is recorded for stale detection (see cleanup.go). Set members
+// with a start command are started too.
 func (m Model) start_worktree(wt worktree.Worktree) (Model, tea.Cmd) {
+	m.record_start(wt)
+	m.start_set(wt)
 	if wt.Type == worktree.TypeLocal {
 		return m.start_dev_server(wt)
 	}
@@ -336,17 +343,19 @@ func (m Model) stop_host_build(wt worktree.Worktree) (Model, tea.Cmd) {
 		m.focus = PanelWorktrees
 	}
 
-	return m, cmd_docker_action("stop", wt, m.repo_root, m.cfg)
+	return m.stop_set(wt, cmd_docker_action("stop", wt, m.repo_root, m.cfg))
 }
 
-// remove_worktree opens a picker to choose removal mode
+// remove_worktree opens a picker to choose removal mode. The picker warns
+// about uncommitted files and unpushed commits, re-reading git status so the
+// warning isn't stale.
 func (m Model) remove_worktree(wt worktree.Worktree) (Model, tea.Cmd) {
 	m.picker_open = true
 	m.picker_cursor = 0
-	m.picker_actions = ui.RemoveActions
+	m.picker_actions = remove_actions(wt)
 	m.picker_context = pickerRemove
 	m.recalc_layout()
-	return m, nil
+	return m, cmd_read_git_status(m.repo_root, m.base_refs(), []string{wt.Path}, false)
 }
 
 func (m Model) execute_remove_action(action ui.PickerAction) (Model, tea.Cmd) {
@@ -365,14 +374,21 @@ func (m Model) execute_remove_action(action ui.PickerAction) (Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Model) run_remove_worktree(wt worktree.Worktree, force bool) (Model, tea.Cmd) {
-	// Close any terminal sessions for this worktree
+// close_worktree_tabs closes the terminal sessions opened for a worktree.
+func (m *Model) close_worktree_tabs(wt worktree.Worktree) {
 	for _, prefix := range []string{labels.Shell, labels.Claude, labels.Logs, labels.Dev, labels.Build} {
 		m.term_mgr.CloseByLabel(labels.Tab(prefix, wt.Alias))
 	}
+	m.close_set_tabs(wt)
 	if m.term_mgr.Count() == 0 && m.focus == PanelTerminal {
 		m.focus = PanelWorktrees
 	}
+}
+
+func (m Model) run_remove_worktree(wt worktree.Worktree, force bool) (Model, tea.Cmd) {
+	// Close any terminal sessions for this worktree
+	m.close_worktree_tabs(wt)
+	m.forget_push(wt)
 
 	m.services = nil
 	m.service_cursor = 0
@@ -391,6 +407,14 @@ func (m Model) run_remove_worktree(wt worktree.Worktree, force bool) (Model, tea
 		},
 		func() tea.Msg {
 			out, err := run_host_cmd("node", args...)
+			if err == nil {
+				drop_checkpoints(m.repo_root, wt)
+			}
+			if err == nil && len(wt.Set) > 0 {
+				var set_out string
+				set_out, err = remove_set_members(wt, m.cfg, force)
+				out = strings.TrimSpace(out + "\n" + set_out)
+			}
 			return MsgActionOutput{Output: out, Err: err}
 		},
 	)
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cmd_git_worktree runs a git worktree subcommand in the repo and reports
+// it like any other action, which rediscovers the worktrees.
+func cmd_git_worktree(repo_root string, args ...string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := run_host_cmd("git", append([]string{"-C", repo_root, "worktree"}, args...)...)
+		return MsgActionOutput{Output: out, Err: err}
+	}
+}
+
+// toggle_lock unlocks a locked worktree, or asks why it should be locked
+// and locks it. Git won't prune or remove a locked worktree.
+func (m Model) toggle_lock(wt worktree.Worktree) (Model, tea.Cmd) {
+	if wt.Locked {
+		m.activity = fmt.Sprintf("Unlocking %s...", wt.Alias)
+		return m, cmd_git_worktree(m.repo_root, "unlock", wt.Path)
+	}
+	return m.open_panel_input("Lock", fmt.Sprintf("Why lock %s?", wt.Alias), func(mdl *Model, reason string) (Model, tea.Cmd) {
+		mdl.activity = fmt.Sprintf("Locking %s...", wt.Alias)
+		return *mdl, cmd_git_worktree(mdl.repo_root, "lock", "--reason", reason, wt.Path)
+	})
+}
+
+// prune_worktrees asks to drop git's records of worktrees whose directory
+// is gone. Git prunes every stale entry at once.
+func (m Model) prune_worktrees(wt worktree.Worktree) (Model, tea.Cmd) {
+	var stale int
+	for _, w := range m.worktrees {
+		if w.Prunable && !w.Locked {
+			stale++
+		}
+	}
+	prompt := fmt.Sprintf("Prune %s (git worktree prune)?", plural(stale, "stale worktree"))
+	return m.open_panel_confirm("Prune", prompt, func(mdl *Model) (Model, tea.Cmd) {
+		mdl.activity = "Pruning worktrees..."
+		return *mdl, cmd_git_worktree(mdl.repo_root, "prune", "--verbose")
+	})
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+// find_picker_action returns the picker entry with the given key, or nil.
+func find_picker_action(actions []ui.PickerAction, key string) *ui.PickerAction {
+	for i := range actions {
+		if actions[i].Key == key {
+			return &actions[i]
+		}
+	}
+	return nil
+}
+
+func TestLockAndPruneActions(t *testing.T) {
+	m := list_model()
+
+	// Unlocked: Lock asks for a reason before running git
+	m, cmd := m.toggle_lock(m.worktrees[0])
+	if cmd != nil || !m.input_active || !strings.Contains(m.input_prompt, "lock api") {
+		t.Fatalf("lock should prompt for a reason: active=%v prompt=%q", m.input_active, m.input_prompt)
+	}
+
+	locked := worktree.Worktree{Type: worktree.TypeLocal, Locked: true, LockReason: "demo on friday"}
+	if a := find_picker_action(m.actions_for_worktree(locked), "O"); a == nil || a.Label != "Unlock" || a.Desc != "demo on friday" {
+		t.Errorf("locked worktree picker entry: %+v", a)
+	}
+	if got := remove_actions(locked)[0].Desc; got != "Fails if dirty · locked" {
+		t.Errorf("remove warning = %q", got)
+	}
+
+	// Prune replaces Lock for worktrees whose directory is gone
+	prunable := worktree.Worktree{Type: worktree.TypeLocal, Prunable: true}
+	actions := m.actions_for_worktree(prunable)
+	if find_picker_action(actions, "R") == nil || find_picker_action(actions, "O") != nil {
+		t.Errorf("prunable worktree: %q", action_keys(actions))
+	}
+	m.worktrees[3].Prunable = true
+	m, _ = m.prune_worktrees(m.worktrees[3])
+	if !m.confirm_open || m.confirm_prompt != "Prune 1 stale worktree (git worktree prune)?" {
+		t.Errorf("prune confirm: open=%v prompt=%q", m.confirm_open, m.confirm_prompt)
+	}
+}
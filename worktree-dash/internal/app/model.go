@@ -7,10 +7,12 @@ import (
 	"time"
 
 	"github.com/elvisnm/wt/internal/beads"
+	"github.com/elvisnm/wt/internal/cast"
 	"github.com/elvisnm/wt/internal/claude"
 	"github.com/elvisnm/wt/internal/config"
 	"github.com/elvisnm/wt/internal/docker"
 	"github.com/elvisnm/wt/internal/settings"
+	"github.com/elvisnm/wt/internal/state"
 	"github.com/elvisnm/wt/internal/terminal"
 	"github.com/elvisnm/wt/internal/ui"
 	"github.com/elvisnm/wt/internal/worktree"
@@ -64,6 +66,29 @@ type Model struct {
 	// Merge state: stored when merge flow starts
 	merge_source_session_id int // session being moved
 
+	// Scrollback: session pending capture, last search query and its hits
+	capture_session_id int
+	search_query       string
+	search_matches     []terminal.ScrollbackMatch
+
+	// Recordings listed in the recordings picker (newest first)
+	recordings []cast.Recording
+
+	// Command palette: full index, ranked matches for the query, recent-use history
+	palette_open    bool
+	palette_query   string
+	palette_cursor  int
+	palette_index   []paletteEntry
+	palette_results []paletteEntry
+	palette_history []string
+	palette_title   string // set when the palette is reused as a picker
+
+	// Finished session the rerun picker acts on
+	finished_session_id int
+
+	// Broadcast commands in flight or finished, keyed by session ID
+	broadcasts map[int]*broadcastRun
+
 	// Details panel scroll
 	details_scroll int
 
@@ -118,6 +143,54 @@ type Model struct {
 	// Claude auto-mode: when true, claude opens with --enable-auto-mode
 	claude_auto_mode bool
 
+	// Checkpoint a worktree whenever claude is opened in it
+	claude_checkpoint bool
+
+	// Exit policy per label type, from settings (see settings.ExitPolicies)
+	exit_policies map[string]string
+
+	// User keybindings rejected at startup, reported once the UI is up
+	keybinding_problems []string
+	theme_problems      []string
+
+	// Worktree list view: search text, quick filters, grouping and sort
+	list_view    settings.WorktreeList
+	list_query   string
+	list_editing bool // search text is being typed
+
+	// Git status per worktree path, read in the background (see gitstatus.go)
+	git_status   map[string]worktree.GitStatus
+	git_fetched  time.Time
+	git_fetching bool
+
+	// Pins and notes, from the repo's state file in ~/.wt/state
+	repo_state state.State
+
+	// Multi-select: worktrees marked for a bulk action, by name
+	marked       map[string]bool
+	bulk         *bulkRun     // bulk action in progress, or nil
+	bulk_results []bulkResult // failed and skipped worktrees of the last bulk action
+	bulk_summary string       // title of the bulk results picker
+
+	// Cleanup view: merged and stale worktrees from the last scan (see cleanup.go)
+	cleanup          []cleanupCandidate
+	cleanup_scanning bool
+	stale_days       int
+
+	// Overlaps view: files changed in more than one worktree (see overlaps.go)
+	overlaps []overlapFile
+
+	// Worktree sets listing their members under their row, by name (see sets.go)
+	expanded_sets map[string]bool
+
+	// Quick create: options collected so far, then the running steps (see create.go)
+	create_plan createPlan
+	create      *createRun
+
+	// Rename: the worktree and alias picked, then the plan and its steps (see rename.go)
+	rename_plan renamePlan
+	rename      *renameRun
+
 	// Claude usage panel
 	usage_visible bool
 	usage_data    *claude.Usage
@@ -158,12 +231,18 @@ func (m *Model) recalc_layout() {
 // notify_height returns the number of rows needed by the notification area.
 func (m *Model) notify_height() int {
 	switch {
+	case m.palette_open:
+		return ui.NotifyHeight(ui.NotifyPalette, len(m.palette_results))
 	case m.picker_open:
 		return ui.NotifyHeight(ui.NotifyPicker, len(m.picker_actions))
 	case m.confirm_open:
 		return ui.NotifyHeight(ui.NotifyConfirm, 0)
 	case m.input_active:
 		return ui.NotifyHeight(ui.NotifyInput, 0)
+	case m.create != nil:
+		return ui.NotifyHeight(ui.NotifySteps, len(m.create.state))
+	case m.rename != nil:
+		return ui.NotifyHeight(ui.NotifySteps, len(m.rename.state))
 	case m.notify_open:
 		return ui.NotifyHeight(ui.NotifyMessage, 0)
 	default:
@@ -212,6 +291,8 @@ func NewModelWithLayout(server *terminal.TmuxServer, pl *terminal.PaneLayout) Mo
 	// Load user settings and apply default panel visibility + split limits
 	s := settings.Load()
 	mgr.SetSplitLimits(s.MaxPanesPerGroup)
+	_, key_problems := ApplyKeybindings(s.Keybindings)
+	theme_problems := ApplyTheme(s.Theme, s.Colors)
 
 	return Model{
 		focus:           PanelWorktrees,
@@ -225,6 +306,14 @@ func NewModelWithLayout(server *terminal.TmuxServer, pl *terminal.PaneLayout) Mo
 		usage_visible:   s.DefaultPanels.Usage,
 		tasks_visible:   s.DefaultPanels.Tasks,
 		claude_auto_mode: s.ClaudeAutoMode,
+		claude_checkpoint: s.ClaudeCheckpoint,
+		exit_policies:    s.ExitPolicies,
+		list_view:        s.WorktreeList,
+		repo_state:       state.Load(repo_root),
+		stale_days:       s.StaleDays,
+
+		keybinding_problems: key_problems,
+		theme_problems:      theme_problems,
 	}
 }
 
@@ -245,6 +334,12 @@ func (m Model) Init() tea.Cmd {
 	debug_log("[init] config name=%q strategy=%q", cfg_name, cfg_strategy)
 
 	cmds := []tea.Cmd{m.cmd_discover()}
+	if cmd := cmd_settings_problems("Keybindings", m.keybinding_problems); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := cmd_settings_problems("Theme", m.theme_problems); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
 
 	// Fetch data for panels enabled by default via settings
 	if m.usage_visible {
@@ -296,7 +391,7 @@ func (m Model) cmd_discover() tea.Cmd {
 	term_mgr := m.term_mgr
 	return func() tea.Msg {
 		debug_log("[discovery] starting discovery in %s", m.worktrees_dir)
-		wts := worktree.Discover(m.worktrees_dir, m.worktrees, cfg)
+		wts := worktree.DiscoverRepo(m.repo_root, m.worktrees_dir, m.worktrees, cfg)
 		debug_log("[discovery] found %d worktrees", len(wts))
 		for _, wt := range wts {
 			debug_log("[discovery]   %s type=%v alias=%s offset=%d domain=%s path=%s", wt.Name, wt.Type, wt.Alias, wt.Offset, wt.Domain, wt.Path)
@@ -314,10 +409,10 @@ func (m Model) cmd_discover() tea.Cmd {
 	}
 }
 
-func cmd_fetch_status(wt_dir string, wts []worktree.Worktree, cfg *config.Config, term_mgr *terminal.Manager) tea.Cmd {
+func cmd_fetch_status(repo_root, wt_dir string, wts []worktree.Worktree, cfg *config.Config, term_mgr *terminal.Manager) tea.Cmd {
 	return func() tea.Msg {
 		debug_log("[tick] fetch_status: %d worktrees", len(wts))
-		fresh := worktree.Discover(wt_dir, wts, cfg)
+		fresh := worktree.DiscoverRepo(repo_root, wt_dir, wts, cfg)
 		fresh = docker.FetchContainerStatus(fresh, cfg)
 		fresh = mark_local_running(fresh, cfg, term_mgr)
 		fresh = worktree.SortWorktrees(fresh)
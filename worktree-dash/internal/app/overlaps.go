@@ -0,0 +1,120 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// overlapFile is a file changed in more than one worktree, listed in the
+// overlaps picker.
+type overlapFile struct {
+	path    string
+	aliases []string
+}
+
+// overlap_name is how a worktree is named in overlaps: its alias, or its
+// directory name when it has none.
+func overlap_name(wt worktree.Worktree) string {
+	if wt.Alias != "" {
+		return wt.Alias
+	}
+	return wt.Name
+}
+
+// find_overlaps returns the files changed in more than one worktree, by
+// path, with the worktrees changing them. Changes are what each worktree's
+// cached git status lists since it forked from the base ref, so worktrees
+// not read yet take no part.
+func find_overlaps(wts []worktree.Worktree) []overlapFile {
+	by_path := map[string][]string{}
+	for _, wt := range wts {
+		if wt.Prunable {
+			continue
+		}
+		for _, path := range wt.Git.Changed {
+			by_path[path] = append(by_path[path], overlap_name(wt))
+		}
+	}
+	var files []overlapFile
+	for path, aliases := range by_path {
+		if len(aliases) > 1 {
+			sort.Strings(aliases)
+			files = append(files, overlapFile{path: path, aliases: aliases})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files
+}
+
+// apply_overlaps sets each worktree's overlaps from the cached git status.
+func (m *Model) apply_overlaps() {
+	by_alias := map[string][]worktree.Overlap{}
+	for _, f := range find_overlaps(m.worktrees) {
+		for _, alias := range f.aliases {
+			var others []string
+			for _, other := range f.aliases {
+				if other != alias {
+					others = append(others, other)
+				}
+			}
+			by_alias[alias] = append(by_alias[alias], worktree.Overlap{Path: f.path, With: others})
+		}
+	}
+	for i := range m.worktrees {
+		m.worktrees[i].Overlaps = by_alias[overlap_name(m.worktrees[i])]
+	}
+}
+
+// open_overlaps lists every file changed in more than one worktree.
+func (m Model) open_overlaps() (Model, tea.Cmd) {
+	m.overlaps = find_overlaps(m.worktrees)
+	if len(m.overlaps) == 0 {
+		if m.git_fetched.IsZero() {
+			return m.show_notification("Overlaps", "Git status hasn't been read yet")
+		}
+		return m.show_notification("Overlaps", "No file is changed in more than one worktree")
+	}
+	return m.open_panel_picker("Overlaps", m.overlap_actions(), pickerOverlaps)
+}
+
+// overlap_actions has one entry per file, with the worktrees changing it.
+func (m Model) overlap_actions() []ui.PickerAction {
+	actions := make([]ui.PickerAction, len(m.overlaps))
+	for i, f := range m.overlaps {
+		actions[i] = ui.PickerAction{Key: fmt.Sprintf("%d", i+1), Label: f.path, Desc: strings.Join(f.aliases, ", ")}
+	}
+	return actions
+}
+
+// overlaps_title counts the files and the worktrees involved.
+func (m Model) overlaps_title() string {
+	wts := map[string]bool{}
+	for _, f := range m.overlaps {
+		for _, alias := range f.aliases {
+			wts[alias] = true
+		}
+	}
+	return fmt.Sprintf("Overlaps — %s in %s", plural(len(m.overlaps), "file"), plural(len(wts), "worktree"))
+}
+
+// execute_overlap_action moves the cursor to the first worktree changing
+// the file selected, where the details panel lists its other overlaps.
+func (m Model) execute_overlap_action(action ui.PickerAction) (Model, tea.Cmd) {
+	idx := m.picker_cursor
+	if idx < 0 || idx >= len(m.overlaps) {
+		return m, nil
+	}
+	name := ""
+	for _, wt := range m.worktrees {
+		if overlap_name(wt) == m.overlaps[idx].aliases[0] {
+			name = wt.Name
+		}
+	}
+	return m.focus_worktree(name, "Overlaps")
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+func TestOverlaps(t *testing.T) {
+	m := list_model()
+	changed := map[string][]string{
+		"/wt/api":        nil,
+		"/wt/feat-login": {"go.mod", "src/api/orders.ts", "src/login.ts"},
+		"/wt/fix-crash":  {"src/api/orders.ts", "src/crash.ts"},
+		"/wt/feat-pay":   {"go.mod", "src/api/orders.ts"},
+	}
+	m.git_status = map[string]worktree.GitStatus{}
+	for path, files := range changed {
+		m.git_status[path] = worktree.GitStatus{Changed: files, Fetched: time.Now()}
+	}
+	m.git_fetched = time.Now()
+	m.apply_git_status()
+
+	var got []string
+	for _, wt := range m.worktrees {
+		for _, o := range wt.Overlaps {
+			got = append(got, wt.Alias+" "+o.Path+" "+strings.Join(o.With, ","))
+		}
+	}
+	want := "login go.mod pay; login src/api/orders.ts crash,pay; crash src/api/orders.ts login,pay; " +
+		"pay go.mod login; pay src/api/orders.ts crash,login"
+	if strings.Join(got, "; ") != want {
+		t.Errorf("overlaps:\n%s\nwant\n%s", strings.Join(got, "; "), want)
+	}
+
+	m, _ = m.open_overlaps()
+	if !m.picker_open || m.picker_context != pickerOverlaps || m.overlaps_title() != "Overlaps — 2 files in 3 worktrees" {
+		t.Fatalf("picker: open=%v context=%q title=%q", m.picker_open, m.picker_context, m.overlaps_title())
+	}
+	if a := m.picker_actions[1]; a.Label != "src/api/orders.ts" || a.Desc != "crash, login, pay" {
+		t.Errorf("second entry: %+v", a)
+	}
+	m = type_keys(m, runes("2"))
+	if wt := m.selected_worktree(); wt == nil || wt.Alias != "crash" {
+		t.Errorf("the first worktree changing the file should be selected: %+v", wt)
+	}
+
+	// Nothing shared
+	m.git_status = map[string]worktree.GitStatus{"/wt/feat-login": {Changed: []string{"a"}, Fetched: time.Now()}}
+	m.apply_git_status()
+	if m, _ = m.open_overlaps(); m.picker_open || m.worktrees[1].Overlaps != nil {
+		t.Error("no overlaps should show a notification, not the picker")
+	}
+}
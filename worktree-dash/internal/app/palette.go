@@ -0,0 +1,388 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/elvisnm/wt/internal/labels"
+	"github.com/elvisnm/wt/internal/settings"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteHistoryMax caps how many recently used palette entries are remembered.
+const paletteHistoryMax = 50
+
+// paletteEntry is one command in the palette index.
+type paletteEntry struct {
+	id    string // stable key for recent-use ranking
+	title string // searchable text ("Restart — feat-login")
+	hint  string // dim text on the right (description or key)
+	run   func(m Model) (tea.Model, tea.Cmd)
+}
+
+// palette_history_path returns ~/.wt/palette-history.json.
+func palette_history_path() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	return filepath.Join(home, ".wt", "palette-history.json")
+}
+
+// load_palette_history reads recently used entry IDs, most recent first.
+func load_palette_history() []string {
+	data, err := os.ReadFile(palette_history_path())
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	if json.Unmarshal(data, &ids) != nil {
+		return nil
+	}
+	return ids
+}
+
+// save_palette_history writes recently used entry IDs.
+func save_palette_history(ids []string) {
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return
+	}
+	path := palette_history_path()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, data, 0644)
+}
+
+// touch_palette_history moves id to the front of the history.
+func touch_palette_history(ids []string, id string) []string {
+	result := []string{id}
+	for _, existing := range ids {
+		if existing != id && len(result) < paletteHistoryMax {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
+
+// fuzzy_score matches every whitespace-separated token of query as a
+// case-insensitive subsequence of text. Consecutive characters and matches at
+// word starts score higher; gaps cost a little. Returns false if any token doesn't match.
+func fuzzy_score(query, text string) (int, bool) {
+	t := []rune(strings.ToLower(text))
+	total := 0
+	for _, token := range strings.Fields(strings.ToLower(query)) {
+		q := []rune(token)
+		best := -1
+		for start := range t {
+			if t[start] != q[0] {
+				continue
+			}
+			if score, ok := fuzzy_from(q, t, start); ok && score > best {
+				best = score
+			}
+		}
+		if best < 0 {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// fuzzy_from greedily matches q in t starting at t[start] (which matches q[0]).
+func fuzzy_from(q, t []rune, start int) (int, bool) {
+	score := 0
+	prev := -2
+	qi := 0
+	for ti := start; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+		score++
+		if ti == prev+1 {
+			score += 5
+		} else if prev >= 0 {
+			score -= min(ti-prev-1, 3) // small penalty for skipped characters
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3
+		}
+		prev = ti
+		qi++
+	}
+	return score, qi == len(q)
+}
+
+// rank_palette filters entries by query and orders them by match score,
+// boosted by how recently each entry was used. With an empty query, recently
+// used entries come first and the rest keep their index order.
+func rank_palette(entries []paletteEntry, query string, history []string) []paletteEntry {
+	recent := make(map[string]int, len(history))
+	for i, id := range history {
+		recent[id] = paletteHistoryMax - i
+	}
+
+	type scored struct {
+		entry paletteEntry
+		score int
+	}
+	var results []scored
+	for _, e := range entries {
+		score := 0
+		if strings.TrimSpace(query) != "" {
+			s, ok := fuzzy_score(query, e.title)
+			if !ok {
+				continue
+			}
+			score = s * 10
+		}
+		score += recent[e.id]
+		results = append(results, scored{e, score})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	ranked := make([]paletteEntry, len(results))
+	for i, r := range results {
+		ranked[i] = r.entry
+	}
+	return ranked
+}
+
+// palette_entries indexes every worktree action, open session and global command.
+func (m Model) palette_entries() []paletteEntry {
+	var entries []paletteEntry
+
+	// picker_entry runs a picker action through dispatch_picker, as if chosen
+	// in that picker with alias selected in the worktree list.
+	picker_entry := func(id_prefix, title, alias, context string, a ui.PickerAction) paletteEntry {
+		return paletteEntry{
+			id:    id_prefix + ":" + a.Key + ":" + alias,
+			title: title,
+			hint:  a.Desc,
+			run: func(m Model) (tea.Model, tea.Cmd) {
+				var select_cmd tea.Cmd
+				if alias != "" {
+					m, select_cmd = m.select_worktree_alias(alias)
+				}
+				m.picker_context = context
+				m, cmd := m.dispatch_picker(a)
+				return m, tea.Batch(select_cmd, cmd)
+			},
+		}
+	}
+
+	// Worktree actions, as offered by each worktree's action picker
+	for _, wt := range m.worktrees {
+		for _, a := range m.actions_for_worktree(wt) {
+			entries = append(entries, picker_entry("wt", labels.Tab(a.Label, wt.Alias), wt.Alias, pickerWorktree, a))
+		}
+		if wt.Running && wt.Type == worktree.TypeDocker {
+			for _, a := range ui.FilterDatabaseActions(m.cfg) {
+				entries = append(entries, picker_entry("db", labels.Tab("DB "+a.Label, wt.Alias), wt.Alias, pickerDB, a))
+			}
+		}
+	}
+
+	// Open sessions
+	for _, s := range m.term_mgr.Sessions() {
+		id := s.ID
+		entries = append(entries, paletteEntry{
+			id:    "session:" + s.Label,
+			title: "Focus " + s.Label,
+			hint:  "tab",
+			run: func(m Model) (tea.Model, tea.Cmd) {
+				if m.term_mgr.FocusBySessionID(id) == nil {
+					return m, nil
+				}
+				m.sync_tab_cursor_from_active()
+				m.prev_focus = m.focus
+				m.focus = PanelTerminal
+				if m.pane_layout != nil {
+					m.pane_layout.FocusRight()
+				}
+				return m, nil
+			},
+		})
+	}
+
+	// Maintenance
+	for _, a := range ui.FilterMaintenanceActions(m.cfg) {
+		entries = append(entries, picker_entry("maint", a.Label, "", pickerMaintenance, a))
+	}
+
+	// Global operations and settings, gated like their Shift+key shortcuts
+	global := func(id, title, hint string, run func(m Model) (tea.Model, tea.Cmd)) {
+		entries = append(entries, paletteEntry{id: "global:" + id, title: title, hint: hint, run: run})
+	}
+	global("create", "Create worktree", "n", func(m Model) (tea.Model, tea.Cmd) { return m.open_quick_create() })
+	for _, a := range global_actions {
+		if a.palette == "" || !a.enabled(&m) {
+			continue
+		}
+		var hint string
+		if keys := a.binding().Keys(); len(keys) > 0 {
+			hint = keys[0]
+		}
+		global(a.palette, a.title, hint, a.run)
+	}
+	global("filter", "Filter worktrees", "/", func(m Model) (tea.Model, tea.Cmd) { return m.open_list_filter() })
+	global("list-view", "Worktree filters, grouping & sort", "f", func(m Model) (tea.Model, tea.Cmd) { return m.open_list_view() })
+	global("mark-all", "Mark all listed worktrees", "*", func(m Model) (tea.Model, tea.Cmd) { return m.mark_all() })
+	if len(m.marked) > 0 {
+		global("bulk", fmt.Sprintf("Bulk actions on %d marked worktrees", len(m.marked)), "enter", func(m Model) (tea.Model, tea.Cmd) { return m.open_bulk_menu() })
+	}
+	global("help", "Keybindings help", "?", func(m Model) (tea.Model, tea.Cmd) { return m.open_help() })
+	global("settings", "Settings", "S", func(m Model) (tea.Model, tea.Cmd) { return m.open_settings() })
+	global("claude-auto", "Toggle Claude auto mode", "setting", func(m Model) (tea.Model, tea.Cmd) {
+		s := settings.Load()
+		s.ClaudeAutoMode = !s.ClaudeAutoMode
+		if err := settings.Save(s); err != nil {
+			return m.show_notification("Settings", err.Error())
+		}
+		m.claude_auto_mode = s.ClaudeAutoMode
+		state := "off"
+		if s.ClaudeAutoMode {
+			state = "on"
+		}
+		return m.show_notification("Settings", "Claude auto mode "+state)
+	})
+
+	return entries
+}
+
+// select_worktree_alias moves the worktree cursor to alias, as if navigated there.
+// If the list filters hide it, the search text and quick filters are cleared
+// for this session (the saved settings are left alone).
+func (m Model) select_worktree_alias(alias string) (Model, tea.Cmd) {
+	hidden := true
+	for _, wt := range m.visible_worktrees() {
+		if wt.Alias == alias {
+			hidden = false
+		}
+	}
+	if hidden {
+		m.list_query = ""
+		m.list_editing = false
+		m.list_view.Running, m.list_view.Docker, m.list_view.Dirty = false, false, false
+		m.clamp_cursor()
+	}
+	for i, wt := range m.visible_worktrees() {
+		if wt.Alias != alias {
+			continue
+		}
+		if i == m.cursor {
+			return m, nil
+		}
+		m.cursor = i
+		m.details_scroll = 0
+		m.close_preview()
+		m.services = nil
+		m.service_cursor = 0
+		return m, m.refresh_services()
+	}
+	return m, nil
+}
+
+// open_palette opens the command palette in the notification area.
+func (m Model) open_palette() (tea.Model, tea.Cmd) {
+	m.palette_open = true
+	m.palette_title = ""
+	m.palette_query = ""
+	m.palette_cursor = 0
+	m.palette_index = m.palette_entries()
+	m.palette_history = load_palette_history()
+	m.palette_results = rank_palette(m.palette_index, "", m.palette_history)
+	m.recalc_layout()
+	return m, nil
+}
+
+// close_palette hides the palette and drops its index.
+func (m *Model) close_palette() {
+	m.palette_open = false
+	m.palette_title = ""
+	m.palette_query = ""
+	m.palette_index = nil
+	m.palette_results = nil
+	m.recalc_layout()
+}
+
+// refilter_palette re-ranks the index after the query changed.
+func (m *Model) refilter_palette() {
+	m.palette_results = rank_palette(m.palette_index, m.palette_query, m.palette_history)
+	m.palette_cursor = 0
+	m.recalc_layout()
+}
+
+// palette_items converts ranked entries for rendering.
+func (m Model) palette_items() []ui.PaletteItem {
+	items := make([]ui.PaletteItem, len(m.palette_results))
+	for i, e := range m.palette_results {
+		items[i] = ui.PaletteItem{Title: e.title, Hint: e.hint}
+	}
+	return items
+}
+
+func (m Model) handle_palette_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, Keys.Escape), key.Matches(msg, Keys.CtrlC):
+		m.close_palette()
+		return m, nil
+
+	case key.Matches(msg, Keys.Enter):
+		if m.palette_cursor < 0 || m.palette_cursor >= len(m.palette_results) {
+			return m, nil
+		}
+		entry := m.palette_results[m.palette_cursor]
+		if entry.id != "" {
+			save_palette_history(touch_palette_history(m.palette_history, entry.id))
+		}
+		m.close_palette()
+		return entry.run(m)
+
+	case msg.String() == "up", msg.String() == "ctrl+p":
+		if m.palette_cursor > 0 {
+			m.palette_cursor--
+		}
+		return m, nil
+
+	case msg.String() == "down", msg.String() == "ctrl+n":
+		if m.palette_cursor < len(m.palette_results)-1 {
+			m.palette_cursor++
+		}
+		return m, nil
+
+	case msg.Type == tea.KeyBackspace:
+		if r := []rune(m.palette_query); len(r) > 0 {
+			m.palette_query = string(r[:len(r)-1])
+			m.refilter_palette()
+		}
+		return m, nil
+
+	case msg.String() == "ctrl+u":
+		m.palette_query = ""
+		m.refilter_palette()
+		return m, nil
+
+	case msg.Type == tea.KeySpace:
+		m.palette_query += " "
+		m.refilter_palette()
+		return m, nil
+
+	case msg.Type == tea.KeyRunes:
+		m.palette_query += string(msg.Runes)
+		m.refilter_palette()
+		return m, nil
+	}
+	return m, nil
+}
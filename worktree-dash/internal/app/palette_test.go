@@ -0,0 +1,94 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzy_score("rst login", "Restart — feat-login"); !ok {
+		t.Error("tokens should match as subsequences in any order of words")
+	}
+	if _, ok := fuzzy_score("restart xyz", "Restart — feat-login"); ok {
+		t.Error("every token must match")
+	}
+
+	word_start, _ := fuzzy_score("seed", "DB Seed — api")
+	scattered, _ := fuzzy_score("seed", "Search scrollback — eden")
+	if word_start <= scattered {
+		t.Errorf("contiguous word-start match (%d) should beat a scattered one (%d)", word_start, scattered)
+	}
+}
+
+func titles(entries []paletteEntry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.title
+	}
+	return out
+}
+
+func TestRankPalette(t *testing.T) {
+	entries := []paletteEntry{
+		{id: "wt:r:feat-login", title: "Restart — feat-login"},
+		{id: "wt:r:api", title: "Restart — api"},
+		{id: "wt:l:feat-login", title: "Logs — feat-login"},
+	}
+
+	got := titles(rank_palette(entries, "restart login", nil))
+	if len(got) != 1 || got[0] != "Restart — feat-login" {
+		t.Errorf("rank(restart login) = %v", got)
+	}
+
+	// Empty query: recently used entries first, then index order
+	got = titles(rank_palette(entries, "", []string{"wt:l:feat-login"}))
+	want := []string{"Logs — feat-login", "Restart — feat-login", "Restart — api"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("rank(\"\") = %v, want %v", got, want)
+	}
+
+	// Equal matches: the recently used one wins
+	got = titles(rank_palette(entries, "restart", []string{"wt:r:api"}))
+	if got[0] != "Restart — api" {
+		t.Errorf("rank(restart) with history = %v", got)
+	}
+}
+
+func TestTouchPaletteHistory(t *testing.T) {
+	got := touch_palette_history([]string{"a", "b", "c"}, "c")
+	if strings.Join(got, ",") != "c,a,b" {
+		t.Errorf("touch = %v, want [c a b]", got)
+	}
+}
+
+func TestPaletteIndexesWorktreeActions(t *testing.T) {
+	m := test_model_full()
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	m = result.(Model)
+	if !m.palette_open {
+		t.Fatal("':' should open the command palette")
+	}
+
+	for _, r := range "seed test" {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+		if r == ' ' {
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		}
+		result, _ = m.Update(msg)
+		m = result.(Model)
+	}
+	if len(m.palette_results) == 0 || m.palette_results[0].title != "DB Seed — test" {
+		t.Fatalf("top result = %v, want DB Seed — test", titles(m.palette_results))
+	}
+	if !strings.Contains(m.View(), "DB Seed — test") {
+		t.Error("palette should render its matches")
+	}
+
+	result, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if result.(Model).palette_open {
+		t.Error("Esc should close the palette")
+	}
+}
@@ -0,0 +1,287 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/beads"
+	"github.com/elvisnm/wt/internal/ui"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// globalAction is a dashboard-wide key, checked before any panel handler.
+// The key handler, the palette and the help page all read global_actions,
+// so a key can't be gated in one of them and not the others.
+type globalAction struct {
+	id      string // keybinding ID (keys.<id>)
+	help    string // help page description
+	palette string // palette entry ID, "" to leave it out of the palette
+	title   string // palette title
+	feature string // config feature flag it needs, "" for none
+	run     func(m Model) (tea.Model, tea.Cmd)
+}
+
+var global_actions = []globalAction{
+	{
+		id: "aws", help: "AWS Keys", palette: "aws", title: "AWS credentials", feature: "awsCredentials",
+		run: func(m Model) (tea.Model, tea.Cmd) {
+			debug_log("[aws] Shift+A pressed")
+			// SSO mode: check session first
+			if profile := m.cfg.AwsSsoProfile(); profile != "" {
+				return m.check_sso_then_login()
+			}
+			return m.open_aws_keys()
+		},
+	},
+	{id: "database", help: "database picker", run: Model.open_db_picker},
+	{id: "details", help: "details toggle", palette: "details", title: "Toggle details panel", run: Model.toggle_details},
+	{id: "search", help: "search scrollback", palette: "search", title: "Search scrollback", run: Model.open_scrollback_search},
+	{id: "skip_worktree", help: "skip-worktree", palette: "skip", title: "Toggle skip-worktree", run: Model.toggle_skip_worktree},
+	{id: "lan", help: "LAN toggle", palette: "lan", title: "Toggle LAN access", feature: "lan", run: Model.toggle_lan},
+	{id: "maintenance", help: "maintenance", run: Model.open_maintenance_picker},
+	{id: "tasks", help: "tasks", palette: "tasks", title: "Toggle tasks panel", run: Model.toggle_tasks},
+	{id: "usage", help: "Claude usage", palette: "usage", title: "Toggle usage panel", run: Model.toggle_usage},
+	{id: "admin", help: "admin toggle", palette: "admin", title: "Toggle admin account", feature: "admin", run: Model.toggle_admin},
+}
+
+// enabled reports whether a's feature flag is on.
+func (a globalAction) enabled(m *Model) bool {
+	return a.feature == "" || m.cfg == nil || m.cfg.FeatureEnabled(a.feature)
+}
+
+// binding returns a's current keys.
+func (a globalAction) binding() key.Binding {
+	return *keymap_bindings(&Keys)[a.id]
+}
+
+// find_global_action returns the enabled global action msg is bound to.
+func (m *Model) find_global_action(msg tea.KeyMsg) (globalAction, bool) {
+	for _, a := range global_actions {
+		if key.Matches(msg, a.binding()) && a.enabled(m) {
+			return a, true
+		}
+	}
+	return globalAction{}, false
+}
+
+// panelAction is a single-key action of one dashboard panel.
+type panelAction struct {
+	key  string
+	help string
+	run  func(m Model) (tea.Model, tea.Cmd)
+}
+
+// find_panel_action looks up the action bound to k in actions.
+func find_panel_action(actions []panelAction, k string) (panelAction, bool) {
+	for _, a := range actions {
+		if a.key == k {
+			return a, true
+		}
+	}
+	return panelAction{}, false
+}
+
+// tab_actions are the active tabs panel's keys.
+var tab_actions = []panelAction{
+	{key: "h", help: "prev tab", run: Model.prev_tab},
+	{key: "l", help: "next tab", run: Model.next_tab},
+	{key: "f", help: "fullscreen", run: Model.fullscreen_tab},
+	{key: "c", help: "capture scrollback", run: Model.open_capture_picker},
+	{key: "o", help: "toggle recording", run: Model.toggle_recording},
+	{key: "b", help: "broadcast to group", run: Model.toggle_group_sync},
+	{key: "R", help: "rerun finished tab", run: Model.open_finished_picker},
+	{key: "r", help: "rename tab", run: Model.rename_tab},
+	{key: "x", help: "close tab", run: Model.close_tab},
+}
+
+// split_actions are the active tabs panel's keys for split panes.
+var split_actions = []panelAction{
+	{key: "|", help: "split side by side", run: func(m Model) (tea.Model, tea.Cmd) { return m.open_split_picker(SplitH) }},
+	{key: "_", help: "split below", run: func(m Model) (tea.Model, tea.Cmd) { return m.open_split_picker(SplitV) }},
+	{key: "m", help: "move tab into group", run: Model.open_merge_picker},
+}
+
+// service_actions are the services panel's keys. They run with a running
+// worktree selected.
+var service_actions = []panelAction{
+	{key: "l", help: "pin logs (tab)", run: Model.pin_service_logs},
+	{key: "r", help: "restart service", run: func(m Model) (tea.Model, tea.Cmd) { return m.service_action("restart") }},
+	{key: "t", help: "stop service", run: func(m Model) (tea.Model, tea.Cmd) { return m.service_action("stop") }},
+}
+
+// task_actions are the tasks panel's keys. They run with a task selected.
+var task_actions = []panelAction{
+	{key: "c", help: "close task", run: func(m Model) (tea.Model, tea.Cmd) { return m.confirm_task_action("Close", beads.CloseTask) }},
+	{key: "d", help: "delete task", run: func(m Model) (tea.Model, tea.Cmd) { return m.confirm_task_action("Delete", beads.DeleteTask) }},
+}
+
+// tab_panel_keys are the fixed keys of the active tabs panel.
+func tab_panel_keys() []string {
+	var keys []string
+	for _, a := range append(append([]panelAction{}, tab_actions...), split_actions...) {
+		keys = append(keys, a.key)
+	}
+	return keys
+}
+
+func (m Model) prev_tab() (tea.Model, tea.Cmd) {
+	m.term_mgr.PrevTab()
+	m.sync_tab_cursor_from_active()
+	return m, nil
+}
+
+func (m Model) next_tab() (tea.Model, tea.Cmd) {
+	m.term_mgr.NextTab()
+	m.sync_tab_cursor_from_active()
+	return m, nil
+}
+
+// fullscreen_tab zooms the right pane and focuses it.
+func (m Model) fullscreen_tab() (tea.Model, tea.Cmd) {
+	if s := m.term_mgr.Active(); s != nil && s.IsAlive() && m.pane_layout != nil {
+		m.pane_layout.ZoomRight()
+		m.pane_layout.FocusRight()
+	}
+	return m, nil
+}
+
+// close_tab closes the tab under the cursor: a whole group on its head, one
+// pane on a group child.
+func (m Model) close_tab() (tea.Model, tea.Cmd) {
+	tab_labels := m.term_mgr.TabLabels()
+	if m.tab_cursor >= 0 && m.tab_cursor < len(tab_labels) {
+		tl := tab_labels[m.tab_cursor]
+		if tl.IsGroupHead {
+			// Close entire group
+			m.term_mgr.CloseActive()
+		} else if tl.IsGroupChild {
+			// Close just this one pane
+			m.term_mgr.CloseBySessionID(tl.SessionID)
+		} else {
+			// Standalone tab
+			m.term_mgr.CloseActive()
+		}
+	} else {
+		m.term_mgr.CloseActive()
+	}
+	// Clamp tab_cursor after close
+	new_labels := m.term_mgr.TabLabels()
+	if m.tab_cursor >= len(new_labels) {
+		m.tab_cursor = len(new_labels) - 1
+	}
+	if m.tab_cursor < 0 {
+		m.tab_cursor = 0
+	}
+	if m.term_mgr.Count() == 0 {
+		m.focus = PanelWorktrees
+	}
+	return m, nil
+}
+
+// rename_tab renames the session under the cursor.
+func (m Model) rename_tab() (tea.Model, tea.Cmd) {
+	tab_labels := m.term_mgr.TabLabels()
+	if m.tab_cursor < 0 || m.tab_cursor >= len(tab_labels) || tab_labels[m.tab_cursor].SessionID <= 0 {
+		return m, nil
+	}
+	session_id := tab_labels[m.tab_cursor].SessionID
+	return m.open_panel_input("Rename", "New name:", func(mdl *Model, val string) (Model, tea.Cmd) {
+		name := strings.TrimSpace(val)
+		if name == "" {
+			return *mdl, nil
+		}
+		for _, s := range mdl.term_mgr.Sessions() {
+			if s.ID == session_id {
+				s.Label = name
+				break
+			}
+		}
+		return *mdl, nil
+	})
+}
+
+// pin_service_logs opens the selected service's logs in a tab.
+func (m Model) pin_service_logs() (tea.Model, tea.Cmd) {
+	wt := m.selected_worktree()
+	if wt == nil || m.service_cursor < 0 || m.service_cursor >= len(m.services) {
+		return m, nil
+	}
+	m.close_preview()
+	return m.open_service_logs(*wt, m.services[m.service_cursor])
+}
+
+// service_action restarts or stops the selected service.
+func (m Model) service_action(action string) (tea.Model, tea.Cmd) {
+	wt := m.selected_worktree()
+	if wt == nil {
+		return m, nil
+	}
+	if m.is_static_local(*wt) {
+		return m, m.show_result(fmt.Sprintf("Per-service %s not available", action))
+	}
+	if m.service_cursor < 0 || m.service_cursor >= len(m.services) {
+		return m, nil
+	}
+	svc := m.services[m.service_cursor]
+	verb := "Restarting"
+	if action == "stop" {
+		verb = "Stopping"
+	}
+	m.activity = fmt.Sprintf("%s %s...", verb, svc.DisplayName)
+	return m, cmd_service_action(action, *wt, svc, m.cfg)
+}
+
+// confirm_task_action asks before running do (close or delete) on the
+// selected task.
+func (m Model) confirm_task_action(verb string, do func(id string) error) (tea.Model, tea.Cmd) {
+	task := m.selected_task()
+	if task == nil {
+		return m, nil
+	}
+	id := task.ID
+	return m.open_panel_confirm(verb+" Task", fmt.Sprintf("%s task %s?", verb, id),
+		func(mdl *Model) (Model, tea.Cmd) {
+			return *mdl, func() tea.Msg {
+				err := do(id)
+				return MsgTaskActionDone{Err: err}
+			}
+		})
+}
+
+// panel_key_help lists actions for the help page, after the given leading
+// entries.
+func panel_key_help(actions []panelAction, lead ...ui.HintPair) []ui.HintPair {
+	items := lead
+	for _, a := range actions {
+		items = append(items, ui.HintPair{Key: DisplayKey(a.key), Desc: a.help})
+	}
+	return items
+}
+
+// HelpKeys returns every help page section generated from the key
+// registries, as currently bound.
+func HelpKeys() ui.HelpKeys {
+	var ops []ui.HintPair
+	for _, a := range global_actions {
+		ops = append(ops, ui.HintPair{Key: KeyHelp(a.id), Desc: a.help})
+	}
+	ops = append(ops, ui.HintPair{Key: DisplayKey("S"), Desc: "settings"})
+
+	return ui.HelpKeys{
+		Tabs: panel_key_help(tab_actions, ui.HintPair{Key: KeyHelp("enter"), Desc: "focus terminal"}),
+		Splits: append(panel_key_help(split_actions),
+			ui.HintPair{Key: "x", Desc: "close pane / group"},
+			ui.HintPair{Key: KeyHelp("enter"), Desc: "focus selected pane"},
+		),
+		Worktrees: WorktreeKeyHelp(),
+		Services:  panel_key_help(service_actions, ui.HintPair{Key: KeyHelp("enter"), Desc: "preview logs"}),
+		Tasks: panel_key_help(task_actions,
+			ui.HintPair{Key: KeyHelp("down") + " / " + KeyHelp("up"), Desc: "navigate tasks"},
+			ui.HintPair{Key: KeyHelp("enter"), Desc: "task detail"},
+		),
+		TasksKey:   KeyHelp("tasks"),
+		Operations: ops,
+	}
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// apply_repo_state copies pins, notes, last starts and sync conflicts from
+// the repo state file onto the worktrees. Discovery doesn't know about
+// them, so this runs after every update.
+func (m *Model) apply_repo_state() {
+	for i := range m.worktrees {
+		name := m.worktrees[i].Name
+		m.worktrees[i].Pinned = m.repo_state.Pinned(name)
+		m.worktrees[i].Note = m.repo_state.Note(name)
+		m.worktrees[i].LastStarted = m.repo_state.LastStarted(name)
+		c, _ := m.repo_state.Conflict(name)
+		m.worktrees[i].SyncConflict, m.worktrees[i].ConflictFiles = c.Base, c.Files
+	}
+}
+
+// pinned_first moves pinned worktrees to the top, keeping the order within
+// pinned and unpinned worktrees.
+func pinned_first(wts []worktree.Worktree) []worktree.Worktree {
+	result := make([]worktree.Worktree, 0, len(wts))
+	for _, wt := range wts {
+		if wt.Pinned {
+			result = append(result, wt)
+		}
+	}
+	for _, wt := range wts {
+		if !wt.Pinned {
+			result = append(result, wt)
+		}
+	}
+	return result
+}
+
+// save_repo_state writes the pins and notes and re-applies them to the list,
+// keeping the selected worktree under the cursor.
+func (m Model) save_repo_state(title string) (Model, tea.Cmd) {
+	if err := state.Save(m.repo_root, m.repo_state); err != nil {
+		return m.show_notification(title, err.Error())
+	}
+	selected := m.selected_name()
+	m.apply_repo_state()
+	return m.relist(selected)
+}
+
+// toggle_pin pins a worktree to the top of the list, or unpins it.
+func (m Model) toggle_pin(wt worktree.Worktree) (Model, tea.Cmd) {
+	pinned := m.repo_state.TogglePin(wt.Name)
+	m, cmd := m.save_repo_state("Pin")
+	if pinned {
+		m.activity = fmt.Sprintf("Pinned %s", wt.Alias)
+	} else {
+		m.activity = fmt.Sprintf("Unpinned %s", wt.Alias)
+	}
+	return m, tea.Batch(cmd, tick_after(3*time.Second, "clear-activity"))
+}
+
+// open_note adds a note to a worktree. When it already has one, a picker
+// offers to edit or remove it.
+func (m Model) open_note(wt worktree.Worktree) (Model, tea.Cmd) {
+	if wt.Note == "" {
+		return m.edit_note(wt)
+	}
+	return m.open_panel_picker("Note", []ui.PickerAction{
+		{Key: "e", Label: "Edit", Desc: wt.Note},
+		{Key: "x", Label: "Remove", Desc: "Delete the note"},
+	}, pickerNote)
+}
+
+// execute_note_action edits or removes the selected worktree's note.
+func (m Model) execute_note_action(action ui.PickerAction) (Model, tea.Cmd) {
+	wt := m.selected_worktree()
+	if wt == nil {
+		return m, nil
+	}
+	switch action.Key {
+	case "e":
+		return m.edit_note(*wt)
+	case "x":
+		m.repo_state.SetNote(wt.Name, "")
+		return m.save_repo_state("Note")
+	}
+	return m, nil
+}
+
+// edit_note prompts for a worktree's note, starting from the current one.
+func (m Model) edit_note(wt worktree.Worktree) (Model, tea.Cmd) {
+	m, cmd := m.open_panel_input("Note", fmt.Sprintf("Note for %s:", wt.Alias), func(mdl *Model, val string) (Model, tea.Cmd) {
+		mdl.repo_state.SetNote(wt.Name, val)
+		return mdl.save_repo_state("Note")
+	})
+	m.input_value = wt.Note
+	return m, cmd
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPinnedSortFirst(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := list_model()
+	m.repo_root = "/code/app"
+
+	// Pin the stopped local worktree; it leads every sort mode
+	m, _ = m.toggle_pin(m.worktrees[2])
+	for _, mode := range worktree.SortModes {
+		m.list_view.Sort = mode
+		if rows := m.visible_worktrees(); rows[0].Name != "fix-crash" {
+			t.Errorf("sort %s: first = %s", mode, rows[0].Name)
+		}
+	}
+
+	// With grouping on, pins get their own group above the branch prefixes
+	m.list_view.Sort = worktree.SortName
+	m.list_view.Group = true
+	rows, groups := m.worktree_rows()
+	if row_names(rows) != "crash login pay api " || groups[0] != "pinned" || groups[1] != "feat/" {
+		t.Errorf("grouped = %q %v", row_names(rows), groups)
+	}
+
+	// Pins are saved per repo and come back with the next discovery
+	if !state.Load("/code/app").Pinned("fix-crash") {
+		t.Error("pin was not saved")
+	}
+	fresh := list_model()
+	fresh.repo_state = state.Load("/code/app")
+	fresh.update_worktrees(list_model().worktrees)
+	if !fresh.worktrees[2].Pinned {
+		t.Error("pin not applied after discovery")
+	}
+}
+
+func TestNoteEditing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := list_model()
+	m.repo_root = "/code/app"
+
+	m, _ = m.open_note(m.worktrees[1])
+	m = type_keys(m, runes("repro 123"))
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	result, _ = result.(Model).Update(cmd())
+	m = result.(Model)
+
+	if got := m.worktrees[1].Note; got != "repro 123" {
+		t.Fatalf("note = %q", got)
+	}
+	if got := state.Load("/code/app").Note("feat-login"); got != "repro 123" {
+		t.Errorf("saved note = %q", got)
+	}
+
+	// With a note, the picker offers to edit it (starting from the current
+	// text) or remove it
+	m.cursor = 1
+	m, _ = m.open_note(m.worktrees[1])
+	if !m.picker_open || m.picker_context != pickerNote {
+		t.Fatalf("existing note should open the picker, context %q", m.picker_context)
+	}
+	edit, _ := m.execute_note_action(m.picker_actions[0])
+	if !edit.input_active || edit.input_value != "repro 123" {
+		t.Errorf("edit starts with %q", edit.input_value)
+	}
+	m, _ = m.execute_note_action(m.picker_actions[1])
+	if m.worktrees[1].Note != "" || state.Load("/code/app").Note("feat-login") != "" {
+		t.Error("remove should delete the note")
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/elvisnm/wt/internal/cast"
+	"github.com/elvisnm/wt/internal/labels"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxRecordingsListed caps the recordings picker so it fits in the notification area.
+const maxRecordingsListed = 9
+
+// unassignedRecordings holds the casts of tabs that belong to no worktree.
+const unassignedRecordings = "_unassigned"
+
+// recordings_dir returns ~/.wt/recordings/<alias>, where a worktree's casts are kept.
+func recordings_dir(alias string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	if alias == "" {
+		alias = unassignedRecordings
+	}
+	return filepath.Join(home, ".wt", "recordings", alias)
+}
+
+// recording_alias returns the worktree a cast was recorded in, from the
+// directory recordings_dir put it in; "" when it belongs to none.
+func recording_alias(path string) string {
+	alias := filepath.Base(filepath.Dir(path))
+	if alias == unassignedRecordings {
+		return ""
+	}
+	return alias
+}
+
+// wt_executable resolves the running wt binary (followed through symlinks).
+func wt_executable() string {
+	exe, _ := os.Executable()
+	exe, _ = filepath.EvalSymlinks(exe)
+	return exe
+}
+
+// toggle_recording starts or stops recording the session under the Active Tabs cursor.
+func (m Model) toggle_recording() (tea.Model, tea.Cmd) {
+	s := m.cursor_session()
+	if s == nil {
+		return m, nil
+	}
+
+	if path := s.StopRecording(); path != "" {
+		return m.show_notification("Recording", "Saved to "+path)
+	}
+	if !s.IsAlive() {
+		return m.show_notification("Recording", "Session has exited")
+	}
+
+	if _, err := s.StartRecording(wt_executable(), recordings_dir(s.WorktreeAlias)); err != nil {
+		return m.show_notification("Recording", fmt.Sprintf("Failed: %v", err))
+	}
+	m.activity = "Recording " + s.Label
+	return m, tick_after(3*time.Second, "clear-activity")
+}
+
+// open_recordings_picker lists the selected worktree's recordings, newest first.
+func (m Model) open_recordings_picker(wt worktree.Worktree) (Model, tea.Cmd) {
+	recs := cast.List(recordings_dir(wt.Alias))
+	if len(recs) == 0 {
+		return m.show_notification("Recordings", "No recordings for "+wt.Alias)
+	}
+	if len(recs) > maxRecordingsListed {
+		recs = recs[:maxRecordingsListed]
+	}
+
+	m.recordings = recs
+	actions := make([]ui.PickerAction, len(recs))
+	for i, r := range recs {
+		actions[i] = ui.PickerAction{
+			Key:   fmt.Sprintf("%d", i+1),
+			Label: r.Name,
+			Desc:  r.ModTime.Format("Jan 2 15:04"),
+		}
+	}
+	return m.open_panel_picker("Recordings", actions, pickerRecordings)
+}
+
+// execute_recordings_action replays the chosen recording in a new right-pane tab.
+func (m Model) execute_recordings_action(action ui.PickerAction) (Model, tea.Cmd) {
+	var idx int
+	fmt.Sscanf(action.Key, "%d", &idx)
+	idx--
+	if idx < 0 || idx >= len(m.recordings) {
+		return m, nil
+	}
+	rec := m.recordings[idx]
+	alias := recording_alias(rec.Path)
+	name := alias
+	if name == "" {
+		name = rec.Name
+	}
+
+	w, h := m.right_pane_dimensions()
+	s, err := m.term_mgr.OpenNew(labels.Tab(labels.Replay, name), wt_executable(), []string{"_replay", rec.Path}, w, h, "")
+	if err != nil {
+		m.terminal_output = fmt.Sprintf("Error: %v", err)
+		return m, nil
+	}
+	if wt := m.find_worktree_by_alias(alias); wt != nil {
+		s.SetWorktree(wt.Alias, wt.Path)
+	}
+	m.prev_focus = m.focus
+	m.focus = PanelTerminal
+	if m.pane_layout != nil {
+		m.pane_layout.FocusRight()
+	}
+	return m, tick_after(100*time.Millisecond, "render")
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordingsDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if got, want := recordings_dir("api"), filepath.Join(home, ".wt", "recordings", "api"); got != want {
+		t.Errorf("recordings_dir(api) = %q, want %q", got, want)
+	}
+	if got := recording_alias(filepath.Join(recordings_dir("api"), "shell.cast")); got != "api" {
+		t.Errorf("alias = %q, want api", got)
+	}
+
+	// Tabs outside any worktree don't record into the recordings root
+	dir := recordings_dir("")
+	if got, want := dir, filepath.Join(home, ".wt", "recordings", unassignedRecordings); got != want {
+		t.Errorf("recordings_dir() = %q, want %q", got, want)
+	}
+	if got := recording_alias(filepath.Join(dir, "shell.cast")); got != "" {
+		t.Errorf("an unassigned recording should have no alias, got %q", got)
+	}
+}
@@ -0,0 +1,439 @@
+package app
+
+import (
+	"github.com/elvisnm/wt/internal/labels"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// wtKind classifies a worktree by what can be done with it. Each kind used to
+// have its own hand-written action list; the registry filters by kind instead.
+type wtKind int
+
+const (
+	kindLocal        wtKind = iota // local, or docker without a container yet
+	kindLocalRunning               // local with the dev server up
+	kindDockerRunning
+	kindDockerStopped
+	kindHostBuildRunning
+	kindHostBuildStopped
+)
+
+func kind_of(wt worktree.Worktree) wtKind {
+	switch {
+	case wt.Type == worktree.TypeLocal && wt.Running:
+		return kindLocalRunning
+	case wt.Type == worktree.TypeLocal || !wt.ContainerExists:
+		return kindLocal
+	case wt.HostBuild && wt.Running:
+		return kindHostBuildRunning
+	case wt.HostBuild:
+		return kindHostBuildStopped
+	case wt.Running:
+		return kindDockerRunning
+	}
+	return kindDockerStopped
+}
+
+// worktreeAction is one entry in the worktree action registry. The action
+// picker, the quick keys in the worktrees panel, the help page and the command
+// palette are all generated from these entries.
+type worktreeAction struct {
+	id    string // stable name for keybindings ("worktree.<id>")
+	key   string
+	label string
+	desc  string
+	help  string // help page text; quick keys only
+
+	// describe overrides label and desc for a particular worktree
+	describe func(wt worktree.Worktree) (label, desc string)
+
+	// picker reports whether the action is offered in wt's action picker.
+	// nil keeps the action out of pickers.
+	picker func(m *Model, wt worktree.Worktree) bool
+
+	// quick reports whether the key works directly in the worktrees panel.
+	// nil means the action has no quick key.
+	quick func(m *Model, wt worktree.Worktree) bool
+
+	// feature is the config feature flag (features.<name>) the action
+	// needs, "" for none. Off, it's left out of pickers and quick keys.
+	feature string
+
+	// sso checks the AWS SSO session first, logging in when it has expired.
+	// It names the pending action resolve_sso_action resumes once the session
+	// is valid; "" skips the check.
+	sso string
+
+	run func(m Model, wt worktree.Worktree) (Model, tea.Cmd)
+}
+
+// enabled reports whether a's feature flag is on. Without a config every
+// feature counts as on, as for the global keys.
+func (a worktreeAction) enabled(m *Model) bool {
+	return a.feature == "" || m.cfg == nil || m.cfg.FeatureEnabled(a.feature)
+}
+
+// in_picker reports whether a is offered in wt's action picker.
+func (a worktreeAction) in_picker(m *Model, wt worktree.Worktree) bool {
+	return a.picker != nil && a.enabled(m) && a.picker(m, wt)
+}
+
+// quick_key reports whether a's key works on wt in the worktrees panel.
+func (a worktreeAction) quick_key(m *Model, wt worktree.Worktree) bool {
+	return a.quick != nil && a.enabled(m) && a.quick(m, wt)
+}
+
+// invoke runs a on wt, behind the SSO check when it has one.
+func (a worktreeAction) invoke(m Model, wt worktree.Worktree) (Model, tea.Cmd) {
+	if a.sso != "" {
+		if m, cmd, gated := m.sso_gate(a.sso, &wt); gated {
+			return m, cmd
+		}
+	}
+	return a.run(m, wt)
+}
+
+// kinds returns a predicate matching worktrees of the given kinds.
+func kinds(ks ...wtKind) func(m *Model, wt worktree.Worktree) bool {
+	return func(m *Model, wt worktree.Worktree) bool {
+		k := kind_of(wt)
+		for _, want := range ks {
+			if k == want {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func always(m *Model, wt worktree.Worktree) bool       { return true }
+func when_running(m *Model, wt worktree.Worktree) bool { return wt.Running }
+func when_stopped(m *Model, wt worktree.Worktree) bool { return !wt.Running }
+func in_set(m *Model, wt worktree.Worktree) bool       { return len(wt.Set) > 0 }
+
+// worktree_actions is the registry, in picker order.
+var worktree_actions []worktreeAction
+
+// worktree_default_keys maps action IDs to their built-in keys, before
+// user keybindings are applied.
+var worktree_default_keys = map[string]string{}
+
+func init() {
+	worktree_actions = []worktreeAction{
+		{
+			id: "start", key: "u", label: "Start", desc: "Start container", help: "start",
+			describe: func(wt worktree.Worktree) (string, string) {
+				switch kind_of(wt) {
+				case kindLocal:
+					return "Start", "Start dev server"
+				case kindHostBuildStopped:
+					return "Start + Build", "Container + esbuild"
+				}
+				return "Start", "Start container"
+			},
+			picker: kinds(kindLocal, kindDockerStopped, kindHostBuildStopped),
+			quick:  when_stopped,
+			sso:    "start",
+			run:    Model.start_worktree,
+		},
+		{
+			id: "build", key: "e", label: labels.Build, desc: "Esbuild watch", help: "esbuild watch",
+			picker:  kinds(kindHostBuildRunning),
+			quick:   kinds(kindHostBuildRunning),
+			feature: "hostBuild",
+			run:     Model.open_esbuild_watch,
+		},
+		{
+			id: "shell", key: "b", label: labels.Shell, desc: "Worktree shell", help: "bash shell",
+			describe: func(wt worktree.Worktree) (string, string) {
+				if k := kind_of(wt); k == kindDockerRunning || k == kindHostBuildRunning {
+					return labels.Shell, "Container shell"
+				}
+				return labels.Shell, "Worktree shell"
+			},
+			picker: kinds(kindLocal, kindLocalRunning, kindDockerRunning, kindHostBuildRunning),
+			quick:  always,
+			run:    Model.open_bash,
+		},
+		{
+			id: "zsh", key: "z", label: labels.Zsh, desc: "Host shell", help: "local shell (zsh)",
+			picker: kinds(kindDockerRunning, kindDockerStopped, kindHostBuildRunning, kindHostBuildStopped),
+			quick:  always,
+			run:    Model.open_local_shell,
+		},
+		{
+			id: "claude", key: "c", label: labels.Claude, desc: "Claude Code", help: "claude code",
+			picker: always,
+			quick:  always,
+			run:    Model.open_claude,
+		},
+		{
+			id: "claude_auto", key: "C", label: ui.ActionClaudeAuto.Label, desc: ui.ActionClaudeAuto.Desc,
+			picker: func(m *Model, wt worktree.Worktree) bool { return !m.claude_auto_mode },
+			run:    Model.open_claude_auto,
+		},
+		{
+			id: "pull", key: "g", label: labels.Pull, desc: "Pull latest changes", help: "pull latest",
+			picker: always,
+			quick:  always,
+			run:    Model.open_pull,
+		},
+		{
+			id: "diff", key: "V", label: labels.Diff, desc: "Changes against the base branch", help: "diff vs base",
+			picker: func(m *Model, wt worktree.Worktree) bool { return !wt.Prunable },
+			quick:  func(m *Model, wt worktree.Worktree) bool { return !wt.Prunable },
+			run:    Model.open_diff,
+		},
+		{
+			id: "commits", key: "G", label: labels.Commits, desc: "Commit graph against the base branch", help: "commit graph",
+			picker: func(m *Model, wt worktree.Worktree) bool { return !wt.Prunable },
+			quick:  func(m *Model, wt worktree.Worktree) bool { return !wt.Prunable },
+			run:    Model.open_commits,
+		},
+		{
+			id: "checkpoints", key: "Z", label: labels.Checkpoints, desc: "Snapshots of its files to diff and roll back to",
+			picker: func(m *Model, wt worktree.Worktree) bool { return !wt.Prunable },
+			run:    Model.open_checkpoints,
+		},
+		{
+			id: "logs", key: "l", label: labels.Logs, desc: "Dev logs", help: "logs",
+			picker: kinds(kindLocalRunning),
+			quick:  always,
+			run:    Model.open_logs,
+		},
+		{
+			id: "start_service", key: "o", label: "Start service", desc: "Start a stopped service",
+			picker: func(m *Model, wt worktree.Worktree) bool {
+				has_stopped, _ := m.service_availability()
+				return kind_of(wt) == kindLocalRunning && has_stopped
+			},
+			run: Model.open_start_service_picker,
+		},
+		{
+			id: "stop_service", key: "p", label: "Stop service", desc: "Stop a running service",
+			picker: func(m *Model, wt worktree.Worktree) bool {
+				_, has_running := m.service_availability()
+				return kind_of(wt) == kindLocalRunning && has_running
+			},
+			run: Model.open_stop_service_picker,
+		},
+		{
+			id: "switch_mode", key: "m", label: "Switch mode", desc: "Toggle minimal/full",
+			picker: func(m *Model, wt worktree.Worktree) bool {
+				k := kind_of(wt)
+				return (k == kindLocal || k == kindLocalRunning) && m.has_modes()
+			},
+			run: Model.switch_mode,
+		},
+		{
+			id: "restart", key: "r", label: "Restart", desc: "Restart container", help: "restart",
+			describe: func(wt worktree.Worktree) (string, string) {
+				if kind_of(wt) == kindLocalRunning {
+					return "Restart", "Restart services"
+				}
+				return "Restart", "Restart container"
+			},
+			picker: kinds(kindLocalRunning, kindDockerRunning, kindHostBuildRunning),
+			quick:  when_running,
+			// SSO check before restarting — ensures credentials are valid
+			sso: "restart",
+			run: func(m Model, wt worktree.Worktree) (Model, tea.Cmd) {
+				if wt.Type == worktree.TypeLocal {
+					return m.restart_local_services(wt)
+				}
+				if wt.HostBuild {
+					return m.restart_host_build(wt)
+				}
+				return m, cmd_docker_action("restart", wt, m.repo_root, m.cfg)
+			},
+		},
+		{
+			id: "create", key: "n", label: labels.Create, desc: "Create container", help: "create worktree",
+			picker: kinds(kindLocal),
+			quick:  always,
+			sso:    "create",
+			run: func(m Model, wt worktree.Worktree) (Model, tea.Cmd) {
+				return m.open_quick_create()
+			},
+		},
+		{
+			id: "info", key: "i", label: "Info", desc: "Worktree info", help: "info",
+			picker: kinds(kindLocal, kindLocalRunning),
+			quick:  always,
+			run: func(m Model, wt worktree.Worktree) (Model, tea.Cmd) {
+				return m.open_worktree_info()
+			},
+		},
+		{
+			id: "stop", key: "t", label: "Stop", desc: "Stop container", help: "stop",
+			describe: func(wt worktree.Worktree) (string, string) {
+				if kind_of(wt) == kindLocalRunning {
+					return "Stop", "Stop dev server"
+				}
+				return "Stop", "Stop container"
+			},
+			picker: kinds(kindLocalRunning, kindDockerRunning, kindHostBuildRunning),
+			quick:  when_running,
+			run: func(m Model, wt worktree.Worktree) (Model, tea.Cmd) {
+				if wt.Type == worktree.TypeLocal {
+					return m.stop_dev_server(wt)
+				}
+				if wt.HostBuild {
+					return m.stop_host_build(wt)
+				}
+				return m.stop_set(wt, cmd_docker_action("stop", wt, m.repo_root, m.cfg))
+			},
+		},
+		{
+			id: "pin", key: "P", label: "Pin", desc: "Keep at the top of the list", help: "pin / unpin",
+			describe: func(wt worktree.Worktree) (string, string) {
+				if wt.Pinned {
+					return "Unpin", "Sort with the rest again"
+				}
+				return "Pin", "Keep at the top of the list"
+			},
+			picker: always,
+			quick:  always,
+			run:    Model.toggle_pin,
+		},
+		{
+			id: "note", key: "N", label: "Note", desc: "Attach a short note", help: "edit note",
+			describe: func(wt worktree.Worktree) (string, string) {
+				if wt.Note != "" {
+					return "Edit note", wt.Note
+				}
+				return "Note", "Attach a short note"
+			},
+			picker: always,
+			quick:  always,
+			run:    Model.open_note,
+		},
+		{
+			id: "rename", key: "E", label: "Rename", desc: "Change the alias, branch and everything named after them",
+			picker: func(m *Model, wt worktree.Worktree) bool { return !wt.Prunable },
+			run:    Model.open_rename,
+		},
+		{
+			id: "duplicate", key: "Y", label: "Duplicate", desc: "New branch and worktree from this one, database included",
+			picker: func(m *Model, wt worktree.Worktree) bool { return !wt.Prunable && !wt.Detached && wt.Branch != "" },
+			run:    Model.open_duplicate,
+		},
+		{
+			id: "sparse", key: "I", label: "Sparse checkout", desc: "Narrow or widen the directories checked out",
+			describe: describe_sparse,
+			picker:   sparse_offered,
+			run:      Model.open_sparse,
+		},
+		{
+			id: "set", key: "=", label: "Set", desc: "Show or hide the related repos' worktrees", help: "expand / collapse set",
+			picker: in_set,
+			quick:  in_set,
+			run:    Model.toggle_set,
+		},
+		{
+			id: "lock", key: "O", label: "Lock", desc: "Keep git from pruning or removing it",
+			describe: func(wt worktree.Worktree) (string, string) {
+				if wt.Locked && wt.LockReason != "" {
+					return "Unlock", wt.LockReason
+				}
+				if wt.Locked {
+					return "Unlock", "Allow prune and remove again"
+				}
+				return "Lock", "Keep git from pruning or removing it"
+			},
+			picker: func(m *Model, wt worktree.Worktree) bool { return !wt.Prunable },
+			run:    Model.toggle_lock,
+		},
+		{
+			id: "prune", key: "R", label: "Prune", desc: "Drop stale entries (directory is gone)",
+			picker: func(m *Model, wt worktree.Worktree) bool { return wt.Prunable && !wt.Locked },
+			run:    Model.prune_worktrees,
+		},
+		{
+			id: "remove", key: "x", label: labels.Remove, desc: "Remove worktree", help: "remove worktree",
+			picker: always,
+			quick:  always,
+			run:    Model.remove_worktree,
+		},
+
+		// Quick keys without a picker entry
+		{
+			id: "details", key: "d", label: "Details", desc: "Toggle details panel", help: "details toggle",
+			quick: always,
+			run: func(m Model, wt worktree.Worktree) (Model, tea.Cmd) {
+				next, cmd := m.toggle_details()
+				return next.(Model), cmd
+			},
+		},
+		{
+			id: "recordings", key: "v", label: "Recordings", desc: "Replay a recording", help: "recordings",
+			quick: always,
+			run:   Model.open_recordings_picker,
+		},
+		{
+			id: "template", key: "W", label: "Template", desc: "Open a workspace template", help: "workspace template",
+			quick: always,
+			run:   Model.open_template_picker,
+		},
+		{
+			id: "broadcast", key: "!", label: "Broadcast", desc: "Run a command in selected worktrees", help: "broadcast command",
+			quick: always,
+			run: func(m Model, wt worktree.Worktree) (Model, tea.Cmd) {
+				return m.open_broadcast_picker()
+			},
+		},
+	}
+	for _, a := range worktree_actions {
+		worktree_default_keys[a.id] = a.key
+	}
+}
+
+// picker_action returns the picker entry for a on wt.
+func (a worktreeAction) picker_action(wt worktree.Worktree) ui.PickerAction {
+	label, desc := a.label, a.desc
+	if a.describe != nil {
+		label, desc = a.describe(wt)
+	}
+	return ui.PickerAction{Key: a.key, Label: label, Desc: desc}
+}
+
+// find_worktree_action looks up a registry entry by key.
+func find_worktree_action(key string) (worktreeAction, bool) {
+	for _, a := range worktree_actions {
+		if a.key == key {
+			return a, true
+		}
+	}
+	return worktreeAction{}, false
+}
+
+// find_sso_action looks up the registry entry an SSO check was pending for.
+func find_sso_action(sso string) (worktreeAction, bool) {
+	for _, a := range worktree_actions {
+		if a.sso == sso {
+			return a, true
+		}
+	}
+	return worktreeAction{}, false
+}
+
+// WorktreeKeyHelp lists the worktree panel's quick keys for the help page,
+// as currently bound, with shifted letters spelled out ("W" → "Shift+W").
+func WorktreeKeyHelp() []ui.HintPair {
+	items := []ui.HintPair{{Key: KeyHelp("enter"), Desc: "action menu"}}
+	for _, a := range worktree_actions {
+		if a.quick == nil || a.help == "" {
+			continue
+		}
+		items = append(items, ui.HintPair{Key: DisplayKey(a.key), Desc: a.help})
+	}
+	return append(items,
+		ui.HintPair{Key: KeyHelp("filter"), Desc: "filter list"},
+		ui.HintPair{Key: KeyHelp("list_view"), Desc: "filters, grouping & sort"},
+		ui.HintPair{Key: KeyHelp("mark"), Desc: "mark for bulk actions"},
+		ui.HintPair{Key: KeyHelp("mark_all"), Desc: "mark all listed"},
+	)
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRegistryLocalActions(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Services.Modes = map[string][]string{"minimal": nil, "full": nil}
+	m := &Model{cfg: cfg, claude_auto_mode: true}
+
+	stopped := m.actions_for_worktree(worktree.Worktree{Type: worktree.TypeLocal})
+	if keys := action_keys(stopped); keys != "ubcgVGZmniPNEOx" {
+		t.Errorf("local stopped: got %q, want %q", keys, "ubcgVGZmniPNEOx")
+	}
+	if stopped[0].Desc != "Start dev server" {
+		t.Errorf("local Start desc = %q", stopped[0].Desc)
+	}
+
+	running := m.actions_for_worktree(worktree.Worktree{Type: worktree.TypeLocal, Running: true})
+	if keys := action_keys(running); keys != "bcgVGZlmritPNEOx" {
+		t.Errorf("local running: got %q, want %q", keys, "bcgVGZlmritPNEOx")
+	}
+	for _, a := range running {
+		if a.Key == "t" && a.Desc != "Stop dev server" {
+			t.Errorf("local Stop desc = %q", a.Desc)
+		}
+	}
+}
+
+func TestRegistryKeysUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, a := range worktree_actions {
+		if seen[a.key] {
+			t.Errorf("duplicate registry key %q", a.key)
+		}
+		seen[a.key] = true
+		if a.run == nil {
+			t.Errorf("action %q has no handler", a.key)
+		}
+	}
+}
+
+func TestRegistryQuickKeyGating(t *testing.T) {
+	m := &Model{}
+	stopped := worktree.Worktree{Type: worktree.TypeDocker, ContainerExists: true}
+	running := stopped
+	running.Running = true
+
+	cases := []struct {
+		key  string
+		wt   worktree.Worktree
+		want bool
+	}{
+		{"r", running, true},
+		{"r", stopped, false},
+		{"t", stopped, false},
+		{"u", stopped, true},
+		{"u", running, false},
+		{"e", running, false}, // not a host-build worktree
+		{"C", running, false}, // picker only
+		{"W", stopped, true},
+	}
+	for _, c := range cases {
+		a, ok := find_worktree_action(c.key)
+		if !ok {
+			t.Fatalf("no registry entry for %q", c.key)
+		}
+		if got := a.quick != nil && a.quick(m, c.wt); got != c.want {
+			t.Errorf("quick %q (running=%v) = %v, want %v", c.key, c.wt.Running, got, c.want)
+		}
+	}
+}
+
+func TestWorktreeKeyHelp(t *testing.T) {
+	help := WorktreeKeyHelp()
+	if help[0].Key != "Enter" {
+		t.Errorf("first help entry = %q, want Enter", help[0].Key)
+	}
+	keys := map[string]string{}
+	for _, h := range help {
+		keys[h.Key] = h.Desc
+	}
+	if keys["Shift+W"] == "" {
+		t.Error("expected W spelled as Shift+W")
+	}
+	if _, ok := keys["C"]; ok {
+		t.Error("picker-only action should not be in the help page")
+	}
+	for _, a := range worktree_actions {
+		if a.quick != nil && a.help == "" {
+			t.Errorf("quick key %q has no help text", a.key)
+		}
+	}
+}
+
+func TestRegistryFeatureGate(t *testing.T) {
+	cfg := &config.Config{}
+	m := &Model{cfg: cfg}
+	wt := worktree.Worktree{Type: worktree.TypeDocker, ContainerExists: true, HostBuild: true, Running: true}
+
+	build, _ := find_worktree_action_by_id("build")
+	if build.in_picker(m, wt) || build.quick_key(m, wt) {
+		t.Error("esbuild watch offered with hostBuild off")
+	}
+	cfg.Features.HostBuild = true
+	if !build.in_picker(m, wt) || !build.quick_key(m, wt) {
+		t.Error("esbuild watch hidden with hostBuild on")
+	}
+
+	if _, ok := m.find_global_action(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")}); ok {
+		t.Error("LAN toggle enabled with the lan feature off")
+	}
+}
+
+func TestHelpKeys(t *testing.T) {
+	keys := HelpKeys()
+	descs := func(items []ui.HintPair) map[string]string {
+		out := map[string]string{}
+		for _, h := range items {
+			out[h.Key] = h.Desc
+		}
+		return out
+	}
+	if tabs := descs(keys.Tabs); tabs["r"] != "rename tab" || tabs["Shift+R"] != "rerun finished tab" || keys.Tabs[0].Key != "Enter" {
+		t.Errorf("tab help = %+v", keys.Tabs)
+	}
+	if descs(keys.Services)["t"] != "stop service" {
+		t.Errorf("service help = %+v", keys.Services)
+	}
+	if descs(keys.Operations)["Shift+L"] != "LAN toggle" || keys.TasksKey != "Shift+T" {
+		t.Errorf("operations help = %+v, tasks key %q", keys.Operations, keys.TasksKey)
+	}
+}
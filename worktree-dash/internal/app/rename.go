@@ -0,0 +1,527 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/aws"
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// renamePlan is a worktree rename, worked out before anything changes.
+type renamePlan struct {
+	wt        worktree.Worktree // as it was when the rename was planned
+	alias     string
+	branch    string // new branch name; "" keeps the branch and directory
+	path      string // directory after the rename
+	name      string // worktree name after the rename (the directory's name)
+	env       []renameVar
+	old_db    string // database copied to new_db; both "" when it isn't renamed
+	new_db    string
+	container string // container name after the rename; "" for local worktrees
+	domain    string
+	old_vol   string // node_modules volume copied to new_vol; both "" without a container
+	new_vol   string
+}
+
+// renameVar is one env var the rename rewrites.
+type renameVar struct {
+	key, from, to string
+}
+
+// renameStep is one step of a rename: commands run in the repo root, or an
+// in-process apply. undo and revert take a finished step back when a later
+// one fails; a step with neither stays done. An optional step that fails is
+// shown as skipped.
+type renameStep struct {
+	label    string
+	detail   string // the change, shown in the plan
+	optional bool
+	cmds     [][]string
+	apply    func() (string, error)
+	undo     [][]string
+	revert   func() error
+}
+
+// renameRun is a planned rename: shown until confirmed, then its steps run
+// one after another. A failed step rolls the finished ones back in reverse.
+type renameRun struct {
+	plan        renamePlan
+	steps       []renameStep
+	state       []ui.Step
+	started     bool
+	current     int
+	failed      bool // a step failed; current counts down through the rollback
+	undo_failed bool
+}
+
+// MsgRenameStep reports that step Index of the running rename finished, or
+// was rolled back when Undo is set.
+type MsgRenameStep struct {
+	Index  int
+	Undo   bool
+	Output string
+	Err    error
+}
+
+// volume_copy_image runs the throwaway container that copies a volume.
+const volume_copy_image = "alpine"
+
+// node_modules_volume is the name generate-docker-compose.js gives a
+// worktree's node_modules volume.
+func node_modules_volume(cfg *config.Config, alias string) string {
+	return cfg.VolumePrefix(alias) + "_node_modules"
+}
+
+// rename_exec runs one command of a rename step. Tests replace it.
+var rename_exec = run_host_cmd_env_dir
+
+// worktree_env_filename returns the worktree env file's name.
+func worktree_env_filename(cfg *config.Config) string {
+	if cfg != nil && cfg.Env.Filename != "" {
+		return cfg.Env.Filename
+	}
+	return ".env.worktree"
+}
+
+// plan_rename works out what renaming wt to alias changes: env vars, the
+// database (an isolated one named after the alias), container, its
+// node_modules volume and domain, and with a new branch, the branch and its
+// directory.
+func plan_rename(wt worktree.Worktree, alias, branch, worktrees_dir string, cfg *config.Config) renamePlan {
+	p := renamePlan{wt: wt, alias: alias, branch: branch, path: wt.Path, name: wt.Name}
+	if branch != "" {
+		p.path = worktree_dir_for(worktrees_dir, branch)
+		p.name = filepath.Base(p.path)
+	}
+
+	filename := worktree_env_filename(cfg)
+	read := func(key string) string { return worktree.ReadEnvVar(wt.Path, filename, key) }
+	alias_var := "WORKTREE_ALIAS"
+	if cfg != nil && cfg.WorktreeVar("alias") != "" {
+		alias_var = cfg.WorktreeVar("alias")
+	}
+	p.env = append(p.env, renameVar{alias_var, wt.Alias, alias})
+	if cfg == nil {
+		return p
+	}
+
+	if wt.Type == worktree.TypeDocker {
+		p.container = cfg.ContainerName(alias)
+		// The volume only exists once the container has been created
+		if wt.ContainerExists {
+			p.old_vol, p.new_vol = node_modules_volume(cfg, wt.Alias), node_modules_volume(cfg, alias)
+		}
+	}
+	p.domain = cfg.DomainFor(alias)
+
+	// Only an isolated database is named after the alias; a shared one stays
+	if has_local_db(cfg) && wt.DBName != "" && wt.DBName == cfg.DbName(wt.Alias) {
+		p.old_db, p.new_db = wt.DBName, cfg.DbName(alias)
+		if key := cfg.EnvVar("dbConnection"); key != "" {
+			if url := read(key); strings.HasSuffix(url, "/"+p.old_db) {
+				p.env = append(p.env, renameVar{key, url, strings.TrimSuffix(url, p.old_db) + p.new_db})
+			}
+		}
+	}
+
+	if old_domain := cfg.DomainFor(wt.Alias); old_domain != "" {
+		for _, name := range []string{"localIp", "appUrl"} {
+			if key := cfg.EnvVar(name); key != "" {
+				if v := read(key); strings.Contains(v, old_domain) {
+					p.env = append(p.env, renameVar{key, v, strings.ReplaceAll(v, old_domain, p.domain)})
+				}
+			}
+		}
+	}
+	// LAN domains are <alias>.<ip>.nip.io
+	if key := cfg.EnvVar("lanDomain"); key != "" {
+		if v := read(key); strings.HasPrefix(v, wt.Alias+".") {
+			p.env = append(p.env, renameVar{key, v, alias + strings.TrimPrefix(v, wt.Alias)})
+		}
+	}
+	return p
+}
+
+// rename_steps turns a plan into the steps that carry it out. A running
+// worktree is stopped first (which drops its proxy route) and brought back
+// up under the new alias last, so dc-worktree-up regenerates the compose
+// file, container and route from the rewritten env file, on a copy of the
+// node_modules volume. The old database and volume are removed only once
+// everything else is through.
+func rename_steps(p renamePlan, repo_root, scripts_dir, filename string, remove_container bool) []renameStep {
+	wt := p.wt
+	git := func(args ...string) []string { return append([]string{"git", "-C", repo_root}, args...) }
+	node := func(script string, args ...string) []string {
+		return append([]string{"node", filepath.Join(scripts_dir, script)}, args...)
+	}
+	up := func(name string) []string {
+		if wt.Type == worktree.TypeLocal {
+			return node("dc-worktree-up.js", name, "--no-docker")
+		}
+		return node("dc-worktree-up.js", name)
+	}
+	var steps []renameStep
+
+	switch {
+	case wt.Running:
+		stop := renameStep{label: "Stop " + wt.Alias, detail: "services and proxy route",
+			cmds: [][]string{node("dc-worktree-down.js", wt.Name)},
+			undo: [][]string{up(wt.Name)}}
+		if remove_container {
+			stop.detail = "container and proxy route"
+			stop.cmds = append(stop.cmds, []string{"docker", "rm", "-f", wt.Container})
+		}
+		steps = append(steps, stop)
+	case remove_container:
+		// Not taken back on failure: the next start creates it again
+		steps = append(steps, renameStep{label: "Remove container", detail: wt.Container,
+			cmds: [][]string{{"docker", "rm", "-f", wt.Container}}})
+	}
+
+	if p.branch != "" {
+		steps = append(steps,
+			renameStep{label: "Rename branch", detail: wt.Branch + " → " + p.branch,
+				cmds: [][]string{git("branch", "-m", wt.Branch, p.branch)},
+				undo: [][]string{git("branch", "-m", p.branch, wt.Branch)}},
+			renameStep{label: "Move directory", detail: wt.Name + " → " + p.name,
+				cmds: [][]string{git("worktree", "move", wt.Path, p.path)},
+				undo: [][]string{git("worktree", "move", p.path, wt.Path)}})
+	}
+
+	var keys []string
+	for _, v := range p.env {
+		keys = append(keys, v.key)
+	}
+	env_path := filepath.Join(p.path, filename)
+	var saved []byte
+	steps = append(steps, renameStep{label: "Update " + filename, detail: strings.Join(keys, ", "),
+		apply: func() (string, error) {
+			data, err := os.ReadFile(env_path)
+			if err != nil {
+				return "", err
+			}
+			saved = data
+			for _, v := range p.env {
+				if err := worktree.WriteEnvVar(p.path, filename, v.key, v.to); err != nil {
+					return "", err
+				}
+			}
+			return "", nil
+		},
+		revert: func() error { return os.WriteFile(env_path, saved, 0644) },
+	})
+
+	if p.new_db != "" {
+		steps = append(steps, renameStep{label: "Copy database", detail: p.old_db + " → " + p.new_db,
+			cmds: [][]string{node("dc-seed.js", "--db="+p.new_db, "--from="+p.old_db)},
+			undo: [][]string{node("dc-seed.js", "--db="+p.new_db, "--drop")}})
+	}
+
+	if p.new_vol != "" {
+		steps = append(steps, renameStep{label: "Copy volume", detail: p.old_vol + " → " + p.new_vol,
+			cmds: [][]string{
+				{"docker", "volume", "create", p.new_vol},
+				{"docker", "run", "--rm", "-v", p.old_vol + ":/from", "-v", p.new_vol + ":/to",
+					volume_copy_image, "cp", "-a", "/from/.", "/to/"},
+			},
+			undo: [][]string{{"docker", "volume", "rm", "-f", p.new_vol}}})
+	}
+
+	if wt.Running {
+		restart := renameStep{label: "Restart services", detail: p.domain,
+			cmds: [][]string{up(p.name)},
+			undo: [][]string{node("dc-worktree-down.js", p.name)}}
+		if wt.Type == worktree.TypeDocker {
+			restart.label = "Recreate container"
+			restart.detail = strings.TrimSuffix(p.container+", "+p.domain, ", ")
+			if remove_container {
+				restart.undo = append(restart.undo, []string{"docker", "rm", "-f", p.container})
+			}
+		}
+		steps = append(steps, restart)
+	}
+
+	if p.new_db != "" {
+		steps = append(steps, renameStep{label: "Drop old database", detail: p.old_db, optional: true,
+			cmds: [][]string{node("dc-seed.js", "--db="+p.old_db, "--drop")}})
+	}
+	if p.new_vol != "" {
+		// Fails while the old container still uses it
+		steps = append(steps, renameStep{label: "Remove old volume", detail: p.old_vol, optional: true,
+			cmds: [][]string{{"docker", "volume", "rm", p.old_vol}}})
+	}
+	return steps
+}
+
+// open_rename starts renaming a worktree: it asks for the new alias, then
+// whether the branch and directory follow, then shows the plan.
+func (m Model) open_rename(wt worktree.Worktree) (Model, tea.Cmd) {
+	if m.rename != nil {
+		return m.show_notification("Rename", fmt.Sprintf("Still renaming %s", m.rename.plan.wt.Alias))
+	}
+	return m.prompt_rename_alias(wt, wt.Alias, "")
+}
+
+// can_move_worktree reports whether git can rename wt's branch and move it
+// to the directory dc-worktree-up expects for the new branch.
+func can_move_worktree(wt worktree.Worktree) bool {
+	return wt.Branch != "" && !wt.Detached && !wt.Outside && !wt.Locked
+}
+
+// prompt_rename_alias asks for the new alias. A rejected one is asked for
+// again, with the problem in the prompt.
+func (m Model) prompt_rename_alias(wt worktree.Worktree, value, problem string) (Model, tea.Cmd) {
+	prompt := fmt.Sprintf("New alias for %s:", wt.Alias)
+	if problem != "" {
+		prompt = fmt.Sprintf("%s — new alias for %s:", problem, wt.Alias)
+	}
+	m, cmd := m.open_panel_input("Rename", prompt, func(mdl *Model, value string) (Model, tea.Cmd) {
+		alias := strings.TrimSpace(value)
+		problem := validate_alias(alias, mdl.worktrees)
+		if alias == wt.Alias {
+			problem = "That is the current alias"
+		}
+		if problem != "" {
+			return mdl.prompt_rename_alias(wt, value, problem)
+		}
+		if !can_move_worktree(wt) {
+			return mdl.show_rename_plan(plan_rename(wt, alias, "", mdl.worktrees_dir, mdl.cfg))
+		}
+		mdl.rename_plan = renamePlan{wt: wt, alias: alias}
+		return mdl.open_panel_picker("Rename", []ui.PickerAction{
+			{Key: "a", Label: "Alias only", Desc: "Keep " + wt.Branch},
+			{Key: "b", Label: "Alias and branch", Desc: "Rename the branch and move the directory"},
+		}, pickerRename)
+	})
+	m.input_value = value
+	return m, cmd
+}
+
+// execute_rename_choice plans an alias-only rename, or asks for the new
+// branch name first.
+func (m Model) execute_rename_choice(action ui.PickerAction) (Model, tea.Cmd) {
+	p := m.rename_plan
+	m.rename_plan = renamePlan{}
+	if action.Key == "b" {
+		return m.prompt_rename_branch(p.wt, p.alias, p.wt.Branch, "")
+	}
+	return m.show_rename_plan(plan_rename(p.wt, p.alias, "", m.worktrees_dir, m.cfg))
+}
+
+// prompt_rename_branch asks for the branch's new name.
+func (m Model) prompt_rename_branch(wt worktree.Worktree, alias, value, problem string) (Model, tea.Cmd) {
+	prompt := fmt.Sprintf("Rename branch %s to:", wt.Branch)
+	if problem != "" {
+		prompt = fmt.Sprintf("%s — rename branch %s to:", problem, wt.Branch)
+	}
+	m, cmd := m.open_panel_input("Rename", prompt, func(mdl *Model, value string) (Model, tea.Cmd) {
+		branch := strings.TrimSpace(value)
+		problem := validate_branch(branch, branch_prefixes(mdl.cfg))
+		if problem == "" && branch == wt.Branch {
+			problem = "That is the current branch"
+		}
+		if problem == "" {
+			problem = branch_taken(mdl.repo_root, mdl.worktrees_dir, branch)
+		}
+		if problem != "" {
+			return mdl.prompt_rename_branch(wt, alias, value, problem)
+		}
+		return mdl.show_rename_plan(plan_rename(wt, alias, branch, mdl.worktrees_dir, mdl.cfg))
+	})
+	m.input_value = value
+	return m, cmd
+}
+
+// show_rename_plan lists the steps of a rename with what each changes,
+// waiting for Enter to apply them.
+func (m Model) show_rename_plan(p renamePlan) (Model, tea.Cmd) {
+	remove_container := p.wt.Type == worktree.TypeDocker && p.wt.ContainerExists &&
+		(m.cfg == nil || m.cfg.Docker.ComposeStrategy == "generate")
+	steps := rename_steps(p, m.repo_root, flow_scripts_dir(m.repo_root, m.cfg), worktree_env_filename(m.cfg), remove_container)
+	run := &renameRun{plan: p, steps: steps, state: make([]ui.Step, len(steps))}
+	for i, s := range steps {
+		run.state[i] = ui.Step{Label: s.label, Detail: s.detail}
+	}
+	m.rename = run
+	m.recalc_layout()
+	return m, nil
+}
+
+// handle_rename_plan_key applies the shown plan on Enter or drops it on Esc.
+func (m Model) handle_rename_plan_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		return m.start_rename()
+	case "esc", "q", "ctrl+c":
+		m.rename = nil
+		m.recalc_layout()
+	}
+	return m, nil
+}
+
+// start_rename runs a confirmed rename's steps, showing their progress in
+// the notification area.
+func (m Model) start_rename() (Model, tea.Cmd) {
+	run := m.rename
+	if run.plan.wt.Running {
+		// Refresh AWS credentials so the restarted services get the latest keys
+		aws.Refresh(m.sso_profile())
+	}
+	run.started = true
+	run.state[0].State = ui.StepRunning
+	m.activity = fmt.Sprintf("Renaming %s...", run.plan.wt.Alias)
+	m.recalc_layout()
+	return m, cmd_rename_step(0, run.steps[0], false, m.repo_root)
+}
+
+// cmd_rename_step runs a step, or takes it back, stopping at the first
+// command that fails.
+func cmd_rename_step(index int, step renameStep, undo bool, repo_root string) tea.Cmd {
+	return func() tea.Msg {
+		var out string
+		var err error
+		apply, cmds := step.apply, step.cmds
+		if undo {
+			apply, cmds = nil, step.undo
+			if step.revert != nil {
+				apply = func() (string, error) { return "", step.revert() }
+			}
+		}
+		if apply != nil {
+			out, err = apply()
+		}
+		for _, c := range cmds {
+			// WT_INNER keeps dc-worktree-up from starting dev servers; the
+			// dashboard starts them once the worktree is discovered
+			if out, err = rename_exec(repo_root, []string{"WT_INNER=1"}, c[0], c[1:]...); err != nil {
+				break
+			}
+		}
+		return MsgRenameStep{Index: index, Undo: undo, Output: out, Err: err}
+	}
+}
+
+// done reports whether the run has finished: through every step, or rolled
+// back after a failure.
+func (r *renameRun) done() bool {
+	if r.failed {
+		return r.current < 0
+	}
+	return r.current >= len(r.steps)
+}
+
+// handle_rename_step records a finished step and starts the next one. A
+// failed step starts the rollback.
+func (m Model) handle_rename_step(msg MsgRenameStep) (Model, tea.Cmd) {
+	run := m.rename
+	if run == nil || !run.started || run.done() || msg.Index != run.current || msg.Undo != run.failed {
+		return m, nil
+	}
+	state := &run.state[msg.Index]
+	step := run.steps[msg.Index]
+	reason := func() string { return first_nonempty(last_line(msg.Output), fmt.Sprint(msg.Err)) }
+
+	if msg.Undo {
+		state.State, state.Detail = ui.StepSkipped, "rolled back"
+		if msg.Err != nil {
+			debug_log("[rename] rolling back %s failed: %v\n%s", step.label, msg.Err, msg.Output)
+			state.State, state.Detail = ui.StepFailed, "rollback failed: "+reason()
+			run.undo_failed = true
+		}
+		return m.next_rename_undo()
+	}
+
+	switch {
+	case msg.Err != nil && step.optional:
+		state.State, state.Detail = ui.StepSkipped, reason()
+	case msg.Err != nil:
+		debug_log("[rename] %s failed: %v\n%s", step.label, msg.Err, msg.Output)
+		state.State, state.Detail = ui.StepFailed, reason()
+		run.failed = true
+		return m.next_rename_undo()
+	default:
+		state.State = ui.StepDone
+	}
+
+	run.current++
+	if run.current < len(run.steps) {
+		run.state[run.current].State = ui.StepRunning
+		return m, cmd_rename_step(run.current, run.steps[run.current], false, m.repo_root)
+	}
+	return m.finish_rename()
+}
+
+// next_rename_undo takes back the next finished step, latest first. Once
+// there are none left the worktree is as it was, and is discovered again.
+func (m Model) next_rename_undo() (Model, tea.Cmd) {
+	run := m.rename
+	for run.current--; run.current >= 0; run.current-- {
+		s := run.steps[run.current]
+		if run.state[run.current].State == ui.StepDone && (s.undo != nil || s.revert != nil) {
+			run.state[run.current].State = ui.StepRunning
+			return m, cmd_rename_step(run.current, s, true, m.repo_root)
+		}
+	}
+	m.activity = ""
+	if wt := run.plan.wt; wt.Running && wt.Type == worktree.TypeLocal {
+		m.pending_dev_alias = wt.Alias
+	}
+	return m, m.cmd_discover()
+}
+
+// finish_rename carries the dashboard's own state over once every step is
+// through: pins, notes and the like move to the new name, and open tabs and
+// checkpoints to the new alias.
+func (m Model) finish_rename() (Model, tea.Cmd) {
+	p := m.rename.plan
+	m.rename = nil
+	m.activity = ""
+	if p.name != p.wt.Name {
+		m.repo_state.Rename(p.wt.Name, p.name)
+		if err := state.Save(m.repo_root, m.repo_state); err != nil {
+			debug_log("[rename] saving state failed: %v", err)
+		}
+	}
+	if m.term_mgr != nil {
+		dir := ""
+		if p.path != p.wt.Path {
+			dir = p.path
+		}
+		m.term_mgr.RelabelWorktree(p.wt.Alias, p.alias, dir)
+	}
+	if p.alias != p.wt.Alias {
+		if err := worktree.RenameCheckpoints(p.path, p.wt.Alias, p.alias); err != nil {
+			debug_log("[rename] moving checkpoints failed: %v", err)
+		}
+	}
+	if p.wt.Running && p.wt.Type == worktree.TypeLocal {
+		m.pending_dev_alias = p.alias
+	}
+	m, cmd := m.show_notification("Rename", fmt.Sprintf("Renamed %s to %s", p.wt.Alias, p.alias))
+	return m, tea.Batch(cmd, m.cmd_discover())
+}
+
+// rename_title is the title of the rename panel.
+func (m Model) rename_title() string {
+	run := m.rename
+	from, to := run.plan.wt.Alias, run.plan.alias
+	switch {
+	case !run.started:
+		return fmt.Sprintf("Rename %s → %s — Enter to apply, Esc to cancel", from, to)
+	case run.failed && run.done() && run.undo_failed:
+		return fmt.Sprintf("Renaming %s failed, rollback incomplete — Esc to close", from)
+	case run.failed && run.done():
+		return fmt.Sprintf("Renaming %s failed, rolled back — Esc to close", from)
+	case run.failed:
+		return fmt.Sprintf("Renaming %s failed, rolling back", from)
+	}
+	return fmt.Sprintf("Renaming %s → %s", from, to)
+}
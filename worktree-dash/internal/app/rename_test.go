@@ -0,0 +1,229 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const renameEnv = "WORKTREE_ALIAS=api\nMONGO_URL=mongodb://localhost:27017/db_api\nLOCAL_IP=api.app.localhost\n" +
+	"APP_URL=http://api.app.localhost/\nLAN_DOMAIN=api.192.168.1.5.nip.io\nPORT_OFFSET=3\n"
+
+// rename_model is a model with one running Docker worktree, api, with an
+// isolated database, in a temporary worktrees directory.
+func rename_model(t *testing.T) Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	path := filepath.Join(dir, "feat-api")
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, ".env.worktree"), []byte(renameEnv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := test_model()
+	m.repo_root = "/code/app"
+	m.worktrees_dir = dir
+	m.worktrees = []worktree.Worktree{{
+		Path: path, Name: "feat-api", Alias: "api", Branch: "feat/api", Type: worktree.TypeDocker,
+		Running: true, ContainerExists: true, Container: "app-api", DBName: "db_api",
+	}}
+	m.cfg = &config.Config{Name: "app"}
+	m.cfg.Docker.ComposeStrategy = "generate"
+	m.cfg.Docker.Proxy.DomainTemplate = "{alias}.app.localhost"
+	m.cfg.Database = &config.DatabaseConfig{Type: "mongo", Host: "localhost", DefaultDb: "db", DbNamePrefix: "db_"}
+	m.cfg.Env.Vars = map[string]string{"dbConnection": "MONGO_URL", "localIp": "LOCAL_IP", "appUrl": "APP_URL", "lanDomain": "LAN_DOMAIN"}
+	m.cfg.Paths.FlowScripts = "/flow"
+	return m
+}
+
+func step_lines(steps []renameStep, undo bool) []string {
+	var lines []string
+	for _, s := range steps {
+		cmds := s.cmds
+		if undo {
+			cmds = s.undo
+		}
+		var parts []string
+		for _, c := range cmds {
+			parts = append(parts, strings.Join(c, " "))
+		}
+		lines = append(lines, s.label+": "+strings.Join(parts, " && "))
+	}
+	return lines
+}
+
+func TestRenamePlan(t *testing.T) {
+	m := rename_model(t)
+	wt := m.worktrees[0]
+	p := plan_rename(wt, "billing", "feat/billing", m.worktrees_dir, m.cfg)
+	if p.path != filepath.Join(m.worktrees_dir, "feat-billing") || p.name != "feat-billing" ||
+		p.container != "app-billing" || p.domain != "billing.app.localhost" || p.old_db != "db_api" || p.new_db != "db_billing" ||
+		p.old_vol != "app_api_node_modules" || p.new_vol != "app_billing_node_modules" {
+		t.Errorf("plan: %+v", p)
+	}
+	var env []string
+	for _, v := range p.env {
+		env = append(env, v.key+"="+v.to)
+	}
+	want := "WORKTREE_ALIAS=billing MONGO_URL=mongodb://localhost:27017/db_billing LOCAL_IP=billing.app.localhost " +
+		"APP_URL=http://billing.app.localhost/ LAN_DOMAIN=billing.192.168.1.5.nip.io"
+	if got := strings.Join(env, " "); got != want {
+		t.Errorf("env:\n%s\nwant\n%s", got, want)
+	}
+
+	steps := rename_steps(p, "/code/app", "/flow", ".env.worktree", true)
+	got := strings.Join(step_lines(steps, false), "\n")
+	want = strings.Join([]string{
+		"Stop api: node /flow/dc-worktree-down.js feat-api && docker rm -f app-api",
+		"Rename branch: git -C /code/app branch -m feat/api feat/billing",
+		"Move directory: git -C /code/app worktree move " + wt.Path + " " + p.path,
+		"Update .env.worktree: ",
+		"Copy database: node /flow/dc-seed.js --db=db_billing --from=db_api",
+		"Copy volume: docker volume create app_billing_node_modules && " +
+			"docker run --rm -v app_api_node_modules:/from -v app_billing_node_modules:/to alpine cp -a /from/. /to/",
+		"Recreate container: node /flow/dc-worktree-up.js feat-billing",
+		"Drop old database: node /flow/dc-seed.js --db=db_api --drop",
+		"Remove old volume: docker volume rm app_api_node_modules",
+	}, "\n")
+	if got != want {
+		t.Errorf("steps:\n%s\nwant\n%s", got, want)
+	}
+	if steps[3].detail != "WORKTREE_ALIAS, MONGO_URL, LOCAL_IP, APP_URL, LAN_DOMAIN" || steps[7].undo != nil || !steps[7].optional {
+		t.Errorf("env step %+v, drop step %+v", steps[3], steps[7])
+	}
+	if undo := step_lines(steps[5:6], true)[0]; undo != "Copy volume: docker volume rm -f app_billing_node_modules" {
+		t.Errorf("volume undo = %q", undo)
+	}
+	if s := steps[8]; s.undo != nil || !s.optional || s.detail != "app_api_node_modules" {
+		t.Errorf("remove volume step %+v", s)
+	}
+
+	// A container that was never created has no volume to copy
+	p = plan_rename(worktree.Worktree{Path: wt.Path, Name: "feat-api", Alias: "api", Type: worktree.TypeDocker}, "billing", "", m.worktrees_dir, m.cfg)
+	if p.old_vol != "" || p.new_vol != "" {
+		t.Errorf("volumes planned without a container: %q → %q", p.old_vol, p.new_vol)
+	}
+
+	// A stopped local worktree on the shared database only gets its env file rewritten
+	local := worktree.Worktree{Path: wt.Path, Name: "feat-api", Alias: "api", Type: worktree.TypeLocal, DBName: "db"}
+	steps = rename_steps(plan_rename(local, "billing", "", m.worktrees_dir, m.cfg), "/code/app", "/flow", ".env.worktree", false)
+	if len(steps) != 1 || steps[0].label != "Update .env.worktree" {
+		t.Errorf("local steps: %v", step_lines(steps, false))
+	}
+}
+
+// run_rename confirms the plan shown and runs its steps to the end.
+func run_rename(t *testing.T, m Model) Model {
+	t.Helper()
+	if m.rename == nil || m.rename.started || m.notify_height() != ui.NotifyHeight(ui.NotifySteps, len(m.rename.state)) {
+		t.Fatalf("the plan should be shown: %+v", m.rename)
+	}
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(Model)
+	for m.rename != nil && !m.rename.done() {
+		result, cmd = m.Update(cmd())
+		m = result.(Model)
+	}
+	return m
+}
+
+func TestRenameApply(t *testing.T) {
+	m := rename_model(t)
+	old_path := m.worktrees[0].Path
+	m.repo_state.TogglePin("feat-api")
+	var ran []string
+	rename_exec = func(dir string, env []string, name string, args ...string) (string, error) {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		if name == "git" && args[2] == "worktree" {
+			return "", os.Rename(args[4], args[5])
+		}
+		return "", nil
+	}
+	defer func() { rename_exec = run_host_cmd_env_dir }()
+
+	m, _ = m.open_rename(m.worktrees[0])
+	m = submit_input(t, m, "billing")
+	if !m.picker_open || m.picker_context != pickerRename {
+		t.Fatalf("expected the branch choice, context=%q", m.picker_context)
+	}
+	m.picker_open = false
+	m, _ = m.dispatch_picker(ui.PickerAction{Key: "b"})
+	m = submit_input(t, m, "feat/billing")
+	if m.rename_title() != "Rename api → billing — Enter to apply, Esc to cancel" {
+		t.Errorf("title = %q", m.rename_title())
+	}
+
+	m = run_rename(t, m)
+	if m.rename != nil || !m.notify_open || len(ran) != 10 {
+		t.Fatalf("after rename: run=%v notify=%v ran=%v", m.rename, m.notify_open, ran)
+	}
+	data, err := os.ReadFile(filepath.Join(m.worktrees_dir, "feat-billing", ".env.worktree"))
+	if err != nil || !strings.Contains(string(data), "WORKTREE_ALIAS=billing\nMONGO_URL=mongodb://localhost:27017/db_billing\n") ||
+		!strings.Contains(string(data), "PORT_OFFSET=3") {
+		t.Errorf("env file (%v):\n%s", err, data)
+	}
+	if _, err := os.Stat(old_path); err == nil {
+		t.Error("the old directory should be gone")
+	}
+	if saved := state.Load("/code/app"); !saved.Pinned("feat-billing") || saved.Pinned("feat-api") {
+		t.Errorf("the pin should follow the rename: %+v", saved)
+	}
+}
+
+func TestRenameRollback(t *testing.T) {
+	m := rename_model(t)
+	var ran []string
+	ups := 0
+	rename_exec = func(dir string, env []string, name string, args ...string) (string, error) {
+		ran = append(ran, filepath.Base(args[0])+" "+strings.Join(args[1:], " "))
+		if strings.HasSuffix(args[0], "dc-worktree-up.js") {
+			if ups++; ups == 1 {
+				return "Starting Docker container...\nPort 3003 is already in use", errors.New("exit status 1")
+			}
+		}
+		return "", nil
+	}
+	defer func() { rename_exec = run_host_cmd_env_dir }()
+
+	m, _ = m.show_rename_plan(plan_rename(m.worktrees[0], "billing", "", m.worktrees_dir, m.cfg))
+	m = run_rename(t, m)
+
+	want := "dc-worktree-down.js feat-api; rm -f app-api; dc-seed.js --db=db_billing --from=db_api; " +
+		"volume create app_billing_node_modules; run --rm -v app_api_node_modules:/from -v app_billing_node_modules:/to alpine cp -a /from/. /to/; " +
+		"dc-worktree-up.js feat-api; volume rm -f app_billing_node_modules; dc-seed.js --db=db_billing --drop; dc-worktree-up.js feat-api"
+	if got := strings.Join(ran, "; "); got != want {
+		t.Errorf("ran:\n%s\nwant\n%s", got, want)
+	}
+	var states []string
+	for _, s := range m.rename.state {
+		states = append(states, s.Label+"="+s.Detail)
+	}
+	want = "Stop api=rolled back; Update .env.worktree=rolled back; Copy database=rolled back; Copy volume=rolled back; " +
+		"Recreate container=Port 3003 is already in use; Drop old database=db_api; Remove old volume=app_api_node_modules"
+	if got := strings.Join(states, "; "); got != want {
+		t.Errorf("states:\n%s\nwant\n%s", got, want)
+	}
+	if m.rename_title() != "Renaming api failed, rolled back — Esc to close" {
+		t.Errorf("title = %q", m.rename_title())
+	}
+	if data, _ := os.ReadFile(filepath.Join(m.worktrees[0].Path, ".env.worktree")); string(data) != renameEnv {
+		t.Errorf("the env file should be restored:\n%s", data)
+	}
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if m = result.(Model); m.rename != nil {
+		t.Error("Esc should dismiss a rolled back rename")
+	}
+}
@@ -0,0 +1,190 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/elvisnm/wt/internal/terminal"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxSearchResults caps the search picker so it fits in the notification area.
+const maxSearchResults = 9
+
+// worktreeCaptureDir is where captures land when saved inside a worktree.
+// It's added to the repo's git exclude, so captures don't show as untracked
+// files or keep `git worktree remove` from removing the worktree.
+const worktreeCaptureDir = ".wt-captures"
+
+// MsgScrollbackSearched carries the result of a cross-session scrollback search.
+type MsgScrollbackSearched struct {
+	Query   string
+	Matches []terminal.ScrollbackMatch
+}
+
+// home_capture_dir returns ~/.wt/captures/<alias> (or ~/.wt/captures when the
+// session is not tied to a worktree).
+func home_capture_dir(alias string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	dir := filepath.Join(home, ".wt", "captures")
+	if alias != "" {
+		dir = filepath.Join(dir, alias)
+	}
+	return dir
+}
+
+// cursor_session returns the session under the Active Tabs cursor, falling
+// back to the active group's primary session.
+func (m Model) cursor_session() *terminal.Session {
+	tab_labels := m.term_mgr.TabLabels()
+	if m.tab_cursor >= 0 && m.tab_cursor < len(tab_labels) {
+		id := tab_labels[m.tab_cursor].SessionID
+		for _, s := range m.term_mgr.Sessions() {
+			if s.ID == id {
+				return s
+			}
+		}
+	}
+	return m.term_mgr.Active()
+}
+
+// open_capture_picker asks where to save the scrollback of the session under the cursor.
+// Sessions without a worktree skip the picker and save straight to ~/.wt/captures.
+func (m Model) open_capture_picker() (tea.Model, tea.Cmd) {
+	s := m.cursor_session()
+	if s == nil {
+		return m, nil
+	}
+	m.capture_session_id = s.ID
+	if s.WorktreeDir == "" {
+		return m.capture_scrollback(home_capture_dir(""))
+	}
+	actions := []ui.PickerAction{
+		{Key: "h", Label: "Home", Desc: "~/.wt/captures/" + s.WorktreeAlias},
+		{Key: "w", Label: "Worktree", Desc: worktreeCaptureDir + "/ in " + s.WorktreeAlias},
+	}
+	return m.open_panel_picker("Capture Scrollback", actions, pickerCapture)
+}
+
+// execute_capture_action saves the pending capture to the chosen location.
+func (m Model) execute_capture_action(action ui.PickerAction) (Model, tea.Cmd) {
+	var s *terminal.Session
+	for _, sess := range m.term_mgr.Sessions() {
+		if sess.ID == m.capture_session_id {
+			s = sess
+			break
+		}
+	}
+	if s == nil {
+		return m, nil
+	}
+	switch action.Key {
+	case "h":
+		return m.capture_scrollback(home_capture_dir(s.WorktreeAlias))
+	case "w":
+		if err := worktree.ExcludeFromGit(s.WorktreeDir, worktreeCaptureDir+"/"); err != nil {
+			debug_log("[capture] excluding %s from git in %s: %v", worktreeCaptureDir, s.WorktreeDir, err)
+		}
+		return m.capture_scrollback(filepath.Join(s.WorktreeDir, worktreeCaptureDir))
+	}
+	return m, nil
+}
+
+// capture_scrollback writes the pending session's full scrollback into dir.
+func (m Model) capture_scrollback(dir string) (Model, tea.Cmd) {
+	for _, s := range m.term_mgr.Sessions() {
+		if s.ID != m.capture_session_id {
+			continue
+		}
+		path, err := s.SaveScrollback(dir)
+		if err != nil {
+			return m.show_notification("Capture", fmt.Sprintf("Failed: %v", err))
+		}
+		return m.show_notification("Capture", "Saved to "+path)
+	}
+	return m, nil
+}
+
+// open_scrollback_search prompts for a query to grep across all open tabs.
+func (m Model) open_scrollback_search() (tea.Model, tea.Cmd) {
+	if m.term_mgr.Count() == 0 {
+		return m.show_notification("Search", "No open tabs to search")
+	}
+	return m.open_panel_input("Search", "Search scrollback:", func(mdl *Model, val string) (Model, tea.Cmd) {
+		query := strings.TrimSpace(val)
+		if query == "" {
+			return *mdl, nil
+		}
+		mdl.activity = fmt.Sprintf("Searching for %q...", query)
+		mgr := mdl.term_mgr
+		return *mdl, func() tea.Msg {
+			return MsgScrollbackSearched{Query: query, Matches: mgr.SearchScrollback(query)}
+		}
+	})
+}
+
+// handle_scrollback_searched shows search hits as a picker, one row per tab.
+func (m Model) handle_scrollback_searched(msg MsgScrollbackSearched) (Model, tea.Cmd) {
+	m.activity = ""
+	if len(msg.Matches) == 0 {
+		return m.show_notification("Search", fmt.Sprintf("No matches for %q", msg.Query))
+	}
+	matches := msg.Matches
+	if len(matches) > maxSearchResults {
+		matches = matches[:maxSearchResults]
+	}
+	m.search_query = msg.Query
+	m.search_matches = matches
+
+	actions := make([]ui.PickerAction, len(matches))
+	for i, hit := range matches {
+		label := hit.Label
+		if hit.Count > 1 {
+			label = fmt.Sprintf("%s (%d)", hit.Label, hit.Count)
+		}
+		actions[i] = ui.PickerAction{
+			Key:   fmt.Sprintf("%d", i+1),
+			Label: label,
+			Desc:  hit.Line,
+		}
+	}
+	return m.open_panel_picker("Search", actions, pickerSearch)
+}
+
+// execute_search_action focuses the tab holding the selected match and
+// scrolls its pane to the most recent hit.
+func (m Model) execute_search_action(action ui.PickerAction) (Model, tea.Cmd) {
+	var idx int
+	fmt.Sscanf(action.Key, "%d", &idx)
+	idx--
+	if idx < 0 || idx >= len(m.search_matches) {
+		return m, nil
+	}
+
+	s := m.term_mgr.FocusBySessionID(m.search_matches[idx].SessionID)
+	if s == nil {
+		return m.show_notification("Search", "Tab is no longer open")
+	}
+	m.close_preview()
+	m.sync_tab_cursor_from_active()
+	m.prev_focus = m.focus
+	m.focus = PanelTerminal
+	s.RevealMatch(m.search_query)
+	if m.pane_layout != nil {
+		if pid := s.PaneID(); pid != "" {
+			m.pane_layout.Server().Run("select-pane", "-t", pid)
+		} else {
+			m.pane_layout.FocusRight()
+		}
+	}
+	return m, tick_after(100*time.Millisecond, "render")
+}
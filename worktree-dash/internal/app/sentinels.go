@@ -8,6 +8,7 @@ import (
 	"github.com/elvisnm/wt/internal/aws"
 	"github.com/elvisnm/wt/internal/labels"
 	"github.com/elvisnm/wt/internal/sentinel"
+	"github.com/elvisnm/wt/internal/ui"
 	"github.com/elvisnm/wt/internal/worktree"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -173,29 +174,19 @@ func (m Model) resolve_sso_action() (Model, tea.Cmd) {
 
 	switch action {
 	case "create":
-		return m.open_create(m.selected_worktree())
-	case "start":
-		if m.pending_sso_start != nil {
+		m.pending_sso_start = nil
+		return m.open_quick_create()
+	case "start", "restart":
+		// The registry entry behind the check, run on its worktree
+		if a, ok := find_sso_action(action); ok && m.pending_sso_start != nil {
 			wt := *m.pending_sso_start
 			m.pending_sso_start = nil
-			return m.start_worktree(wt)
-		}
-	case "restart":
-		if m.pending_sso_start != nil {
-			wt := *m.pending_sso_start
-			m.pending_sso_start = nil
-			if wt.Type == worktree.TypeLocal {
-				return m.restart_local_services(wt)
-			}
-			if wt.HostBuild {
-				return m.restart_host_build(wt)
-			}
-			return m, cmd_docker_action("restart", wt, m.repo_root, m.cfg)
+			return a.run(m, wt)
 		}
 	}
 	// No pending action (manual Shift+A) — show timed notification
-	white := lipgloss.NewStyle().Foreground(lipgloss.Color("255")).Bold(true)
-	green := lipgloss.NewStyle().Foreground(lipgloss.Color("34")).Bold(true)
+	white := lipgloss.NewStyle().Foreground(ui.TextColor).Bold(true)
+	green := lipgloss.NewStyle().Foreground(ui.RunningColor).Bold(true)
 	msg := white.Render("AWS session is already ") + green.Render("VALID")
 	return m.show_notification("AWS", msg)
 }
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/labels"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A worktree set is a worktree of this repo plus the worktrees of the
+// related repos in config (repo.related) checked out on the same branch.
+// Discovery attaches the members (worktree.AttachSets); start, stop and
+// remove act on them along with the worktree.
+
+// related_repo returns the related repo named name, nil if config has none.
+func related_repo(cfg *config.Config, name string) *config.RelatedRepo {
+	if cfg == nil {
+		return nil
+	}
+	for i := range cfg.Repo.Related {
+		if cfg.Repo.Related[i].Name == name {
+			return &cfg.Repo.Related[i]
+		}
+	}
+	return nil
+}
+
+// set_tab is the label of the tab running a set member's start command.
+func set_tab(wt worktree.Worktree, s worktree.SetMember) string {
+	return labels.Tab(labels.Dev, wt.Alias+"/"+s.Repo)
+}
+
+// start_set runs the start command of each set member that has one in its
+// own tab, unless the tab is already open.
+func (m *Model) start_set(wt worktree.Worktree) {
+	for _, s := range wt.Set {
+		r := related_repo(m.cfg, s.Repo)
+		if r == nil || r.Start == "" || m.term_mgr.IsLabelAlive(set_tab(wt, s)) {
+			continue
+		}
+		w, h := m.right_pane_dimensions()
+		if _, err := m.term_mgr.Open(set_tab(wt, s), "bash", []string{"-c", r.Start}, w, h, s.Path); err != nil {
+			debug_log("[sets] starting %s in %s failed: %v", s.Repo, s.Path, err)
+			m.activity = fmt.Sprintf("Starting %s failed: %v", s.Repo, err)
+		}
+	}
+}
+
+// close_set_tabs closes the tabs start_set opened for wt.
+func (m *Model) close_set_tabs(wt worktree.Worktree) {
+	for _, s := range wt.Set {
+		m.term_mgr.CloseByLabel(set_tab(wt, s))
+	}
+}
+
+// stop_set closes wt's set member tabs and runs their stop commands
+// alongside cmd, the command stopping wt itself.
+func (m Model) stop_set(wt worktree.Worktree, cmd tea.Cmd) (Model, tea.Cmd) {
+	if len(wt.Set) == 0 {
+		return m, cmd
+	}
+	m.close_set_tabs(wt)
+	if m.term_mgr.Count() == 0 && m.focus == PanelTerminal {
+		m.focus = PanelWorktrees
+	}
+	cfg := m.cfg
+	return m, tea.Batch(cmd, func() tea.Msg {
+		if out, err := stop_set_members(wt, cfg); err != nil {
+			debug_log("[sets] stopping the set of %s: %v: %s", wt.Alias, err, out)
+		}
+		return nil
+	})
+}
+
+// stop_set_members runs the stop command of each set member that has one.
+func stop_set_members(wt worktree.Worktree, cfg *config.Config) (string, error) {
+	var outs []string
+	var first error
+	for _, s := range wt.Set {
+		r := related_repo(cfg, s.Repo)
+		if r == nil || r.Stop == "" {
+			continue
+		}
+		out, err := run_host_cmd_env_dir(s.Path, nil, "bash", "-c", r.Stop)
+		if out != "" {
+			outs = append(outs, out)
+		}
+		if err != nil && first == nil {
+			first = fmt.Errorf("stopping %s: %w", s.Repo, err)
+		}
+	}
+	return strings.Join(outs, "\n"), first
+}
+
+// set_remove_cmds are the commands removing wt's set members from their
+// repos. Their branches are kept, as dc-worktree-down keeps wt's.
+func set_remove_cmds(wt worktree.Worktree, cfg *config.Config, force bool) [][]string {
+	var cmds [][]string
+	for _, s := range wt.Set {
+		r := related_repo(cfg, s.Repo)
+		if r == nil {
+			continue
+		}
+		c := []string{"git", "-C", r.PathAbs, "worktree", "remove"}
+		if force {
+			c = append(c, "--force")
+		}
+		cmds = append(cmds, append(c, s.Path))
+	}
+	return cmds
+}
+
+// remove_set_members removes wt's set members, trying each even when one
+// fails (e.g. it has uncommitted changes and force is off).
+func remove_set_members(wt worktree.Worktree, cfg *config.Config, force bool) (string, error) {
+	var outs []string
+	var first error
+	for _, c := range set_remove_cmds(wt, cfg, force) {
+		out, err := run_host_cmd(c[0], c[1:]...)
+		if out != "" {
+			outs = append(outs, out)
+		}
+		if err != nil && first == nil {
+			first = fmt.Errorf("removing %s: %w", c[len(c)-1], err)
+		}
+	}
+	return strings.Join(outs, "\n"), first
+}
+
+// toggle_set lists or hides wt's set members under its row.
+func (m Model) toggle_set(wt worktree.Worktree) (Model, tea.Cmd) {
+	if m.expanded_sets == nil {
+		m.expanded_sets = map[string]bool{}
+	}
+	if m.expanded_sets[wt.Name] {
+		delete(m.expanded_sets, wt.Name)
+	} else {
+		m.expanded_sets[wt.Name] = true
+	}
+	return m, nil
+}
+
+// setRepo is a related repo a new worktree set adds a worktree to.
+type setRepo struct {
+	name string
+	root string // the related repo's root
+	path string // the worktree added
+	base string // ref a new branch starts from, "" when the branch exists
+}
+
+// set_repos plans the related repos' worktrees for a new set on branch. The
+// branch is checked out where it exists; elsewhere it's created from from
+// when the repo has that ref, else from the repo's HEAD.
+func set_repos(cfg *config.Config, branch, from string) []setRepo {
+	if cfg == nil {
+		return nil
+	}
+	var repos []setRepo
+	for _, r := range cfg.Repo.Related {
+		s := setRepo{name: r.Name, root: r.PathAbs, path: worktree_dir_for(r.WorktreesDirAbs, branch)}
+		if _, err := run_host_cmd("git", "-C", r.PathAbs, "rev-parse", "--verify", "-q", "refs/heads/"+branch); err != nil {
+			s.base = "HEAD"
+			if from != "" {
+				if _, err := run_host_cmd("git", "-C", r.PathAbs, "rev-parse", "--verify", "-q", from+"^{commit}"); err == nil {
+					s.base = from
+				}
+			}
+		}
+		repos = append(repos, s)
+	}
+	return repos
+}
+
+// set_steps are the create steps adding each related repo's worktree of
+// branch.
+func set_steps(repos []setRepo, branch string) []createStep {
+	steps := make([]createStep, len(repos))
+	for i, r := range repos {
+		git := func(args ...string) []string { return append([]string{"git", "-C", r.root}, args...) }
+		add := git("worktree", "add", r.path, branch)
+		if r.base != "" {
+			add = git("worktree", "add", "-b", branch, r.path, r.base)
+		}
+		steps[i] = createStep{label: "Add " + r.name + " worktree", detail: r.path, cmds: [][]string{git("worktree", "prune"), add}}
+	}
+	return steps
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+func TestWorktreeSets(t *testing.T) {
+	m := list_model()
+	m.cfg.Repo.Related = []config.RelatedRepo{{Name: "lib", PathAbs: "/lib", WorktreesDirAbs: "/lib-worktrees"}}
+	login := &m.worktrees[1]
+	login.Set = []worktree.SetMember{{Repo: "lib", Path: "/lib-worktrees/feat-login", Branch: "feat/login"}}
+
+	if got := strings.Join(set_remove_cmds(*login, m.cfg, true)[0], " "); got != "git -C /lib worktree remove --force /lib-worktrees/feat-login" {
+		t.Errorf("remove = %q", got)
+	}
+	if desc := remove_actions(*login)[0].Desc; !strings.HasSuffix(desc, " · with lib") {
+		t.Errorf("remove picker desc = %q", desc)
+	}
+
+	m.cursor = 1
+	m, _ = m.toggle_set(*login)
+	list := m.worktree_list_view(nil)
+	panel := ui.RenderWorktreePanel(m.worktrees, m.cursor, 60, 20, true, m.cfg, list)
+	if !list.Expanded["feat-login"] || !strings.Contains(panel, "└ lib  feat-login") || !strings.Contains(panel, "+lib") {
+		t.Errorf("expanded set not listed:\n%s", panel)
+	}
+	m, _ = m.toggle_set(*login)
+	if m.expanded_sets["feat-login"] {
+		t.Error("second toggle should collapse the set")
+	}
+}
+
+func TestSetCreate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	lib := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.name=Sam", "-c", "user.email=sam@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"branch", "feat/shared"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", lib}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	m := list_model()
+	m.repo_root = "/repo"
+	m.cfg.Repo.Related = []config.RelatedRepo{{Name: "lib", PathAbs: lib, WorktreesDirAbs: "/lib-worktrees"}}
+	m.create_plan = createPlan{branch: "feat/new", from: "origin/main", alias: "new", env: "local"}
+	m, _ = m.continue_create()
+	if !m.picker_open || m.picker_context != pickerCreateSet || m.picker_actions[0].Desc != "feat/new in lib too" {
+		t.Fatalf("set picker: open=%v context=%q actions=%+v", m.picker_open, m.picker_context, m.picker_actions)
+	}
+	m.picker_open = false
+	m, _ = m.dispatch_picker(ui.PickerAction{Key: "s"})
+	if !m.confirm_open || !strings.HasSuffix(m.confirm_prompt, "(from origin/main, local, worktree set)?") {
+		t.Fatalf("confirm: open=%v prompt=%q", m.confirm_open, m.confirm_prompt)
+	}
+
+	// lib has no origin/main, so the new branch starts from its HEAD; an
+	// existing branch is checked out
+	plan := m.create_plan
+	plan.set_repos = set_repos(m.cfg, plan.branch, plan.from)
+	existing := set_repos(m.cfg, "feat/shared", "main")
+	from_main := set_repos(m.cfg, "feat/other", "main")
+	if plan.set_repos[0].base != "HEAD" || existing[0].base != "" || from_main[0].base != "main" {
+		t.Errorf("bases: %+v, %+v, %+v", plan.set_repos, existing, from_main)
+	}
+	var got []string
+	for _, s := range create_steps(plan, "/repo", "/wts", "/flow")[:2] {
+		got = append(got, s.label+": "+strings.Join(s.cmds[len(s.cmds)-1], " "))
+	}
+	want := []string{
+		"Add worktree: git -C /repo worktree add -b feat/new /wts/feat-new origin/main",
+		"Add lib worktree: git -C " + lib + " worktree add -b feat/new " + filepath.Join("/lib-worktrees", "feat-new") + " HEAD",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("steps =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
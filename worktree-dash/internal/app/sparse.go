@@ -0,0 +1,93 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sparse_offered reports whether the sparse checkout picker has anything to
+// offer wt: profiles to switch to, or a sparse checkout to widen.
+func sparse_offered(m *Model, wt worktree.Worktree) bool {
+	if wt.Prunable {
+		return false
+	}
+	return len(wt.Sparse) > 0 || m.cfg != nil && len(m.cfg.SparseProfiles) > 0
+}
+
+// describe_sparse shows the directories a sparse worktree checks out.
+func describe_sparse(wt worktree.Worktree) (string, string) {
+	if len(wt.Sparse) > 0 {
+		return "Sparse checkout", wt.SparseProfile + ": " + strings.Join(wt.Sparse, ", ")
+	}
+	return "Sparse checkout", "Narrow to a profile's directories"
+}
+
+// open_sparse lists the sparse-checkout profiles to narrow or widen wt to,
+// plus adding directories to its cone and going back to a full checkout.
+func (m Model) open_sparse(wt worktree.Worktree) (Model, tea.Cmd) {
+	return m.open_panel_picker("Sparse checkout", sparse_actions(m, wt), pickerSparse)
+}
+
+func sparse_actions(m Model, wt worktree.Worktree) []ui.PickerAction {
+	var actions []ui.PickerAction
+	if m.cfg != nil {
+		for i, name := range m.cfg.SparseProfileNames() {
+			desc := strings.Join(m.cfg.SparseProfiles[name], ", ")
+			if name == wt.SparseProfile {
+				desc += " · current"
+			}
+			actions = append(actions, ui.PickerAction{Key: fmt.Sprintf("%d", i+1), Label: name, Desc: desc})
+		}
+	}
+	if len(wt.Sparse) > 0 {
+		actions = append(actions,
+			ui.PickerAction{Key: "a", Label: "Add directories", Desc: "Widen " + strings.Join(wt.Sparse, ", ")},
+			ui.PickerAction{Key: "f", Label: "Full checkout", Desc: "Every file"})
+	}
+	return actions
+}
+
+// execute_sparse_action sets the selected worktree's cone to a profile,
+// adds directories to it, or turns sparse checkout off. Git keeps files
+// with local changes that fall outside a narrower cone.
+func (m Model) execute_sparse_action(action ui.PickerAction) (Model, tea.Cmd) {
+	wt := m.selected_worktree()
+	if wt == nil {
+		return m, nil
+	}
+	path, alias := wt.Path, wt.Alias
+	switch action.Key {
+	case "a":
+		return m.open_panel_input("Sparse checkout", fmt.Sprintf("Directories to add to %s:", alias), func(mdl *Model, value string) (Model, tea.Cmd) {
+			dirs := strings.Fields(value)
+			if len(dirs) == 0 {
+				return *mdl, nil
+			}
+			mdl.activity = fmt.Sprintf("Adding %s to %s...", strings.Join(dirs, ", "), alias)
+			return *mdl, cmd_sparse_checkout(path, append([]string{"add"}, dirs...)...)
+		})
+	case "f":
+		m.activity = fmt.Sprintf("Checking out every file in %s...", alias)
+		return m, cmd_sparse_checkout(path, "disable")
+	}
+	dirs := m.cfg.SparseProfiles[action.Label]
+	if len(dirs) == 0 {
+		return m, nil
+	}
+	m.activity = fmt.Sprintf("Checking out %s in %s...", action.Label, alias)
+	return m, cmd_sparse_checkout(path, append([]string{"set", "--cone"}, dirs...)...)
+}
+
+// cmd_sparse_checkout runs a git sparse-checkout subcommand in a worktree
+// and reports it like any other action, which rediscovers the worktrees.
+func cmd_sparse_checkout(path string, args ...string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := run_host_cmd("git", append([]string{"-C", path, "sparse-checkout"}, args...)...)
+		return MsgActionOutput{Output: out, Err: err}
+	}
+}
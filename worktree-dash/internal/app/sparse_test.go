@@ -0,0 +1,127 @@
+package app
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSparseCreate(t *testing.T) {
+	m := list_model()
+	m.cfg.SparseProfiles = map[string][]string{"api": {"apps/api", "packages/shared"}, "web": {"apps/web"}}
+	m.create_plan = createPlan{branch: "feat/new", from: "origin/main", alias: "new", env: "local"}
+	m, _ = m.continue_create()
+	if !m.picker_open || m.picker_context != pickerCreateSparse {
+		t.Fatalf("expected checkout picker, open=%v context=%q", m.picker_open, m.picker_context)
+	}
+	if got := m.picker_actions[1].Label + ": " + m.picker_actions[1].Desc; got != "api: apps/api, packages/shared" {
+		t.Errorf("first profile = %q", got)
+	}
+	m.picker_open = false
+	m, _ = m.dispatch_picker(m.picker_actions[1])
+	if !m.confirm_open || m.confirm_prompt != "Create feat/new as new (from origin/main, local, sparse api)?" {
+		t.Fatalf("confirm: open=%v prompt=%q", m.confirm_open, m.confirm_prompt)
+	}
+
+	var got []string
+	for _, s := range create_steps(m.create_plan, "/repo", "/wts", "/flow")[:2] {
+		for _, c := range s.cmds {
+			got = append(got, s.label+": "+strings.Join(c, " "))
+		}
+	}
+	want := []string{
+		"Add worktree: git -C /repo worktree prune",
+		"Add worktree: git -C /repo worktree add --no-checkout -b feat/new /wts/feat-new origin/main",
+		"Sparse checkout api: git -C /wts/feat-new sparse-checkout set --cone apps/api packages/shared",
+		"Sparse checkout api: git -C /wts/feat-new checkout",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("steps =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A duplicate keeps its source's cone without asking
+	src := m.worktrees[1]
+	src.Sparse, src.SparseProfile = []string{"apps/web"}, "web"
+	if plan := m.duplicate_plan(src); plan.sparse != "web" || len(plan.sparse_dirs) != 1 {
+		t.Errorf("duplicate plan: %+v", plan)
+	}
+}
+
+func TestSparseActions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	repo, path := filepath.Join(root, "app"), filepath.Join(root, "feat-api")
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Sam", "GIT_AUTHOR_EMAIL=sam@example.com",
+			"GIT_COMMITTER_NAME=Sam", "GIT_COMMITTER_EMAIL=sam@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	for _, dir := range []string{"apps/api", "apps/web", "docs"} {
+		os.MkdirAll(filepath.Join(repo, dir), 0755)
+		os.WriteFile(filepath.Join(repo, dir, "README"), []byte("x\n"), 0644)
+	}
+	git(repo, "init", "-q", "-b", "main")
+	git(repo, "add", ".")
+	git(repo, "commit", "-qm", "init")
+	git(repo, "worktree", "add", "-q", "-b", "feat/api", path)
+
+	m := list_model()
+	m.cfg.SparseProfiles = map[string][]string{"api": {"apps/api"}}
+	m.worktrees = []worktree.Worktree{{Path: path, Name: "feat-api", Alias: "api", Branch: "feat/api", Type: worktree.TypeLocal}}
+	m.cursor = 0
+	if actions := sparse_actions(m, m.worktrees[0]); len(actions) != 1 || actions[0].Label != "api" {
+		t.Fatalf("full checkout actions: %+v", actions)
+	}
+	finish := func(cmd tea.Cmd) {
+		t.Helper()
+		if msg := cmd().(MsgActionOutput); msg.Err != nil {
+			t.Fatalf("sparse-checkout: %v: %s", msg.Err, msg.Output)
+		}
+	}
+	run := func(action ui.PickerAction) {
+		t.Helper()
+		var cmd tea.Cmd
+		m, cmd = m.execute_sparse_action(action)
+		finish(cmd)
+	}
+	exists := func(dir string) bool {
+		_, err := os.Stat(filepath.Join(path, dir))
+		return err == nil
+	}
+
+	run(ui.PickerAction{Key: "1", Label: "api"})
+	if !exists("apps/api") || exists("apps/web") || exists("docs") {
+		t.Error("narrowing to api should leave only apps/api")
+	}
+
+	m.worktrees[0].Sparse, m.worktrees[0].SparseProfile = []string{"apps/api"}, "api"
+	actions := sparse_actions(m, m.worktrees[0])
+	if len(actions) != 3 || actions[0].Desc != "apps/api · current" || actions[1].Key != "a" || actions[2].Key != "f" {
+		t.Fatalf("sparse checkout actions: %+v", actions)
+	}
+	m, _ = m.execute_sparse_action(actions[1])
+	result, cmd := m.Update(m.input_callback("docs")())
+	m = result.(Model)
+	finish(cmd)
+	if !exists("docs") || exists("apps/web") {
+		t.Error("adding docs should widen the cone to it")
+	}
+
+	run(actions[2])
+	if !exists("apps/web") {
+		t.Error("a full checkout should bring apps/web back")
+	}
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sync_fetch fetches the base ref before a bulk sync and returns it.
+// Tests replace it.
+var sync_fetch = fetch_base_ref
+
+// skip_unless_syncable skips worktrees a rebase or merge would trip over.
+// Uncommitted changes are checked again by dc-pull, as the cached status
+// may be stale.
+func skip_unless_syncable(wt worktree.Worktree) string {
+	s := wt.Git
+	switch {
+	case wt.Prunable:
+		return "directory is gone"
+	case wt.Operation != "":
+		return wt.Operation + " in progress"
+	case wt.Detached:
+		return "detached HEAD"
+	case s.Staged+s.Unstaged+s.Conflicts > 0:
+		return "uncommitted changes"
+	}
+	return ""
+}
+
+// short_base_ref strips refs/remotes/ or refs/heads/ from a ResolveBaseRef result.
+func short_base_ref(ref string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(ref, "refs/remotes/"), "refs/heads/")
+}
+
+// fetch_base_ref resolves the base ref and, for a remote one, fetches it so
+// every worktree syncs with the same, latest commit. A failed fetch still
+// returns the ref, to sync with as last fetched.
+func fetch_base_ref(repo_root string, base_refs []string) (string, error) {
+	ref := worktree.ResolveBaseRef(repo_root, base_refs)
+	base := short_base_ref(ref)
+	if !strings.HasPrefix(ref, "refs/remotes/") {
+		return base, nil
+	}
+	remote, branch, _ := strings.Cut(base, "/")
+	if out, err := exec.Command("git", "-C", repo_root, "fetch", remote, branch).CombinedOutput(); err != nil {
+		return base, fmt.Errorf("%s", last_line(string(out)))
+	}
+	return base, nil
+}
+
+// sync_conflicts returns the files dc-pull reported as conflicting.
+func sync_conflicts(out string) []string {
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if f, ok := strings.CutPrefix(strings.TrimSpace(line), "Conflict: "); ok {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// sync_skip_reason returns why dc-pull skipped the worktree, or "".
+func sync_skip_reason(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		if reason, ok := strings.CutPrefix(strings.TrimSpace(line), "Skipped: "); ok {
+			return reason
+		}
+	}
+	return ""
+}
+
+// record_sync records one worktree's sync: updated or up to date, skipped
+// for changes the cache missed, stopped on conflicts (kept in the repo state
+// for the conflict badge) or failed. A clean sync clears an old conflict.
+func (m *Model) record_sync(run *bulkRun, wt worktree.Worktree, msg MsgBulkItemDone) {
+	result := bulkResult{name: wt.Name, alias: wt.Alias, reason: last_line(msg.Output)}
+	switch conflicts := sync_conflicts(msg.Output); {
+	case msg.Err == nil:
+		run.synced = append(run.synced, result)
+		run.progress[wt.Name] = "done"
+		if m.repo_state.ClearConflict(wt.Name) {
+			run.state = true
+		}
+		return
+	case len(conflicts) > 0:
+		result.conflict = true
+		result.reason = "conflicts in " + strings.Join(conflicts, ", ")
+		m.repo_state.SetConflict(wt.Name, state.Conflict{Base: run.base, Files: conflicts, At: time.Now()})
+		run.state = true
+		run.progress[wt.Name] = "conflict"
+	case sync_skip_reason(msg.Output) != "":
+		result.skipped = true
+		result.reason = sync_skip_reason(msg.Output)
+		run.progress[wt.Name] = "skipped"
+	default:
+		if result.reason == "" {
+			result.reason = msg.Err.Error()
+		}
+		run.progress[wt.Name] = "failed"
+	}
+	debug_log("[bulk] sync %s: %v\n%s", wt.Name, msg.Err, msg.Output)
+	run.results = append(run.results, result)
+}
+
+// cmd_refresh_synced re-reads the git status of the worktrees a sync ran
+// on, so their badges don't wait for the next full pass.
+func (m Model) cmd_refresh_synced(run *bulkRun) tea.Cmd {
+	names := map[string]bool{}
+	for _, rows := range [][]bulkResult{run.synced, run.results} {
+		for _, r := range rows {
+			names[r.name] = true
+		}
+	}
+	var paths []string
+	for _, wt := range m.worktrees {
+		if names[wt.Name] {
+			paths = append(paths, wt.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return cmd_read_git_status(m.repo_root, m.base_refs(), paths, false)
+}
+
+// open_sync_summary lists every worktree of a finished sync with its
+// outcome: conflicted and failed first, then skipped, updated and up to
+// date. Selecting one moves the cursor to it.
+func (m Model) open_sync_summary(run *bulkRun) (Model, tea.Cmd) {
+	var conflicted, failed, skipped, updated, current []bulkResult
+	for _, r := range run.results {
+		switch {
+		case r.conflict:
+			conflicted = append(conflicted, r)
+		case r.skipped:
+			skipped = append(skipped, r)
+		default:
+			failed = append(failed, r)
+		}
+	}
+	for _, r := range run.synced {
+		if strings.HasPrefix(r.reason, "Already up to date") {
+			current = append(current, r)
+		} else {
+			updated = append(updated, r)
+		}
+	}
+
+	counts := []string{fmt.Sprintf("%d updated", len(updated)), fmt.Sprintf("%d up to date", len(current))}
+	for _, c := range []struct {
+		n    int
+		what string
+	}{{len(skipped), "skipped"}, {len(conflicted), "conflicted"}, {len(failed), "failed"}} {
+		if c.n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", c.n, c.what))
+		}
+	}
+	title := "Sync: "
+	if run.base != "" {
+		title = "Sync with " + run.base + ": "
+	}
+	m.bulk_summary = title + strings.Join(counts, ", ")
+	if run.fetch_err != "" {
+		m.bulk_summary += " (fetch failed)"
+	}
+
+	m.bulk_results = nil
+	var actions []ui.PickerAction
+	add := func(rows []bulkResult, prefix string) {
+		for _, r := range rows {
+			alias := r.alias
+			if alias == "" {
+				alias = r.name
+			}
+			m.bulk_results = append(m.bulk_results, r)
+			actions = append(actions, ui.PickerAction{
+				Key: fmt.Sprintf("%d", len(actions)+1), Label: alias, Desc: prefix + r.reason,
+			})
+		}
+	}
+	add(conflicted, "")
+	add(failed, "failed: ")
+	add(skipped, "skipped: ")
+	add(updated, "")
+	add(current, "")
+	if len(actions) == 0 {
+		return m.show_notification("Bulk Sync", m.bulk_summary)
+	}
+	return m.open_panel_picker("Bulk", actions, pickerBulkResult)
+}
+
+// clear_resolved_conflicts drops sync conflicts the user has since resolved
+// by hand, judged by git status read since: the worktree now contains
+// everything on the base ref it conflicted with.
+func (m *Model) clear_resolved_conflicts() {
+	changed := false
+	for _, wt := range m.worktrees {
+		c, ok := m.repo_state.Conflict(wt.Name)
+		s := wt.Git
+		if ok && s.Fetched.After(c.At) && s.Base == c.Base && s.BaseBehind == 0 && wt.Operation == "" {
+			changed = m.repo_state.ClearConflict(wt.Name) || changed
+		}
+	}
+	if !changed {
+		return
+	}
+	if err := state.Save(m.repo_root, m.repo_state); err != nil {
+		debug_log("[sync] saving resolved conflicts failed: %v", err)
+	}
+	m.apply_repo_state()
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/state"
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+func TestBulkSync(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fetches := 0
+	sync_fetch = func(repo_root string, base_refs []string) (string, error) {
+		fetches++
+		return "origin/main", nil
+	}
+	bulk_job = func(op string, wt worktree.Worktree, repo_root string, cfg *config.Config) (string, error) {
+		switch wt.Name {
+		case "wt-0":
+			return "Rebasing onto origin/main...\nRebased onto origin/main (3 new commits)", nil
+		case "wt-2":
+			return "Already up to date with origin/main", nil
+		case "wt-3":
+			return "Rebasing onto origin/main...\nConflict: go.mod\nConflict: api/users.ts\nAborted the rebase: origin/main conflicts in 2 files",
+				errors.New("exit status 1")
+		case "wt-4":
+			return "Skipped: uncommitted changes (1 file)", errors.New("exit status 1")
+		}
+		return "fatal: bad object", errors.New("exit status 128")
+	}
+	defer func() { bulk_job, sync_fetch = run_bulk_job, fetch_base_ref }()
+
+	m := bulk_model()
+	m.repo_root = "/code/app"
+	m.worktrees[1].Git.Unstaged = 2
+	m.repo_state.SetConflict("wt-0", state.Conflict{Base: "origin/main", Files: []string{"old.go"}})
+	m.marked = map[string]bool{}
+	for _, wt := range m.worktrees[:6] {
+		m.marked[wt.Name] = true
+	}
+
+	var op bulkOp
+	for _, o := range bulk_ops {
+		if o.id == "sync" {
+			op = o
+		}
+	}
+	m, cmd := m.start_bulk(op, m.marked_worktrees())
+	pending := bulk_done(cmd)
+	for len(pending) > 0 && m.bulk != nil {
+		msg := pending[0]
+		pending = pending[1:]
+		m, cmd = m.handle_bulk_item_done(msg)
+		if m.bulk != nil {
+			pending = append(pending, bulk_done(cmd)...)
+		}
+	}
+
+	if fetches != 1 {
+		t.Errorf("the base ref should be fetched once, got %d", fetches)
+	}
+	if m.bulk_summary != "Sync with origin/main: 1 updated, 1 up to date, 2 skipped, 1 conflicted, 1 failed" {
+		t.Errorf("summary = %q", m.bulk_summary)
+	}
+	var rows []string
+	for _, a := range m.picker_actions {
+		rows = append(rows, a.Label+": "+a.Desc)
+	}
+	want := "wt3: conflicts in go.mod, api/users.ts; wt5: failed: fatal: bad object; wt1: skipped: uncommitted changes; " +
+		"wt4: skipped: uncommitted changes (1 file); wt0: Rebased onto origin/main (3 new commits); wt2: Already up to date with origin/main"
+	if got := strings.Join(rows, "; "); got != want {
+		t.Errorf("rows =\n%s\nwant\n%s", got, want)
+	}
+	if len(m.marked) != 4 || m.marked["wt-0"] || m.marked["wt-2"] {
+		t.Errorf("updated worktrees should be unmarked: %v", m.marked)
+	}
+
+	// The conflict is saved for the badge; wt-0's old one is cleared
+	saved := state.Load("/code/app")
+	if c, ok := saved.Conflict("wt-3"); !ok || c.Base != "origin/main" || len(c.Files) != 2 {
+		t.Errorf("saved conflicts: %+v", saved.Conflicts)
+	}
+	if _, ok := saved.Conflict("wt-0"); ok {
+		t.Error("a clean sync should clear the old conflict")
+	}
+	if wt := m.worktrees[3]; wt.SyncConflict != "origin/main" || len(wt.ConflictFiles) != 2 {
+		t.Errorf("worktree conflict: %q %v", wt.SyncConflict, wt.ConflictFiles)
+	}
+
+	// Resolved by hand: a later status shows it has all of the base ref
+	m.worktrees[3].Git = worktree.GitStatus{Base: "origin/main", BaseBehind: 2, Fetched: time.Now()}
+	m.clear_resolved_conflicts()
+	if m.worktrees[3].SyncConflict == "" {
+		t.Fatal("still behind the base ref, the conflict should stay")
+	}
+	m.worktrees[3].Git.BaseBehind = 0
+	m.clear_resolved_conflicts()
+	if m.worktrees[3].SyncConflict != "" || len(state.Load("/code/app").Conflicts) != 0 {
+		t.Error("a resolved conflict should be cleared")
+	}
+}
+
+func TestSyncOutputParsing(t *testing.T) {
+	out := "Unskipping 2 setup file(s)...\nConflict: a.go\nConflict: b/c.ts\nAborted the merge: origin/main conflicts in 2 files"
+	if got := sync_conflicts(out); strings.Join(got, ",") != "a.go,b/c.ts" {
+		t.Errorf("conflicts = %v", got)
+	}
+	if got := sync_skip_reason("Skipped: uncommitted changes (3 files)"); got != "uncommitted changes (3 files)" {
+		t.Errorf("skip = %q", got)
+	}
+	if sync_skip_reason(out) != "" || len(sync_conflicts("Rebased onto main (1 new commit)")) != 0 {
+		t.Error("false positives")
+	}
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/labels"
+	"github.com/elvisnm/wt/internal/terminal"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// templateSessionKeys maps template session types to split picker keys.
+var templateSessionKeys = map[string]string{
+	"shell":       "b",
+	"claude":      "c",
+	"claude-auto": "C",
+	"zsh":         "z",
+	"logs":        "l",
+}
+
+// templateStep is one session to open when instantiating a template.
+// Step 0 opens the tab; every later step splits the session opened by
+// step Target in direction Dir.
+type templateStep struct {
+	Key    string // split picker key (b, c, C, z, l)
+	Target int    // index of the step whose session is split
+	Dir    SplitDir
+}
+
+// template_steps flattens a template tree into the SplitInto calls that build it.
+// A split node (dir, L, R) whose leftmost leaf is already open is built by splitting
+// that leaf to create R's leftmost leaf, then building L and R recursively.
+func template_steps(root *config.DashTemplateNode) ([]templateStep, error) {
+	if err := validate_template(root); err != nil {
+		return nil, err
+	}
+	first := root
+	for !first.IsLeaf() {
+		first = first.Left
+	}
+	steps := []templateStep{{Key: templateSessionKeys[first.Session], Target: -1}}
+
+	var build func(n *config.DashTemplateNode, anchor int)
+	build = func(n *config.DashTemplateNode, anchor int) {
+		if n.IsLeaf() {
+			return
+		}
+		right_first := n.Right
+		for !right_first.IsLeaf() {
+			right_first = right_first.Left
+		}
+		dir := SplitH
+		if n.Split == "v" {
+			dir = SplitV
+		}
+		steps = append(steps, templateStep{Key: templateSessionKeys[right_first.Session], Target: anchor, Dir: dir})
+		right_anchor := len(steps) - 1
+		build(n.Left, anchor)
+		build(n.Right, right_anchor)
+	}
+	build(root, 0)
+	return steps, nil
+}
+
+// validate_template checks that every node is either a known session type
+// or a complete h/v split.
+func validate_template(n *config.DashTemplateNode) error {
+	if n == nil {
+		return fmt.Errorf("empty template")
+	}
+	if n.IsLeaf() {
+		if _, ok := templateSessionKeys[n.Session]; !ok {
+			return fmt.Errorf("unknown session type %q", n.Session)
+		}
+		return nil
+	}
+	if n.Left == nil || n.Right == nil {
+		return fmt.Errorf("split %q needs both left and right", n.Split)
+	}
+	if n.Split != "h" && n.Split != "v" {
+		return fmt.Errorf("split must be \"h\" or \"v\", got %q", n.Split)
+	}
+	if err := validate_template(n.Left); err != nil {
+		return err
+	}
+	return validate_template(n.Right)
+}
+
+// template_names returns configured template names in sorted order.
+func (m Model) template_names() []string {
+	if m.cfg == nil {
+		return nil
+	}
+	names := make([]string, 0, len(m.cfg.Dash.Templates))
+	for name := range m.cfg.Dash.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// open_template_picker opens a workspace template for the selected worktree.
+// With a single template it is applied directly.
+func (m Model) open_template_picker(wt worktree.Worktree) (Model, tea.Cmd) {
+	names := m.template_names()
+	if len(names) == 0 {
+		return m.show_notification("Templates", "No templates defined (dash.templates in workflow.config.js)")
+	}
+	if len(names) == 1 {
+		return m.apply_template(names[0], wt)
+	}
+	if len(names) > 9 {
+		names = names[:9]
+	}
+	actions := make([]ui.PickerAction, len(names))
+	for i, name := range names {
+		tpl := m.cfg.Dash.Templates[name]
+		actions[i] = ui.PickerAction{
+			Key:   fmt.Sprintf("%d", i+1),
+			Label: name,
+			Desc:  strings.Join(tpl.Leaves(), " · "),
+		}
+	}
+	return m.open_panel_picker("Templates", actions, pickerTemplate)
+}
+
+// execute_template_action applies the template chosen in the picker.
+func (m Model) execute_template_action(action ui.PickerAction) (Model, tea.Cmd) {
+	wt := m.selected_worktree()
+	if wt == nil {
+		return m, nil
+	}
+	return m.apply_template(action.Label, *wt)
+}
+
+// apply_template opens a new tab group for wt laid out as the named template.
+func (m Model) apply_template(name string, wt worktree.Worktree) (Model, tea.Cmd) {
+	tpl, ok := m.cfg.Dash.Templates[name]
+	if !ok {
+		return m, nil
+	}
+	steps, err := template_steps(&tpl)
+	if err != nil {
+		return m.show_notification("Templates", fmt.Sprintf("%s: %v", name, err))
+	}
+	if max := m.term_mgr.MaxPanes(); len(steps) > max {
+		return m.show_notification("Templates", fmt.Sprintf("%s needs %d panes (max %d per group)", name, len(steps), max))
+	}
+
+	w, h := m.right_pane_dimensions()
+	ids := make([]int, len(steps))
+	for i, step := range steps {
+		spec, _ := m.split_session_spec(step.Key, wt.Alias, wt.Path)
+		label := labels.Tab(spec.prefix, wt.Alias)
+
+		var s *terminal.Session
+		var err error
+		if i == 0 {
+			s, err = m.term_mgr.OpenNew(label, spec.cmd, spec.args, w, h, spec.dir)
+		} else {
+			s, err = m.term_mgr.SplitInto(ids[step.Target], label, spec.cmd, spec.args, w, h, spec.dir, step.Dir)
+		}
+		if err != nil {
+			// Don't leave a half-built layout behind: close the panes
+			// opened so far, the later splits first
+			for j := i - 1; j >= 0; j-- {
+				m.term_mgr.CloseBySessionID(ids[j])
+			}
+			if m.term_mgr.Count() == 0 && m.focus == PanelTerminal {
+				m.focus = PanelWorktrees
+			}
+			m.sync_tab_cursor_from_active()
+			return m.show_notification("Templates", fmt.Sprintf("%s failed at pane %d of %d: %v", name, i+1, len(steps), err))
+		}
+		ids[i] = s.ID
+		s.SetWorktree(wt.Alias, wt.Path)
+		if spec.send_keys != "" && s.PaneID() != "" {
+			m.term_mgr.Server().Run("send-keys", "-t", s.PaneID(), spec.send_keys, "Enter")
+		}
+	}
+
+	m.sync_tab_cursor_from_active()
+	m.terminal_output = ""
+	m.prev_focus = m.focus
+	m.focus = PanelTerminal
+	if m.pane_layout != nil {
+		m.pane_layout.FocusRight()
+	}
+	return m, tick_after(100*time.Millisecond, "render")
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/elvisnm/wt/internal/config"
+)
+
+func parse_template(t *testing.T, src string) *config.DashTemplateNode {
+	t.Helper()
+	var n config.DashTemplateNode
+	if err := json.Unmarshal([]byte(src), &n); err != nil {
+		t.Fatalf("unmarshal %s: %v", src, err)
+	}
+	return &n
+}
+
+func TestTemplateSteps_SingleLeaf(t *testing.T) {
+	steps, err := template_steps(parse_template(t, `"shell"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 1 || steps[0].Key != "b" {
+		t.Errorf("steps = %+v, want one shell step", steps)
+	}
+}
+
+func TestTemplateSteps_DevLayout(t *testing.T) {
+	// Claude left, Logs top-right, Shell bottom-right
+	tpl := parse_template(t, `{"split": "h", "left": "claude", "right": {"split": "v", "left": "logs", "right": "shell"}}`)
+	steps, err := template_steps(tpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []templateStep{
+		{Key: "c", Target: -1},
+		{Key: "l", Target: 0, Dir: SplitH},
+		{Key: "b", Target: 1, Dir: SplitV},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("steps = %+v, want %+v", steps, want)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("steps[%d] = %+v, want %+v", i, steps[i], want[i])
+		}
+	}
+}
+
+func TestTemplateSteps_LeftSubtree(t *testing.T) {
+	// 2x2 grid built column-first: the left column splits the anchor itself
+	tpl := parse_template(t, `{"split": "h",
+		"left": {"split": "v", "left": "claude", "right": "zsh"},
+		"right": {"split": "v", "left": "logs", "right": "shell"}}`)
+	steps, err := template_steps(tpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []templateStep{
+		{Key: "c", Target: -1},
+		{Key: "l", Target: 0, Dir: SplitH},
+		{Key: "z", Target: 0, Dir: SplitV},
+		{Key: "b", Target: 1, Dir: SplitV},
+	}
+	for i := range want {
+		if i >= len(steps) || steps[i] != want[i] {
+			t.Fatalf("steps = %+v, want %+v", steps, want)
+		}
+	}
+}
+
+func TestTemplateSteps_Invalid(t *testing.T) {
+	tests := []string{
+		`"vim"`,
+		`{"split": "x", "left": "shell", "right": "zsh"}`,
+		`{"split": "h", "left": "shell"}`,
+	}
+	for _, src := range tests {
+		if _, err := template_steps(parse_template(t, src)); err == nil {
+			t.Errorf("template_steps(%s) should fail", src)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package app
+
+import (
+	"github.com/elvisnm/wt/internal/theme"
+	"github.com/elvisnm/wt/internal/ui"
+)
+
+// ApplyTheme resolves the user's theme and color overrides and makes them the
+// current palette for the ui package and the tmux hooks. It returns a
+// description of every ignored setting.
+func ApplyTheme(name string, colors map[string]string) []string {
+	t, problems := theme.Resolve(name, colors)
+	theme.Set(t)
+	ui.ApplyTheme(t)
+	return problems
+}
@@ -95,6 +95,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			tick_after(3*time.Second, "stats"),
 			tick_after(100*time.Millisecond, "render"),
 			tick_after(1*time.Second, "agent-poll"),
+			m.cmd_refresh_git_status(),
 		}
 		wt := m.selected_worktree()
 		if wt != nil && wt.Running {
@@ -109,7 +110,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case MsgStatusUpdated:
 		debug_log("[tick] MsgStatusUpdated: count=%d", len(msg.Worktrees))
 		m.update_worktrees(msg.Worktrees)
-		cmds := []tea.Cmd{tick_after(5*time.Second, "status")}
+		cmds := []tea.Cmd{tick_after(5*time.Second, "status"), m.cmd_refresh_git_status()}
 		wt := m.selected_worktree()
 		if wt != nil {
 			debug_log("[tick] selected: %s type=%v running=%v svcs=%d cursor=%d", wt.Alias, wt.Type, wt.Running, len(m.services), m.cursor)
@@ -138,6 +139,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for i := range msg.Worktrees {
 			stats_map[msg.Worktrees[i].Path] = &msg.Worktrees[i]
 		}
+		selected := m.selected_name()
 		for i := range m.worktrees {
 			if s, ok := stats_map[m.worktrees[i].Path]; ok {
 				m.worktrees[i].CPU = s.CPU
@@ -145,6 +147,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.worktrees[i].MemPct = s.MemPct
 			}
 		}
+		// Sorting by memory can reorder the list; keep the cursor on the same worktree
+		m.select_worktree_name(selected)
 		return m, tick_after(3*time.Second, "stats")
 
 	case MsgUsageUpdated:
@@ -270,6 +274,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, tea.Batch(m.cmd_discover(), m.refresh_services())
 
+	case MsgScrollbackSearched:
+		return m.handle_scrollback_searched(msg)
+
+	case MsgBroadcastPolled:
+		return m.handle_broadcast_polled(msg)
+
+	case MsgGitStatus:
+		return m.handle_git_status(msg)
+
+	case MsgCleanupScanned:
+		return m.handle_cleanup_scanned(msg)
+
+	case MsgCreateStep:
+		m, cmd := m.handle_create_step(msg)
+		m.recalc_layout()
+		return m, cmd
+
+	case MsgRenameStep:
+		m, cmd := m.handle_rename_step(msg)
+		m.recalc_layout()
+		return m, cmd
+
+	case MsgCheckpoint:
+		return m.handle_checkpoint(msg)
+
+	case MsgBulkItemDone:
+		return m.handle_bulk_item_done(msg)
+
+	case MsgSettingsProblems:
+		return m.show_notification(msg.Title, "Ignored: "+strings.Join(msg.Problems, "; "))
+
 	case MsgOpenBuildAfterStart:
 		m.actions_pending = nil
 		m.activity = ""
@@ -287,7 +322,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "status":
 			wts := make([]worktree.Worktree, len(m.worktrees))
 			copy(wts, m.worktrees)
-			return m, cmd_fetch_status(m.worktrees_dir, wts, m.cfg, m.term_mgr)
+			return m, cmd_fetch_status(m.repo_root, m.worktrees_dir, wts, m.cfg, m.term_mgr)
 		case "stats":
 			wts := make([]worktree.Worktree, len(m.worktrees))
 			copy(wts, m.worktrees)
@@ -315,6 +350,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tick_after(80*time.Millisecond, "spin")
 			}
 			return m, nil
+		case "broadcast":
+			return m, m.cmd_poll_broadcasts()
 		case "clear-activity":
 			m.activity = ""
 			return m, nil
@@ -351,8 +388,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m, _ = m.handle_heihei_sentinel()
 				}
 			}
-			// Auto-close dead Logs tabs
-			if m.term_mgr != nil && m.term_mgr.CloseDeadLogs() {
+			// Auto-close finished tabs according to their label type's exit policy
+			if m.term_mgr != nil && m.term_mgr.CloseDeadWhere(m.close_on_exit) {
 				m.focus_worktrees_if_empty()
 			}
 			// Auto-close dead Settings tab
@@ -415,8 +452,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handle_mouse(msg)
 
 	case tea.KeyMsg:
-		// Shift+S opens settings from anywhere (even over overlays)
-		if msg.String() == "S" {
+		// Shift+S opens settings from anywhere (even over overlays), except
+		// while typing a worktree filter
+		if msg.String() == "S" && !m.list_editing {
 			return m.open_settings()
 		}
 		// In pane layout mode, the right pane gets native input via tmux focus.
@@ -430,9 +468,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.input_active {
 			return m.handle_input_key(msg)
 		}
+		if m.palette_open {
+			return m.handle_palette_key(msg)
+		}
 		if m.picker_open {
 			return m.handle_picker_key(msg)
 		}
+		if m.rename != nil && !m.rename.started {
+			return m.handle_rename_plan_key(msg)
+		}
+		if m.list_editing {
+			return m.handle_list_filter_key(msg)
+		}
 		return m.handle_key(msg)
 	}
 
@@ -483,7 +530,7 @@ func (m Model) handle_mouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 				}
 			}
 		case m.focus == PanelWorktrees:
-			if m.cursor < len(m.worktrees)-1 {
+			if m.cursor < len(m.visible_worktrees())-1 {
 				m.cursor++
 				m.details_scroll = 0
 				m.close_preview()
@@ -501,19 +548,17 @@ func (m Model) handle_mouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) clamp_cursor() {
-	if len(m.worktrees) == 0 {
+	n := len(m.visible_worktrees())
+	if n == 0 {
 		m.cursor = 0
-	} else if m.cursor >= len(m.worktrees) {
-		m.cursor = len(m.worktrees) - 1
+	} else if m.cursor >= n {
+		m.cursor = n - 1
 	}
 }
 
 // update_worktrees replaces the worktree list while preserving cursor selection
 func (m *Model) update_worktrees(wts []worktree.Worktree) {
-	var selected_name string
-	if m.cursor >= 0 && m.cursor < len(m.worktrees) {
-		selected_name = m.worktrees[m.cursor].Name
-	}
+	selected_name := m.selected_name()
 
 	// Worktrees with pending actions (removing, starting, etc.) are kept
 	// from the current state. Periodic discovery can re-find a directory
@@ -548,21 +593,18 @@ func (m *Model) update_worktrees(wts []worktree.Worktree) {
 	}
 
 	m.worktrees = wts
+	m.apply_repo_state()
+	m.apply_git_status()
+	m.prune_marks()
 
-	if selected_name != "" {
-		for i, wt := range m.worktrees {
-			if wt.Name == selected_name {
-				m.cursor = i
-				debug_log("[update_wt] stored %d worktrees, cursor=%d (%s)", len(wts), m.cursor, selected_name)
-				for j, w := range m.worktrees {
-					debug_log("[update_wt]   [%d] %s type=%v running=%v mode=%q", j, w.Alias, w.Type, w.Running, w.Mode)
-				}
-				return
-			}
+	if m.select_worktree_name(selected_name) {
+		debug_log("[update_wt] stored %d worktrees, cursor=%d (%s)", len(wts), m.cursor, selected_name)
+		for j, w := range m.worktrees {
+			debug_log("[update_wt]   [%d] %s type=%v running=%v mode=%q", j, w.Alias, w.Type, w.Running, w.Mode)
 		}
+		return
 	}
 
-	m.clamp_cursor()
 	debug_log("[update_wt] stored %d worktrees, cursor=%d (clamped, prev=%q)", len(wts), m.cursor, selected_name)
 	for j, w := range m.worktrees {
 		debug_log("[update_wt]   [%d] %s type=%v running=%v mode=%q", j, w.Alias, w.Type, w.Running, w.Mode)
@@ -570,8 +612,9 @@ func (m *Model) update_worktrees(wts []worktree.Worktree) {
 }
 
 func (m Model) selected_worktree() *worktree.Worktree {
-	if m.cursor >= 0 && m.cursor < len(m.worktrees) {
-		wt := m.worktrees[m.cursor]
+	rows := m.visible_worktrees()
+	if m.cursor >= 0 && m.cursor < len(rows) {
+		wt := rows[m.cursor]
 		return &wt
 	}
 	return nil
@@ -594,11 +637,30 @@ func (m Model) handle_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, Keys.Escape):
+		if m.create != nil && m.create.failed {
+			m.create = nil
+			m.recalc_layout()
+			return m, nil
+		}
+		if m.rename != nil && m.rename.failed && m.rename.done() {
+			m.rename = nil
+			m.recalc_layout()
+			return m, nil
+		}
 		if m.focus == PanelTasks && m.tasks_detail != nil {
 			m.tasks_detail = nil
 			m.recalc_layout()
 			return m, nil
 		}
+		if m.focus == PanelWorktrees && m.list_query != "" {
+			selected := m.selected_name()
+			m.list_query = ""
+			return m.relist(selected)
+		}
+		if m.focus == PanelWorktrees && len(m.marked) > 0 {
+			m.marked = nil
+			return m, nil
+		}
 		if m.focus == PanelTerminal {
 			m.focus = m.prev_focus
 		} else if m.focus != PanelWorktrees {
@@ -632,18 +694,23 @@ func (m Model) handle_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.open_help()
 	}
 
+	// Command palette
+	if key.Matches(msg, Keys.Palette) {
+		return m.open_palette()
+	}
+
 	// Panel jump shortcuts: a(ctive tabs), w(orktrees), s(ervices)
-	switch msg.String() {
-	case "a":
+	switch {
+	case key.Matches(msg, Keys.FocusTabs):
 		m.close_preview()
 		m.prev_focus = m.focus
 		m.focus = PanelTerminal
 		return m, nil
-	case "w":
+	case key.Matches(msg, Keys.FocusWorktrees):
 		m.close_preview()
 		m.focus = PanelWorktrees
 		return m, nil
-	case "s":
+	case key.Matches(msg, Keys.FocusServices):
 		m.focus = PanelServices
 		return m, nil
 	}
@@ -662,38 +729,11 @@ func (m Model) handle_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Global operations (Shift+key) — gated by feature flags when config is available
-	switch msg.String() {
-	case "A":
-		if m.cfg == nil || m.cfg.FeatureEnabled("awsCredentials") {
-			debug_log("[aws] Shift+A pressed")
-			// SSO mode: check session first
-			if profile := m.cfg.AwsSsoProfile(); profile != "" {
-				return m.check_sso_then_login()
-			}
-			return m.open_aws_keys()
-		}
-	case "B":
-		return m.open_db_picker()
-	case "D":
-		return m.toggle_details()
-	case "X":
-		if m.cfg == nil || m.cfg.FeatureEnabled("admin") {
-			return m.toggle_admin()
-		}
-	case "L":
-		if m.cfg == nil || m.cfg.FeatureEnabled("lan") {
-			return m.toggle_lan()
-		}
-	case "M":
-		return m.open_maintenance_picker()
-	case "K":
-		return m.toggle_skip_worktree()
-	case "H":
+	if msg.String() == "H" {
 		return m.play_heihei()
-	case "U":
-		return m.toggle_usage()
-	case "T":
-		return m.toggle_tasks()
+	}
+	if a, ok := m.find_global_action(msg); ok {
+		return a.run(m)
 	}
 
 	switch m.focus {
@@ -731,7 +771,7 @@ func (m Model) handle_worktree_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, Keys.Down):
-		if m.cursor < len(m.worktrees)-1 {
+		if m.cursor < len(m.visible_worktrees())-1 {
 			m.cursor++
 			m.details_scroll = 0
 			m.close_preview()
@@ -767,8 +807,8 @@ func (m Model) handle_worktree_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		prev := m.cursor
 		m.cursor += page
-		if m.cursor >= len(m.worktrees) {
-			m.cursor = len(m.worktrees) - 1
+		if n := len(m.visible_worktrees()); m.cursor >= n {
+			m.cursor = n - 1
 		}
 		if m.cursor != prev {
 			m.details_scroll = 0
@@ -780,91 +820,46 @@ func (m Model) handle_worktree_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case key.Matches(msg, Keys.Enter):
+		if len(m.marked) > 0 {
+			return m.open_bulk_menu()
+		}
 		wt := m.selected_worktree()
 		if wt != nil {
 			actions := m.actions_for_worktree(*wt)
 			return m.open_panel_picker("Choose an option - "+wt.Alias, actions, pickerWorktree)
 		}
 		return m, nil
-	}
 
-	// "n" works even with an empty worktree list
-	if msg.String() == "n" {
-		debug_log("[create] 'n' pressed: opening create wizard")
-		// SSO check before opening wizard
-		if m, cmd, gated := m.sso_gate("create", nil); gated {
-			return m, cmd
-		}
-		return m.open_create(m.selected_worktree())
+	case key.Matches(msg, Keys.Filter):
+		return m.open_list_filter()
+
+	case key.Matches(msg, Keys.ListView):
+		return m.open_list_view()
+
+	case key.Matches(msg, Keys.Mark):
+		return m.toggle_mark()
+
+	case key.Matches(msg, Keys.MarkAll):
+		return m.mark_all()
 	}
 
-	// Quick action keys
 	wt := m.selected_worktree()
 	if wt == nil {
-		return m, nil
-	}
-
-	switch msg.String() {
-	case "b":
-		return m.open_bash(*wt)
-	case "c":
-		return m.open_claude(*wt)
-	case "z":
-		return m.open_local_shell(*wt)
-	case "d":
-		return m.toggle_details()
-	case "l":
-		return m.open_logs(*wt)
-	case "i":
-		return m.open_worktree_info()
-	case "g":
-		return m.open_pull(*wt)
-	case "e":
-		if wt.HostBuild && wt.Running {
-			return m.open_esbuild_watch(*wt)
-		}
-	case "r":
-		if wt.Running {
-			// SSO check before restarting — ensures credentials are valid
-			if profile := m.sso_profile(); profile != "" {
-				wtCopy := *wt
-				m.pending_sso_action = "restart"
-				m.pending_sso_start = &wtCopy
-				m.activity = "Checking AWS SSO session..."
-				return m, func() tea.Msg {
-					valid := aws.CheckSession(profile)
-					return MsgSsoSessionCheck{Valid: valid}
-				}
-			}
-			if wt.Type == worktree.TypeLocal {
-				return m.restart_local_services(*wt)
-			}
-			if wt.HostBuild {
-				return m.restart_host_build(*wt)
-			}
-			return m, cmd_docker_action("restart", *wt, m.repo_root, m.cfg)
-		}
-	case "t":
-		if wt.Running {
-			if wt.Type == worktree.TypeLocal {
-				return m.stop_dev_server(*wt)
-			}
-			if wt.HostBuild {
-				return m.stop_host_build(*wt)
-			}
-			return m, cmd_docker_action("stop", *wt, m.repo_root, m.cfg)
-		}
-	case "u":
-		if !wt.Running {
-			if m, cmd, gated := m.sso_gate("start", wt); gated {
+		// Create works even with an empty worktree list
+		if a, ok := find_worktree_action_by_id("create"); ok && msg.String() == a.key {
+			debug_log("[create] 'n' pressed: opening quick create")
+			if m, cmd, gated := m.sso_gate("create", nil); gated {
 				return m, cmd
 			}
-			return m.start_worktree(*wt)
+			return m.open_quick_create()
 		}
-	case "x":
-		return m.remove_worktree(*wt)
+		return m, nil
 	}
 
+	// Quick action keys, from the action registry
+	if a, ok := find_worktree_action(msg.String()); ok && a.quick_key(&m, *wt) {
+		return a.invoke(m, *wt)
+	}
 	return m, nil
 }
 
@@ -976,38 +971,13 @@ func (m Model) handle_services_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	switch msg.String() {
-	case "l":
-		if m.service_cursor >= 0 && m.service_cursor < len(m.services) {
-			m.close_preview()
-			svc := m.services[m.service_cursor]
-			return m.open_service_logs(*wt, svc)
-		}
-	case "r":
-		if m.is_static_local(*wt) {
-			return m, m.show_result("Per-service restart not available")
-		}
-		if m.service_cursor >= 0 && m.service_cursor < len(m.services) {
-			svc := m.services[m.service_cursor]
-			m.activity = fmt.Sprintf("Restarting %s...", svc.DisplayName)
-			return m, cmd_service_action("restart", *wt, svc, m.cfg)
-		}
-	case "t":
-		if m.is_static_local(*wt) {
-			return m, m.show_result("Per-service stop not available")
-		}
-		if m.service_cursor >= 0 && m.service_cursor < len(m.services) {
-			svc := m.services[m.service_cursor]
-			m.activity = fmt.Sprintf("Stopping %s...", svc.DisplayName)
-			return m, cmd_service_action("stop", *wt, svc, m.cfg)
-		}
+	if a, ok := find_panel_action(service_actions, msg.String()); ok {
+		return a.run(m)
 	}
-
 	return m, nil
 }
 
 func (m Model) handle_terminal_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	s := m.term_mgr.Active()
 	tab_labels := m.term_mgr.TabLabels()
 
 	switch {
@@ -1053,88 +1023,14 @@ func (m Model) handle_terminal_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	case msg.String() == "h":
-		m.term_mgr.PrevTab()
-		m.sync_tab_cursor_from_active()
-		return m, nil
-
-	case msg.String() == "l":
-		m.term_mgr.NextTab()
-		m.sync_tab_cursor_from_active()
-		return m, nil
-
-	case msg.String() == "f":
-		// Fullscreen — zoom the right pane and focus it
-		if s != nil && s.IsAlive() && m.pane_layout != nil {
-			m.pane_layout.ZoomRight()
-			m.pane_layout.FocusRight()
-		}
-		return m, nil
-
-	case msg.String() == "x":
-		tab_labels = m.term_mgr.TabLabels()
-		if m.tab_cursor >= 0 && m.tab_cursor < len(tab_labels) {
-			tl := tab_labels[m.tab_cursor]
-			if tl.IsGroupHead {
-				// Close entire group
-				m.term_mgr.CloseActive()
-			} else if tl.IsGroupChild {
-				// Close just this one pane
-				m.term_mgr.CloseBySessionID(tl.SessionID)
-			} else {
-				// Standalone tab
-				m.term_mgr.CloseActive()
-			}
-		} else {
-			m.term_mgr.CloseActive()
-		}
-		// Clamp tab_cursor after close
-		new_labels := m.term_mgr.TabLabels()
-		if m.tab_cursor >= len(new_labels) {
-			m.tab_cursor = len(new_labels) - 1
-		}
-		if m.tab_cursor < 0 {
-			m.tab_cursor = 0
-		}
-		if m.term_mgr.Count() == 0 {
-			m.focus = PanelWorktrees
-		}
-		return m, nil
-
-	case msg.String() == "|":
-		return m.open_split_picker(SplitH)
-
-	case msg.String() == "_":
-		return m.open_split_picker(SplitV)
-
-	case msg.String() == "m":
-		return m.open_merge_picker()
-
-	case msg.String() == "r":
-		// Rename the session under the cursor
-		tab_labels = m.term_mgr.TabLabels()
-		if m.tab_cursor >= 0 && m.tab_cursor < len(tab_labels) {
-			tl := tab_labels[m.tab_cursor]
-			if tl.SessionID > 0 {
-				session_id := tl.SessionID
-				return m.open_panel_input("Rename", "New name:", func(mdl *Model, val string) (Model, tea.Cmd) {
-					name := strings.TrimSpace(val)
-					if name == "" {
-						return *mdl, nil
-					}
-					for _, s := range mdl.term_mgr.Sessions() {
-						if s.ID == session_id {
-							s.Label = name
-							break
-						}
-					}
-					return *mdl, nil
-				})
-			}
-		}
-		return m, nil
 	}
 
+	if a, ok := find_panel_action(tab_actions, msg.String()); ok {
+		return a.run(m)
+	}
+	if a, ok := find_panel_action(split_actions, msg.String()); ok {
+		return a.run(m)
+	}
 	return m, nil
 }
 
@@ -1376,8 +1272,9 @@ func (m Model) handle_picker_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Handle direct key presses in picker
-	for _, a := range m.picker_actions {
+	for i, a := range m.picker_actions {
 		if msg.String() == a.Key {
+			m.picker_cursor = i
 			m.picker_open = false
 			m.recalc_layout()
 			return m.dispatch_picker(a)
@@ -1394,6 +1291,14 @@ func (m Model) dispatch_picker(action ui.PickerAction) (Model, tea.Cmd) {
 		return m.execute_db_action(action)
 	case pickerMaintenance:
 		return m.execute_maintenance_action(action)
+	case pickerCleanup:
+		return m.execute_cleanup_action(action)
+	case pickerOverlaps:
+		return m.execute_overlap_action(action)
+	case pickerSparse:
+		return m.execute_sparse_action(action)
+	case pickerCreateEnv, pickerCreateMode, pickerCreateDB, pickerCreateChanges, pickerCreateSet, pickerCreateSparse:
+		return m.execute_create_choice(action)
 	case pickerRemove:
 		return m.execute_remove_action(action)
 	case pickerStartService:
@@ -1408,39 +1313,64 @@ func (m Model) dispatch_picker(action ui.PickerAction) (Model, tea.Cmd) {
 		return m.execute_merge_target(action)
 	case pickerMergeDir:
 		return m.execute_merge_direction(action)
+	case pickerCapture:
+		return m.execute_capture_action(action)
+	case pickerSearch:
+		return m.execute_search_action(action)
+	case pickerRecordings:
+		return m.execute_recordings_action(action)
+	case pickerTemplate:
+		return m.execute_template_action(action)
+	case pickerBroadcast:
+		return m.execute_broadcast_action(action)
+	case pickerFinished:
+		return m.execute_finished_action(action)
+	case pickerListView:
+		return m.execute_list_view_action(action)
+	case pickerBulk:
+		return m.execute_bulk_action(action)
+	case pickerBulkResult:
+		return m.execute_bulk_result(action)
+	case pickerNote:
+		return m.execute_note_action(action)
+	case pickerRename:
+		return m.execute_rename_choice(action)
 	default:
 		return m.execute_picker_action(action)
 	}
 }
 
-// execute_split_action creates a new split session based on the selected session type.
-func (m Model) execute_split_action(action ui.PickerAction, dir SplitDir) (Model, tea.Cmd) {
-	w, h := m.right_pane_dimensions()
-	alias := m.split_target_alias
-	wt_dir := m.split_target_dir
-	target_id := m.split_target_session_id
+// sessionSpec describes how to launch one of the split session types.
+// When send_keys is set, cmd is an interactive shell and send_keys is typed into it.
+type sessionSpec struct {
+	cmd       string
+	args      []string
+	dir       string
+	prefix    string
+	send_keys string
+}
 
-	var cmd_name string
-	var args []string
-	var session_dir string
-	var label_prefix string
+// split_session_spec resolves a split picker key (b, c, C, z, l) to the command
+// that session type runs for the given worktree.
+func (m Model) split_session_spec(key, alias, wt_dir string) (sessionSpec, bool) {
+	var spec sessionSpec
 
-	switch action.Key {
+	switch key {
 	case "b":
 		// Shell — docker exec if running container, otherwise host shell
 		wt := m.find_worktree_by_alias(alias)
 		if wt != nil && wt.Type == worktree.TypeDocker && wt.Running {
-			cmd_name = "docker"
-			args = []string{"exec", "-it", wt.Container, "bash"}
+			spec.cmd = "docker"
+			spec.args = []string{"exec", "-it", wt.Container, "bash"}
 		} else {
 			shell := os.Getenv("SHELL")
 			if shell == "" {
 				shell = "bash"
 			}
-			cmd_name = shell
-			session_dir = wt_dir
+			spec.cmd = shell
+			spec.dir = wt_dir
 		}
-		label_prefix = labels.Shell
+		spec.prefix = labels.Shell
 	case "c", "C":
 		claude_cmd := "claude"
 		if m.cfg != nil {
@@ -1448,64 +1378,68 @@ func (m Model) execute_split_action(action ui.PickerAction, dir SplitDir) (Model
 				claude_cmd = c.Cmd
 			}
 		}
-		if action.Key == "C" || m.claude_auto_mode {
+		spec.dir = wt_dir
+		spec.prefix = labels.Claude
+		if key == "C" || m.claude_auto_mode {
 			// Auto-mode: open a shell, then send-keys "claude --enable-auto-mode".
 			// Passing --enable-auto-mode via exec doesn't activate auto mode.
 			shell := os.Getenv("SHELL")
 			if shell == "" {
 				shell = "zsh"
 			}
-			cmd_name = shell
-			session_dir = wt_dir
-			label_prefix = labels.Claude
-			label := labels.Tab(label_prefix, alias)
-			s, err := m.term_mgr.SplitInto(target_id, label, cmd_name, args, w, h, session_dir, dir)
-			if err != nil {
-				m.activity = fmt.Sprintf("Split failed: %v", err)
-				return m, nil
-			}
-			s.SetWorktree(alias, wt_dir)
-			if s.PaneID() != "" {
-				m.term_mgr.Server().Run("send-keys", "-t", s.PaneID(), "claude --enable-auto-mode", "Enter")
-			}
-			m.prev_focus = m.focus
-			m.focus = PanelTerminal
-			return m, tick_after(100*time.Millisecond, "render")
+			spec.cmd = shell
+			spec.send_keys = "claude --enable-auto-mode"
+		} else {
+			spec.cmd = claude_cmd
 		}
-		cmd_name = claude_cmd
-		session_dir = wt_dir
-		label_prefix = labels.Claude
 	case "z":
 		shell := os.Getenv("SHELL")
 		if shell == "" {
 			shell = "zsh"
 		}
-		cmd_name = shell
-		session_dir = wt_dir
-		label_prefix = labels.Zsh
+		spec.cmd = shell
+		spec.dir = wt_dir
+		spec.prefix = labels.Zsh
 	case "l":
 		wt := m.find_worktree_by_alias(alias)
 		if wt != nil && wt.Type == worktree.TypeDocker && wt.Running {
-			cmd_name = "docker"
-			args = []string{"exec", "-it", wt.Container, "pm2", "logs", "--lines", "100"}
+			spec.cmd = "docker"
+			spec.args = []string{"exec", "-it", wt.Container, "pm2", "logs", "--lines", "100"}
 		} else {
-			cmd_name = "pm2"
-			args = []string{"logs", "--lines", "100"}
-			session_dir = wt_dir
+			spec.cmd = "pm2"
+			spec.args = []string{"logs", "--lines", "100"}
+			spec.dir = wt_dir
 		}
-		label_prefix = labels.Logs
+		spec.prefix = labels.Logs
 	default:
+		return spec, false
+	}
+	return spec, true
+}
+
+// execute_split_action creates a new split session based on the selected session type.
+func (m Model) execute_split_action(action ui.PickerAction, dir SplitDir) (Model, tea.Cmd) {
+	w, h := m.right_pane_dimensions()
+	alias := m.split_target_alias
+	wt_dir := m.split_target_dir
+	target_id := m.split_target_session_id
+
+	spec, ok := m.split_session_spec(action.Key, alias, wt_dir)
+	if !ok {
 		return m, nil
 	}
 
-	label := labels.Tab(label_prefix, alias)
+	label := labels.Tab(spec.prefix, alias)
 
-	s, err := m.term_mgr.SplitInto(target_id, label, cmd_name, args, w, h, session_dir, dir)
+	s, err := m.term_mgr.SplitInto(target_id, label, spec.cmd, spec.args, w, h, spec.dir, dir)
 	if err != nil {
 		m.activity = fmt.Sprintf("Split failed: %v", err)
 		return m, nil
 	}
 	s.SetWorktree(alias, wt_dir)
+	if spec.send_keys != "" && s.PaneID() != "" {
+		m.term_mgr.Server().Run("send-keys", "-t", s.PaneID(), spec.send_keys, "Enter")
+	}
 
 	m.prev_focus = m.focus
 	m.focus = PanelTerminal
@@ -1535,70 +1469,9 @@ func (m Model) execute_picker_action(action ui.PickerAction) (Model, tea.Cmd) {
 	if wt == nil {
 		return m, nil
 	}
-
-	switch action.Key {
-	case "b":
-		return m.open_bash(*wt)
-	case "c":
-		return m.open_claude(*wt)
-	case "C":
-		return m.open_claude_auto(*wt)
-	case "z":
-		return m.open_local_shell(*wt)
-	case "l":
-		return m.open_logs(*wt)
-	case "n":
-		return m.open_create(wt)
-	case "g":
-		return m.open_pull(*wt)
-	case "e":
-		if wt.HostBuild {
-			return m.open_esbuild_watch(*wt)
-		}
-	case "r":
-		// SSO check before restarting — ensures credentials are valid
-		if profile := m.sso_profile(); profile != "" {
-			wtCopy := *wt
-			m.pending_sso_action = "restart"
-			m.pending_sso_start = &wtCopy
-			m.activity = "Checking AWS SSO session..."
-			return m, func() tea.Msg {
-				valid := aws.CheckSession(profile)
-				return MsgSsoSessionCheck{Valid: valid}
-			}
-		}
-		if wt.Type == worktree.TypeLocal {
-			return m.restart_local_services(*wt)
-		}
-		if wt.HostBuild {
-			return m.restart_host_build(*wt)
-		}
-		return m, cmd_docker_action("restart", *wt, m.repo_root, m.cfg)
-	case "t":
-		if wt.Type == worktree.TypeLocal {
-			return m.stop_dev_server(*wt)
-		}
-		if wt.HostBuild {
-			return m.stop_host_build(*wt)
-		}
-		return m, cmd_docker_action("stop", *wt, m.repo_root, m.cfg)
-	case "u":
-		if m, cmd, gated := m.sso_gate("start", wt); gated {
-			return m, cmd
-		}
-		return m.start_worktree(*wt)
-	case "o":
-		return m.open_start_service_picker(*wt)
-	case "p":
-		return m.open_stop_service_picker(*wt)
-	case "m":
-		return m.switch_mode(*wt)
-	case "i":
-		return m.open_worktree_info()
-	case "x":
-		return m.remove_worktree(*wt)
+	if a, ok := find_worktree_action(action.Key); ok && a.picker != nil && a.enabled(&m) {
+		return a.invoke(m, *wt)
 	}
-
 	return m, nil
 }
 
@@ -1680,6 +1553,17 @@ func (m Model) open_claude_auto(wt worktree.Worktree) (Model, tea.Cmd) {
 }
 
 func (m Model) open_claude_with_flags(wt worktree.Worktree, auto_mode bool) (Model, tea.Cmd) {
+	if m.claude_checkpoint {
+		// The tab opens once the checkpoint is taken (handle_checkpoint),
+		// before claude gets to change anything
+		m.activity = fmt.Sprintf("Checkpointing %s before Claude...", wt.Alias)
+		return m, cmd_claude_checkpoint(wt, auto_mode)
+	}
+	return m.launch_claude(wt, auto_mode)
+}
+
+// launch_claude opens Claude's tab in the worktree.
+func (m Model) launch_claude(wt worktree.Worktree, auto_mode bool) (Model, tea.Cmd) {
 	w, h := m.right_pane_dimensions()
 
 	var cmd_name string
@@ -2253,11 +2137,29 @@ func (m Model) open_settings() (Model, tea.Cmd) {
 // newly enabled panels.
 func (m *Model) reload_settings() tea.Cmd {
 	s := settings.Load()
+	selected := m.selected_name()
 	m.details_visible = s.DefaultPanels.Details
 	m.term_mgr.SetSplitLimits(s.MaxPanesPerGroup)
 	m.claude_auto_mode = s.ClaudeAutoMode
+	m.claude_checkpoint = s.ClaudeCheckpoint
+	m.exit_policies = s.ExitPolicies
+	m.list_view = s.WorktreeList
+	m.select_worktree_name(selected)
+
+	// The theme goes first: ConfigureBindings re-installs the border hook
+	theme_problems := ApplyTheme(s.Theme, s.Colors)
+	tmux_keys, problems := ApplyKeybindings(s.Keybindings)
+	if m.pane_layout != nil {
+		m.pane_layout.ConfigureBindings(tmux_keys)
+	}
 
 	var cmds []tea.Cmd
+	if cmd := cmd_settings_problems("Keybindings", problems); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := cmd_settings_problems("Theme", theme_problems); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
 
 	// Usage: trigger fetch if newly visible and no data loaded
 	if s.DefaultPanels.Usage && !m.usage_visible {
@@ -2734,6 +2636,10 @@ func (m Model) execute_maintenance_action(action ui.PickerAction) (Model, tea.Cm
 		script := filepath.Join(flow_scripts_dir(m.repo_root, m.cfg), "dc-rebuild-base.js")
 		args = []string{script}
 		label = labels.RebuildBase
+	case "c":
+		return m.open_cleanup()
+	case "o":
+		return m.open_overlaps()
 	default:
 		return m, nil
 	}
@@ -3078,32 +2984,12 @@ func (m Model) handle_tasks_list_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	task := m.selected_task()
-	if task == nil {
+	if m.selected_task() == nil {
 		return m, nil
 	}
-
-	switch msg.String() {
-	case "c":
-		id := task.ID
-		return m.open_panel_confirm("Close Task", fmt.Sprintf("Close task %s?", id),
-			func(mdl *Model) (Model, tea.Cmd) {
-				return *mdl, func() tea.Msg {
-					err := beads.CloseTask(id)
-					return MsgTaskActionDone{Err: err}
-				}
-			})
-	case "d":
-		id := task.ID
-		return m.open_panel_confirm("Delete Task", fmt.Sprintf("Delete task %s?", id),
-			func(mdl *Model) (Model, tea.Cmd) {
-				return *mdl, func() tea.Msg {
-					err := beads.DeleteTask(id)
-					return MsgTaskActionDone{Err: err}
-				}
-			})
+	if a, ok := find_panel_action(task_actions, msg.String()); ok {
+		return a.run(m)
 	}
-
 	return m, nil
 }
 
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/elvisnm/wt/internal/labels"
@@ -15,11 +16,7 @@ func (m Model) View() string {
 		return m.loading_view()
 	}
 
-	var selected_wt *worktree.Worktree
-	if m.cursor >= 0 && m.cursor < len(m.worktrees) {
-		wt := m.worktrees[m.cursor]
-		selected_wt = &wt
-	}
+	selected_wt := m.selected_worktree()
 
 	// 0 - Notification area (top of left column)
 	notify_panel := m.render_notify_panel(selected_wt)
@@ -36,11 +33,24 @@ func (m Model) View() string {
 	}
 	tab_infos := make([]ui.TabInfo, len(tab_labels))
 	for i, l := range tab_labels {
+		status := ""
+		if l.IsGroupHead && l.Synced {
+			status = "sync"
+		} else if !l.IsGroupHead {
+			status = m.broadcast_status(l.SessionID)
+		}
+		exit := ""
+		if !l.Alive && !l.IsGroupHead {
+			exit = ui.ExitSummary(l.ExitCode, l.Runtime)
+		}
 		tab_infos[i] = ui.TabInfo{
 			Index:        l.Index,
 			Label:        l.Label,
 			Active:       l.Active,
 			Alive:        l.Alive,
+			Recording:    l.Recording,
+			Status:       status,
+			Exit:         exit,
 			IsGroupHead:  l.IsGroupHead,
 			IsGroupChild: l.IsGroupChild,
 			GroupSize:    l.GroupSize,
@@ -54,11 +64,13 @@ func (m Model) View() string {
 	)
 
 	// 2 - Worktrees panel
+	rows, groups := m.worktree_rows()
 	worktree_panel := ui.RenderWorktreePanel(
-		m.worktrees, m.cursor,
+		rows, m.cursor,
 		m.width, m.layout.WorktreeHeight,
 		m.focus == PanelWorktrees,
 		m.cfg,
+		m.worktree_list_view(groups),
 	)
 
 	// 3 - Services panel
@@ -125,6 +137,12 @@ func (m Model) View() string {
 func (m Model) render_notify_panel(selected_wt *worktree.Worktree) string {
 	h := m.layout.NotifyHeight
 	switch {
+	case m.palette_open && m.palette_title != "":
+		return ui.RenderNotifyPaletteTitled(m.palette_title, m.palette_query, m.palette_items(), m.palette_cursor, m.width)
+
+	case m.palette_open:
+		return ui.RenderNotifyPalette(m.palette_query, m.palette_items(), m.palette_cursor, m.width)
+
 	case m.picker_open:
 		picker_title := m.picker_title(selected_wt)
 		return ui.RenderNotifyPicker(m.picker_actions, m.picker_cursor, m.width, picker_title)
@@ -135,6 +153,12 @@ func (m Model) render_notify_panel(selected_wt *worktree.Worktree) string {
 	case m.input_active:
 		return ui.RenderNotifyInput(m.input_prompt, m.input_value, m.width, h)
 
+	case m.create != nil:
+		return ui.RenderNotifySteps(m.create_title(), m.create.state, m.width)
+
+	case m.rename != nil:
+		return ui.RenderNotifySteps(m.rename_title(), m.rename.state, m.width)
+
 	case m.notify_open:
 		return ui.RenderNotifyMessage(m.notify_title, m.notify_message, m.width, h)
 
@@ -184,6 +208,48 @@ func (m Model) picker_title(selected_wt *worktree.Worktree) string {
 		return "Move into"
 	case pickerMergeDir:
 		return "Split Direction"
+	case pickerCapture:
+		return "Capture Scrollback"
+	case pickerRecordings:
+		if selected_wt != nil {
+			return labels.Tab("Recordings", selected_wt.Alias)
+		}
+		return "Recordings"
+	case pickerTemplate:
+		if selected_wt != nil {
+			return labels.Tab("Templates", selected_wt.Alias)
+		}
+		return "Templates"
+	case pickerFinished:
+		return m.finished_title()
+	case pickerBroadcast:
+		return "Broadcast to"
+	case pickerSearch:
+		return fmt.Sprintf("Search — %q", m.search_query)
+	case pickerListView:
+		return "Worktree list"
+	case pickerBulk:
+		return fmt.Sprintf("Bulk — %d marked", len(m.marked))
+	case pickerBulkResult:
+		return m.bulk_summary
+	case pickerCreateEnv, pickerCreateMode, pickerCreateDB, pickerCreateChanges, pickerCreateSet, pickerCreateSparse:
+		return labels.Tab("New worktree", m.create_plan.alias)
+	case pickerRename:
+		return labels.Tab("Rename", m.rename_plan.wt.Alias)
+	case pickerCleanup:
+		return m.cleanup_title()
+	case pickerOverlaps:
+		return m.overlaps_title()
+	case pickerNote:
+		if selected_wt != nil {
+			return labels.Tab("Note", selected_wt.Alias)
+		}
+		return "Note"
+	case pickerSparse:
+		if selected_wt != nil {
+			return labels.Tab("Sparse checkout", selected_wt.Alias)
+		}
+		return "Sparse checkout"
 	default:
 		if selected_wt != nil {
 			return labels.Tab(labels.Actions, selected_wt.Alias)
@@ -33,7 +33,7 @@ func TestViewWithPickerOpen(t *testing.T) {
 
 	// Manually set picker state (the bubbletea overlay path)
 	m.picker_open = true
-	m.picker_actions = ui.WorktreeActions
+	m.picker_actions = m.actions_for_worktree(m.worktrees[0])
 	m.picker_context = pickerWorktree
 
 	view := m.View()
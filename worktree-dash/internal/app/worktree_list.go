@@ -0,0 +1,305 @@
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/settings"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// list_sorts are the sort modes offered in the list view picker, by picker key.
+var list_sorts = []struct{ key, mode, desc string }{
+	{"s", worktree.SortStatus, "running first"},
+	{"n", worktree.SortName, "A-Z"},
+	{"l", worktree.SortLastUsed, "most recently used first"},
+	{"m", worktree.SortMemory, "highest first"},
+	{"c", worktree.SortCreated, "newest first"},
+}
+
+// branch_prefixes returns repo.branchPrefixes from the config.
+func (m Model) branch_prefixes() []string {
+	if m.cfg == nil {
+		return nil
+	}
+	return m.cfg.Repo.BranchPrefixes
+}
+
+// worktree_rows returns the worktrees as listed in the panel: narrowed by the
+// search query and quick filters, sorted, and grouped by branch prefix. The
+// second result is each row's group, or nil when grouping is off.
+// The cursor indexes into the first result.
+func (m Model) worktree_rows() ([]worktree.Worktree, []string) {
+	v := m.list_view
+	var rows []worktree.Worktree
+	for _, wt := range m.worktrees {
+		switch {
+		case v.Running && !wt.Running:
+			continue
+		case v.Docker && wt.Type != worktree.TypeDocker:
+			continue
+		case v.Dirty && wt.Git.Known() && !wt.Git.Dirty():
+			continue // until git status has been read, nothing is hidden
+		case !worktree.Matches(wt, m.list_query):
+			continue
+		}
+		rows = append(rows, wt)
+	}
+	rows = pinned_first(worktree.SortBy(rows, v.Sort))
+
+	prefixes := m.branch_prefixes()
+	if !v.Group || len(prefixes) == 0 {
+		return rows, nil
+	}
+	// Pinned worktrees come first, then groups in the configured prefix
+	// order; unmatched branches go last
+	rank := func(wt worktree.Worktree) int {
+		if wt.Pinned {
+			return -1
+		}
+		g := worktree.BranchGroup(wt.Branch, prefixes)
+		for i, p := range prefixes {
+			if p == g {
+				return i
+			}
+		}
+		return len(prefixes)
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rank(rows[i]) < rank(rows[j]) })
+	groups := make([]string, len(rows))
+	for i, wt := range rows {
+		groups[i] = worktree.BranchGroup(wt.Branch, prefixes)
+		if wt.Pinned {
+			groups[i] = ui.PinnedGroup
+		}
+	}
+	return rows, groups
+}
+
+// visible_worktrees returns the worktrees listed in the panel, in order.
+func (m Model) visible_worktrees() []worktree.Worktree {
+	rows, _ := m.worktree_rows()
+	return rows
+}
+
+// list_filtered reports whether any search text or quick filter is active.
+func (m Model) list_filtered() bool {
+	v := m.list_view
+	return m.list_query != "" || v.Running || v.Docker || v.Dirty
+}
+
+// list_summary describes the active quick filters, grouping and sort for the panel.
+func (m Model) list_summary() string {
+	v := m.list_view
+	var parts []string
+	for _, f := range []struct {
+		on   bool
+		name string
+	}{{v.Running, "running"}, {v.Docker, "docker"}, {v.Dirty, "dirty"}, {v.Group && len(m.branch_prefixes()) > 0, "grouped"}} {
+		if f.on {
+			parts = append(parts, f.name)
+		}
+	}
+	if v.Sort != "" && v.Sort != worktree.SortStatus {
+		parts = append(parts, "by "+worktree.SortLabel(v.Sort))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// worktree_list_view describes the list state for the worktree panel.
+func (m Model) worktree_list_view(groups []string) ui.WorktreeList {
+	return ui.WorktreeList{
+		Groups:  groups,
+		Query:   m.list_query,
+		Editing: m.list_editing,
+		Summary: m.list_summary(),
+		Total:   len(m.worktrees),
+
+		Marked:   m.marked,
+		Progress: m.bulk_progress(),
+		Expanded: m.expanded_sets,
+	}
+}
+
+// select_worktree_name moves the cursor to the named worktree if it is listed,
+// otherwise keeps the cursor in range. Reports whether the worktree was found.
+func (m *Model) select_worktree_name(name string) bool {
+	if name != "" {
+		for i, wt := range m.visible_worktrees() {
+			if wt.Name == name {
+				m.cursor = i
+				return true
+			}
+		}
+	}
+	m.clamp_cursor()
+	return false
+}
+
+// focus_worktree moves focus and the cursor to the named worktree and
+// reloads its services. title heads the notification when it isn't listed.
+func (m Model) focus_worktree(name, title string) (Model, tea.Cmd) {
+	m.focus = PanelWorktrees
+	if !m.select_worktree_name(name) {
+		return m.show_notification(title, "Worktree is hidden by the list filter")
+	}
+	m.details_scroll = 0
+	m.services = nil
+	m.service_cursor = 0
+	return m, m.refresh_services()
+}
+
+// relist re-applies the list view after it changed, keeping the selected
+// worktree under the cursor when it is still listed.
+func (m Model) relist(selected string) (Model, tea.Cmd) {
+	if m.select_worktree_name(selected) {
+		return m, nil
+	}
+	m.details_scroll = 0
+	m.close_preview()
+	m.services = nil
+	m.service_cursor = 0
+	return m, m.refresh_services()
+}
+
+// selected_name returns the name of the worktree under the cursor, or "".
+func (m Model) selected_name() string {
+	if wt := m.selected_worktree(); wt != nil {
+		return wt.Name
+	}
+	return ""
+}
+
+// handle_list_filter_key edits the search text while the filter is being typed.
+// Up/Down still move through the narrowed list; Enter keeps the filter, Esc clears it.
+func (m Model) handle_list_filter_key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	selected := m.selected_name()
+	switch {
+	case key.Matches(msg, Keys.Escape), key.Matches(msg, Keys.CtrlC):
+		m.list_editing = false
+		m.list_query = ""
+		return m.relist(selected)
+
+	case key.Matches(msg, Keys.Enter):
+		m.list_editing = false
+		return m, nil
+
+	case msg.String() == "up", msg.String() == "down":
+		return m.handle_worktree_key(msg)
+
+	case msg.Type == tea.KeyBackspace:
+		if r := []rune(m.list_query); len(r) > 0 {
+			m.list_query = string(r[:len(r)-1])
+		}
+
+	case msg.String() == "ctrl+u":
+		m.list_query = ""
+
+	case msg.Type == tea.KeySpace:
+		m.list_query += " "
+
+	case msg.Type == tea.KeyRunes:
+		m.list_query += string(msg.Runes)
+
+	default:
+		return m, nil
+	}
+	return m.relist(selected)
+}
+
+// open_list_filter starts typing a search for the worktree list.
+func (m Model) open_list_filter() (tea.Model, tea.Cmd) {
+	m.close_preview()
+	m.focus = PanelWorktrees
+	m.list_editing = true
+	return m, nil
+}
+
+// list_view_actions builds the quick filter, grouping and sort picker.
+func (m Model) list_view_actions() []ui.PickerAction {
+	v := m.list_view
+	state := func(on bool) string {
+		if on {
+			return "on"
+		}
+		return "off"
+	}
+	actions := []ui.PickerAction{
+		{Key: "r", Label: "Running only", Desc: state(v.Running)},
+		{Key: "d", Label: "Docker only", Desc: state(v.Docker)},
+		{Key: "y", Label: "Dirty only", Desc: state(v.Dirty) + ", uncommitted changes"},
+	}
+	if prefixes := m.branch_prefixes(); len(prefixes) > 0 {
+		actions = append(actions, ui.PickerAction{
+			Key: "g", Label: "Group by branch prefix", Desc: state(v.Group) + ", " + strings.Join(prefixes, " "),
+		})
+	}
+	for _, s := range list_sorts {
+		desc := s.desc
+		if s.mode == v.Sort || s.mode == worktree.SortStatus && v.Sort == "" {
+			desc = "current, " + desc
+		}
+		actions = append(actions, ui.PickerAction{Key: s.key, Label: "Sort by " + worktree.SortLabel(s.mode), Desc: desc})
+	}
+	if m.list_filtered() {
+		actions = append(actions, ui.PickerAction{Key: "x", Label: "Clear filters", Desc: "show every worktree"})
+	}
+	return actions
+}
+
+// open_list_view opens the quick filter, grouping and sort picker.
+func (m Model) open_list_view() (tea.Model, tea.Cmd) {
+	return m.open_panel_picker("Worktree list", m.list_view_actions(), pickerListView)
+}
+
+// execute_list_view_action applies a list view choice and saves it to settings.
+// Toggles reopen the picker on the same entry so several can be flipped in a row.
+func (m Model) execute_list_view_action(action ui.PickerAction) (Model, tea.Cmd) {
+	selected := m.selected_name()
+	v := &m.list_view
+	toggle := true
+	switch action.Key {
+	case "r":
+		v.Running = !v.Running
+	case "d":
+		v.Docker = !v.Docker
+	case "y":
+		v.Dirty = !v.Dirty
+	case "g":
+		v.Group = !v.Group
+	case "x":
+		v.Running, v.Docker, v.Dirty = false, false, false
+		m.list_query = ""
+		toggle = false
+	default:
+		toggle = false
+		for _, s := range list_sorts {
+			if action.Key == s.key {
+				v.Sort = s.mode
+			}
+		}
+	}
+
+	s := settings.Load()
+	s.WorktreeList = m.list_view
+	if err := settings.Save(s); err != nil {
+		return m.show_notification("Settings", err.Error())
+	}
+
+	m, relist_cmd := m.relist(selected)
+	if !toggle {
+		return m, relist_cmd
+	}
+	actions := m.list_view_actions()
+	m, picker_cmd := m.open_panel_picker("Worktree list", actions, pickerListView)
+	for i, a := range actions {
+		if a.Key == action.Key {
+			m.picker_cursor = i
+		}
+	}
+	return m, tea.Batch(relist_cmd, picker_cmd)
+}
@@ -0,0 +1,155 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elvisnm/wt/internal/config"
+	"github.com/elvisnm/wt/internal/worktree"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func list_model() Model {
+	m := test_model()
+	m.worktrees = []worktree.Worktree{
+		{Path: "/wt/api", Name: "api", Alias: "api", Branch: "main", Type: worktree.TypeDocker, Running: true, Mem: "200MiB"},
+		{Path: "/wt/feat-login", Name: "feat-login", Alias: "login", Branch: "feat/login", Type: worktree.TypeDocker, Running: true, Mem: "900MiB"},
+		{Path: "/wt/fix-crash", Name: "fix-crash", Alias: "crash", Branch: "fix/crash", Type: worktree.TypeLocal},
+		{Path: "/wt/feat-pay", Name: "feat-pay", Alias: "pay", Branch: "feat/pay", Type: worktree.TypeLocal, Domain: "pay.app.localhost"},
+	}
+	m.cfg = &config.Config{}
+	m.cfg.Repo.BranchPrefixes = []string{"feat/", "fix/"}
+	return m
+}
+
+func row_names(wts []worktree.Worktree) string {
+	out := ""
+	for _, wt := range wts {
+		out += wt.Alias + " "
+	}
+	return out
+}
+
+func type_keys(m Model, keys ...tea.KeyMsg) Model {
+	for _, k := range keys {
+		result, _ := m.Update(k)
+		m = result.(Model)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestWorktreeRowsFilters(t *testing.T) {
+	m := list_model()
+	if got := row_names(m.visible_worktrees()); got != "api login crash pay " {
+		t.Errorf("unfiltered = %q", got)
+	}
+
+	m.list_query = "feat"
+	if got := row_names(m.visible_worktrees()); got != "login pay " {
+		t.Errorf("query feat = %q", got)
+	}
+	m.list_query = "app.local"
+	if got := row_names(m.visible_worktrees()); got != "pay " {
+		t.Errorf("domain query = %q", got)
+	}
+	m.list_query = ""
+
+	m.list_view.Running = true
+	if got := row_names(m.visible_worktrees()); got != "api login " {
+		t.Errorf("running only = %q", got)
+	}
+	m.list_view.Running = false
+
+	m.list_view.Dirty = true
+	if got := row_names(m.visible_worktrees()); got != "api login crash pay " {
+		t.Error("dirty filter should hide nothing before the first git status pass")
+	}
+	result, _ := m.Update(MsgGitStatus{Full: true, Status: map[string]worktree.GitStatus{
+		"/wt/api":        {Fetched: time.Now()},
+		"/wt/feat-login": {Fetched: time.Now()},
+		"/wt/fix-crash":  {Untracked: 1, Fetched: time.Now()},
+		"/wt/feat-pay":   {Fetched: time.Now()},
+	}})
+	m = result.(Model)
+	if got := row_names(m.visible_worktrees()); got != "crash " {
+		t.Errorf("dirty only = %q", got)
+	}
+}
+
+func TestWorktreeRowsSortAndGroup(t *testing.T) {
+	m := list_model()
+	m.list_view.Sort = worktree.SortMemory
+	if got := row_names(m.visible_worktrees()); got != "login api crash pay " {
+		t.Errorf("by memory = %q", got)
+	}
+
+	m.list_view.Sort = worktree.SortName
+	m.list_view.Group = true
+	rows, groups := m.worktree_rows()
+	if got := row_names(rows); got != "login pay crash api " {
+		t.Errorf("grouped = %q", got)
+	}
+	want := []string{"feat/", "feat/", "fix/", ""}
+	for i := range want {
+		if groups[i] != want[i] {
+			t.Errorf("groups = %q, want %q", groups, want)
+			break
+		}
+	}
+	if s := m.list_summary(); s != "grouped · by name" {
+		t.Errorf("summary = %q", s)
+	}
+}
+
+func TestListFilterTyping(t *testing.T) {
+	m := list_model()
+	m.cursor = 2 // crash
+
+	m = type_keys(m, runes("/"))
+	if !m.list_editing {
+		t.Fatal("/ should start the filter")
+	}
+	m = type_keys(m, runes("S"), runes("a"))
+	if m.list_query != "Sa" {
+		t.Errorf("query = %q; typed letters must not trigger shortcuts", m.list_query)
+	}
+	m = type_keys(m, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, runes("cr"))
+	if wt := m.selected_worktree(); wt == nil || wt.Alias != "crash" {
+		t.Errorf("selection should stay on crash while it matches, got %v", wt)
+	}
+
+	m = type_keys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.list_editing || m.list_query != "cr" {
+		t.Errorf("enter should keep the filter: editing=%v query=%q", m.list_editing, m.list_query)
+	}
+
+	m = type_keys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.list_query != "" {
+		t.Errorf("esc should clear the filter, query=%q", m.list_query)
+	}
+	if wt := m.selected_worktree(); wt == nil || wt.Alias != "crash" {
+		t.Errorf("selection lost after clearing the filter: %v", wt)
+	}
+}
+
+func TestSelectionFollowsWorktreeAcrossResort(t *testing.T) {
+	m := list_model()
+	m.list_view.Sort = worktree.SortMemory
+	m.cursor = 1 // api (200MiB, after login)
+
+	result, _ := m.Update(MsgStatsUpdated{Worktrees: []worktree.Worktree{
+		{Path: "/wt/api", Name: "api", Alias: "api", Mem: "2GiB"},
+	}})
+	m = result.(Model)
+	if wt := m.selected_worktree(); wt == nil || wt.Alias != "api" {
+		t.Errorf("cursor should follow api to the top, got %v", wt)
+	}
+	if m.cursor != 0 {
+		t.Errorf("cursor = %d, want 0", m.cursor)
+	}
+}
@@ -0,0 +1,196 @@
+// Package cast reads and writes asciinema v2 recordings (.cast files).
+//
+// A cast file is newline-delimited JSON: a header object on the first line,
+// followed by one [time, "o", data] event array per chunk of output.
+// See https://docs.asciinema.org/manual/asciicast/v2/
+package cast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Ext is the file extension for asciinema recordings.
+const Ext = ".cast"
+
+// Header is the first line of a cast file.
+type Header struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// Event is a single output event: Time is seconds since the recording started.
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// Writer appends events to a cast file.
+type Writer struct {
+	w       io.Writer
+	start   time.Time
+	pending []byte // trailing bytes of an incomplete UTF-8 sequence
+}
+
+// NewWriter writes the header and returns a Writer whose clock starts now.
+func NewWriter(w io.Writer, hdr Header) (*Writer, error) {
+	hdr.Version = 2
+	if hdr.Timestamp == 0 {
+		hdr.Timestamp = time.Now().Unix()
+	}
+	data, err := json.Marshal(hdr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", data); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, start: time.Now()}, nil
+}
+
+// WriteOutput records an output chunk at the given offset from the start.
+// Multi-byte characters split across chunks are held back until complete,
+// since JSON strings must be valid UTF-8.
+func (cw *Writer) WriteOutput(elapsed time.Duration, chunk []byte) error {
+	buf := append(cw.pending, chunk...)
+	cut := utf8_boundary(buf)
+	cw.pending = append([]byte(nil), buf[cut:]...)
+	if cut == 0 {
+		return nil
+	}
+	ev, err := json.Marshal([]any{round_secs(elapsed), "o", string(buf[:cut])})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cw.w, "%s\n", ev)
+	return err
+}
+
+// Record copies r into w as cast events until r is closed.
+// Used by pipe-pane, which feeds the pane's raw output on stdin.
+func Record(r io.Reader, w io.Writer, hdr Header) error {
+	cw, err := NewWriter(w, hdr)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if werr := cw.WriteOutput(time.Since(cw.start), buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Read parses a cast file into its header and output events.
+// Malformed event lines are skipped so a truncated recording still plays.
+func Read(r io.Reader) (Header, []Event, error) {
+	var hdr Header
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return hdr, nil, err
+		}
+		return hdr, nil, fmt.Errorf("empty cast file")
+	}
+	if err := json.Unmarshal(sc.Bytes(), &hdr); err != nil {
+		return hdr, nil, fmt.Errorf("invalid cast header: %w", err)
+	}
+	if hdr.Version != 2 {
+		return hdr, nil, fmt.Errorf("unsupported cast version %d", hdr.Version)
+	}
+
+	var events []Event
+	for sc.Scan() {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(sc.Bytes(), &raw); err != nil || len(raw) != 3 {
+			continue
+		}
+		var ev Event
+		if json.Unmarshal(raw[0], &ev.Time) != nil ||
+			json.Unmarshal(raw[1], &ev.Type) != nil ||
+			json.Unmarshal(raw[2], &ev.Data) != nil {
+			continue
+		}
+		events = append(events, ev)
+	}
+	return hdr, events, sc.Err()
+}
+
+// Recording describes a cast file on disk.
+type Recording struct {
+	Path    string
+	Name    string
+	ModTime time.Time
+	Size    int64
+}
+
+// List returns the cast files in dir, newest first.
+func List(dir string) []Recording {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var recs []Recording
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), Ext) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		recs = append(recs, Recording{
+			Path:    filepath.Join(dir, e.Name()),
+			Name:    strings.TrimSuffix(e.Name(), Ext),
+			ModTime: info.ModTime(),
+			Size:    info.Size(),
+		})
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].ModTime.After(recs[j].ModTime)
+	})
+	return recs
+}
+
+// utf8_boundary returns the length of the longest prefix of b that does not
+// end in the middle of a multi-byte UTF-8 sequence.
+func utf8_boundary(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+		if !utf8.FullRune(b[i:]) {
+			return i
+		}
+		break
+	}
+	return len(b)
+}
+
+// round_secs converts a duration to seconds with microsecond precision.
+func round_secs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1e6
+}
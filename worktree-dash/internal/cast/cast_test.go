@@ -0,0 +1,122 @@
+package cast
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	cw, err := NewWriter(&buf, Header{Width: 80, Height: 24, Title: "Shell — api"})
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	cw.WriteOutput(0, []byte("hello\r\n"))
+	cw.WriteOutput(1500*time.Millisecond, []byte("\x1b[31mred\x1b[0m"))
+
+	hdr, events, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if hdr.Version != 2 || hdr.Width != 80 || hdr.Height != 24 {
+		t.Errorf("header = %+v, want v2 80x24", hdr)
+	}
+	if hdr.Title != "Shell — api" {
+		t.Errorf("Title = %q, want %q", hdr.Title, "Shell — api")
+	}
+	if len(events) != 2 {
+		t.Fatalf("events = %d, want 2", len(events))
+	}
+	if events[1].Time != 1.5 {
+		t.Errorf("events[1].Time = %v, want 1.5", events[1].Time)
+	}
+	if events[1].Type != "o" || events[1].Data != "\x1b[31mred\x1b[0m" {
+		t.Errorf("events[1] = %+v", events[1])
+	}
+}
+
+func TestWriterSplitRune(t *testing.T) {
+	var buf bytes.Buffer
+	cw, _ := NewWriter(&buf, Header{Width: 80, Height: 24})
+
+	// "é" is 0xC3 0xA9 — split it across two chunks
+	cw.WriteOutput(0, []byte{'a', 0xC3})
+	cw.WriteOutput(time.Millisecond, []byte{0xA9, 'b'})
+
+	_, events, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	var got strings.Builder
+	for _, ev := range events {
+		got.WriteString(ev.Data)
+	}
+	if got.String() != "aéb" {
+		t.Errorf("output = %q, want %q", got.String(), "aéb")
+	}
+}
+
+func TestReadSkipsMalformedEvents(t *testing.T) {
+	input := `{"version": 2, "width": 80, "height": 24}
+[0.1, "o", "one"]
+not json
+[0.2, "o"]
+[0.3, "o", "two"]
+`
+	_, events, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("events = %d, want 2", len(events))
+	}
+}
+
+func TestReadRejectsOtherVersions(t *testing.T) {
+	if _, _, err := Read(strings.NewReader(`{"version": 1}`)); err == nil {
+		t.Error("expected error for v1 cast")
+	}
+	if _, _, err := Read(strings.NewReader("")); err == nil {
+		t.Error("expected error for empty input")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Record(strings.NewReader("line one\r\nline two\r\n"), &buf, Header{Width: 100, Height: 30}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	hdr, events, _ := Read(&buf)
+	if hdr.Width != 100 {
+		t.Errorf("Width = %d, want 100", hdr.Width)
+	}
+	if len(events) == 0 || !strings.Contains(events[0].Data, "line one") {
+		t.Errorf("events = %+v, want recorded output", events)
+	}
+}
+
+func TestList(t *testing.T) {
+	tmp := t.TempDir()
+	old := filepath.Join(tmp, "Shell-api-1.cast")
+	recent := filepath.Join(tmp, "Shell-api-2.cast")
+	os.WriteFile(old, []byte("{}"), 0644)
+	os.WriteFile(recent, []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(tmp, "notes.txt"), []byte("x"), 0644)
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(old, past, past)
+
+	recs := List(tmp)
+	if len(recs) != 2 {
+		t.Fatalf("List = %d recordings, want 2", len(recs))
+	}
+	if recs[0].Name != "Shell-api-2" {
+		t.Errorf("recs[0].Name = %q, want newest first", recs[0].Name)
+	}
+	if List(filepath.Join(tmp, "missing")) != nil {
+		t.Error("List of missing dir should be nil")
+	}
+}
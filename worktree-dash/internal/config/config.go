@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -31,6 +32,9 @@ type Config struct {
 	Git        GitConfig         `json:"git"`
 	Paths      PathsConfig      `json:"paths"`
 
+	// Sparse-checkout profiles: name -> directories checked out (cone mode)
+	SparseProfiles map[string][]string `json:"sparseProfiles"`
+
 	// Resolved paths (set by Load, not from JSON)
 	RepoRoot          string `json:"-"`
 	ConfigPath        string `json:"-"`
@@ -44,6 +48,25 @@ type RepoConfig struct {
 	WorktreesDir   string   `json:"worktreesDir"`
 	BranchPrefixes []string `json:"branchPrefixes"`
 	BaseRefs       []string `json:"baseRefs"`
+	SyncStrategy   string   `json:"syncStrategy"` // "rebase" (default) or "merge"
+
+	// Repos a feature often spans; a worktree set has a worktree of the
+	// same branch in each
+	Related []RelatedRepo `json:"related"`
+}
+
+// RelatedRepo is another repo whose worktrees are created, started, stopped
+// and removed together with this repo's.
+type RelatedRepo struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`         // repo root, relative to this repo's root
+	WorktreesDir string `json:"worktreesDir"` // relative to Path
+	Start        string `json:"start"`        // shell command run in the worktree on start, optional
+	Stop         string `json:"stop"`         // shell command run in the worktree on stop, optional
+
+	// Resolved paths (set by Load, not from JSON)
+	PathAbs         string `json:"-"`
+	WorktreesDirAbs string `json:"-"`
 }
 
 type DockerConfig struct {
@@ -171,9 +194,53 @@ func (a *AwsCredsConfig) UnmarshalJSON(data []byte) error {
 }
 
 type DashConfig struct {
-	Commands        map[string]DashCommand `json:"commands"`
-	LocalDevCommand string                 `json:"localDevCommand"`
-	Services        DashServicesConfig     `json:"services"`
+	Commands        map[string]DashCommand      `json:"commands"`
+	LocalDevCommand string                      `json:"localDevCommand"`
+	Services        DashServicesConfig          `json:"services"`
+	Templates       map[string]DashTemplateNode `json:"templates"`
+}
+
+// DashTemplateNode is one node of a workspace template tree.
+// A leaf names a session type ("shell", "claude", "claude-auto", "zsh", "logs")
+// and may be written as a bare string. A split node has Split "h" (side by side)
+// or "v" (stacked) and two children, mirroring terminal.SplitNode.
+type DashTemplateNode struct {
+	Session string            `json:"session"`
+	Split   string            `json:"split"`
+	Left    *DashTemplateNode `json:"left"`
+	Right   *DashTemplateNode `json:"right"`
+}
+
+// UnmarshalJSON accepts either a session type string or a split object.
+func (n *DashTemplateNode) UnmarshalJSON(data []byte) error {
+	var session string
+	if err := json.Unmarshal(data, &session); err == nil {
+		*n = DashTemplateNode{Session: session}
+		return nil
+	}
+	type raw DashTemplateNode
+	var obj raw
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*n = DashTemplateNode(obj)
+	return nil
+}
+
+// IsLeaf returns true if the node names a single session.
+func (n *DashTemplateNode) IsLeaf() bool {
+	return n.Left == nil && n.Right == nil
+}
+
+// Leaves returns the session types in the template, left to right.
+func (n *DashTemplateNode) Leaves() []string {
+	if n == nil {
+		return nil
+	}
+	if n.IsLeaf() {
+		return []string{n.Session}
+	}
+	return append(n.Left.Leaves(), n.Right.Leaves()...)
 }
 
 type DashCommand struct {
@@ -308,6 +375,17 @@ func (c *Config) applyDefaults() {
 		c.Repo.WorktreesDir = fmt.Sprintf("../%s-worktrees", c.Name)
 	}
 
+	// repo.related names and worktreesDirs
+	for i := range c.Repo.Related {
+		r := &c.Repo.Related[i]
+		if r.Name == "" {
+			r.Name = filepath.Base(r.Path)
+		}
+		if r.WorktreesDir == "" {
+			r.WorktreesDir = fmt.Sprintf("../%s-worktrees", r.Name)
+		}
+	}
+
 	// env.prefix
 	if c.Env.Prefix == "" {
 		c.Env.Prefix = strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(c.Name))
@@ -366,6 +444,13 @@ func (c *Config) resolvePaths() {
 	// worktreesDir
 	c.WorktreesDirAbs = resolve_path(c.RepoRoot, c.Repo.WorktreesDir)
 
+	// related repos and their worktrees dirs
+	for i := range c.Repo.Related {
+		r := &c.Repo.Related[i]
+		r.PathAbs = resolve_path(c.RepoRoot, expand_tilde(r.Path))
+		r.WorktreesDirAbs = resolve_path(r.PathAbs, expand_tilde(r.WorktreesDir))
+	}
+
 	// sharedInfra.composePath
 	if c.Docker.SharedInfra.ComposePath != "" {
 		c.ComposePathAbs = resolve_path(c.RepoRoot, expand_tilde(c.Docker.SharedInfra.ComposePath))
@@ -509,6 +594,22 @@ func (c *Config) WorktreeVar(key string) string {
 	return ""
 }
 
+// SparseProfileNames returns the sparse-checkout profile names, sorted.
+func (c *Config) SparseProfileNames() []string {
+	names := make([]string, 0, len(c.SparseProfiles))
+	for name := range c.SparseProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SyncMerges reports whether syncing a worktree with the base ref merges it
+// in rather than rebasing onto it.
+func (c *Config) SyncMerges() bool {
+	return c.Repo.SyncStrategy == "merge"
+}
+
 // ServiceManager returns the effective service manager for local worktrees.
 func (c *Config) ServiceManager() string {
 	return c.Dash.Services.Manager
@@ -3,6 +3,7 @@ package config
 import (
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -113,6 +114,21 @@ func TestLoad(t *testing.T) {
 		t.Error("expected WorktreesDirAbs to be set")
 	}
 
+	// Related repos
+	if len(cfg.Repo.Related) != 1 {
+		t.Fatalf("expected 1 related repo, got %d", len(cfg.Repo.Related))
+	}
+	lib := cfg.Repo.Related[0]
+	if lib.Name != "myapp-lib" || lib.PathAbs != filepath.Join(dir, "../myapp-lib") ||
+		lib.WorktreesDirAbs != filepath.Join(dir, "../myapp-lib-worktrees") || lib.Start != "npm run watch" {
+		t.Errorf("related repo: %+v", lib)
+	}
+
+	// Sparse profiles
+	if names := cfg.SparseProfileNames(); strings.Join(names, ",") != "api,web" || len(cfg.SparseProfiles["api"]) != 2 {
+		t.Errorf("sparse profiles: %v %v", names, cfg.SparseProfiles)
+	}
+
 	// Minimal services
 	minimal := cfg.ServicesForMode("minimal")
 	if minimal == nil || len(minimal) != 2 {
@@ -130,4 +146,16 @@ func TestLoad(t *testing.T) {
 	if ports["api"] != 3501 {
 		t.Errorf("expected api port with offset 500 = 3501, got %d", ports["api"])
 	}
+
+	// Workspace templates: string leaves and nested splits
+	dev, ok := cfg.Dash.Templates["dev"]
+	if !ok {
+		t.Fatal("expected 'dev' template")
+	}
+	if dev.Split != "h" || dev.Right == nil || dev.Right.Split != "v" {
+		t.Errorf("unexpected dev template shape: %+v", dev)
+	}
+	if got := strings.Join(dev.Leaves(), ","); got != "claude,logs,shell" {
+		t.Errorf("dev.Leaves() = %q, want %q", got, "claude,logs,shell")
+	}
 }
@@ -6,6 +6,14 @@ module.exports = {
     worktreesDir: '../myapp-worktrees',
     branchPrefixes: ['feat', 'fix', 'hotfix'],
     baseRefs: ['origin/main'],
+    related: [
+      { path: '../myapp-lib', start: 'npm run watch' },
+    ],
+  },
+
+  sparseProfiles: {
+    web: ['apps/web', 'packages/shared'],
+    api: ['apps/api', 'packages/shared'],
   },
 
   docker: {
@@ -82,5 +90,12 @@ module.exports = {
       shell: { label: 'Shell', cmd: 'bash' },
     },
     localDevCommand: 'pnpm dev',
+    templates: {
+      dev: {
+        split: 'h',
+        left: 'claude',
+        right: { split: 'v', left: 'logs', right: 'shell' },
+      },
+    },
   },
 };
@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"strings"
+
+	"github.com/elvisnm/wt/internal/cmdutil"
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+// FetchVolumeSizes returns the size in bytes of every local volume, by
+// name, from `docker system df -v`. Returns nil when docker isn't available.
+func FetchVolumeSizes() map[string]int64 {
+	raw, err := cmdutil.RunCmd("docker", "system", "df", "-v")
+	if err != nil {
+		return nil
+	}
+	return ParseVolumeSizes(raw)
+}
+
+// ParseVolumeSizes reads the "Local Volumes space usage" table of
+// `docker system df -v`: VOLUME NAME, LINKS, SIZE columns up to a blank line.
+func ParseVolumeSizes(out string) map[string]int64 {
+	sizes := make(map[string]int64)
+	in_table := false
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "VOLUME NAME") {
+			in_table = true
+			continue
+		}
+		if !in_table {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			break
+		}
+		if len(fields) < 3 {
+			continue
+		}
+		sizes[fields[0]] = int64(worktree.MemBytes(fields[len(fields)-1]))
+	}
+	return sizes
+}
+
+// VolumeUsage counts a worktree's volumes and their total size. Compose
+// names them "<prefix>_<volume>", where prefix is config.VolumePrefix.
+func VolumeUsage(sizes map[string]int64, prefix string) (count int, bytes int64) {
+	for name, size := range sizes {
+		if strings.HasPrefix(name, prefix+"_") {
+			count++
+			bytes += size
+		}
+	}
+	return count, bytes
+}
@@ -0,0 +1,35 @@
+package docker
+
+import "testing"
+
+func TestParseVolumeSizes(t *testing.T) {
+	out := `Images space usage:
+
+REPOSITORY   TAG       IMAGE ID       CREATED       SIZE      SHARED SIZE   UNIQUE SIZE   CONTAINERS
+node         20        1a2b3c4d5e6f   2 weeks ago   1.1GB     0B            1.1GB         3
+
+Local Volumes space usage:
+
+VOLUME NAME                 LINKS     SIZE
+myapp_feat-login_mongo      1         512MB
+myapp_feat-login_node       1         1.5GB
+myapp_fix-nav_mongo         0         0B
+
+Build cache usage: 0B
+`
+	sizes := ParseVolumeSizes(out)
+	if len(sizes) != 3 || sizes["myapp_feat-login_node"] != 1.5e9 || sizes["myapp_fix-nav_mongo"] != 0 {
+		t.Fatalf("sizes = %v", sizes)
+	}
+
+	count, bytes := VolumeUsage(sizes, "myapp_feat-login")
+	if count != 2 || bytes != 2.012e9 {
+		t.Errorf("VolumeUsage = %d, %d", count, bytes)
+	}
+	if count, _ := VolumeUsage(sizes, "myapp_feat"); count != 0 {
+		t.Error("a prefix of another worktree's alias shouldn't match")
+	}
+	if count, _ := VolumeUsage(nil, "myapp_x"); count != 0 {
+		t.Error("no sizes should count nothing")
+	}
+}
@@ -32,6 +32,10 @@ const (
 	Actions     = "Actions"
 	Pull        = "Pull"
 	Remove      = "Remove"
+	Replay      = "Replay"
+	Diff        = "Diff"
+	Commits     = "Commits"
+	Checkpoints = "Checkpoints"
 )
 
 // Tab formats a label with an alias suffix: "Prefix — alias".
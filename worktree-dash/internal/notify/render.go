@@ -4,15 +4,20 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/elvisnm/wt/internal/theme"
 )
 
-// ANSI color codes — consistent with panes.go and pick.go
-const (
+// ANSI color codes, set from the current theme by apply_theme
+var (
 	ansiDim    = "\033[38;5;240m" // grey for borders
 	ansiBold   = "\033[1;37m"     // bold white for title
 	ansiOrange = "\033[38;5;214m" // orange for active notifications
 	ansiGreen  = "\033[1;32m"     // bold green for success
-	ansiReset  = "\033[0m"
+)
+
+const (
+	ansiReset = "\033[0m"
 
 	// Cursor control
 	ansiClearScreen = "\033[2J\033[H"
@@ -20,6 +25,15 @@ const (
 	ansiShowCursor  = "\033[?25h"
 )
 
+// apply_theme sets the renderer's colors from the current theme.
+func apply_theme() {
+	t := theme.Current()
+	ansiDim = theme.FG(t.Border)
+	ansiBold = theme.Bold(t.Text)
+	ansiOrange = theme.FG(t.Hint)
+	ansiGreen = theme.Bold(t.Running)
+}
+
 var ansiPattern = regexp.MustCompile(`\033\[[0-9;]*m`)
 
 // visual_len returns the display width of a string, stripping ANSI escapes.
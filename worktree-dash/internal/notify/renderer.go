@@ -29,6 +29,7 @@ type renderer struct {
 // the pane via tmux. This eliminates flicker because content is already in
 // the terminal buffer when new rows become visible.
 func Run(fifo_path, socket string) error {
+	apply_theme()
 	if err := create_fifo(fifo_path); err != nil {
 		return fmt.Errorf("failed to create FIFO %s: %w", fifo_path, err)
 	}
@@ -0,0 +1,131 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// KeyList is the keys bound to one action. In settings.json it may be written
+// as a single key ("Q") or a list (["up", "i"]).
+type KeyList []string
+
+func (k *KeyList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*k = KeyList{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("keybinding must be a key or a list of keys")
+	}
+	*k = many
+	return nil
+}
+
+// KeyDef is the default binding of a remappable action. Actions conflict when
+// they share a key and at least one scope (a set of keys handled together).
+// Fixed defs can't be remapped; they only reserve their keys.
+type KeyDef struct {
+	ID     string
+	Keys   []string
+	Scopes []string
+	Fixed  bool
+}
+
+// ResolveKeys applies user overrides to defaults and returns the effective keys
+// per action ID, plus a description of every override that was rejected.
+// Conflicting overrides fall back to their defaults, so swapping two keys works
+// but binding two actions to one key keeps both on their default keys.
+func ResolveKeys(defs []KeyDef, overrides map[string]KeyList) (map[string][]string, []string) {
+	var problems []string
+	known := make(map[string]KeyDef, len(defs))
+	for _, d := range defs {
+		known[d.ID] = d
+	}
+
+	effective := make(map[string][]string, len(defs))
+	for _, d := range defs {
+		effective[d.ID] = d.Keys
+	}
+
+	ids := make([]string, 0, len(overrides))
+	for id := range overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	overridden := map[string]bool{}
+	for _, id := range ids {
+		keys := overrides[id]
+		if d, ok := known[id]; !ok || d.Fixed {
+			problems = append(problems, fmt.Sprintf("unknown action %q", id))
+			continue
+		}
+		var clean []string
+		for _, k := range keys {
+			if k == " " || strings.EqualFold(strings.TrimSpace(k), "space") {
+				k = " " // bubbletea reports the space bar as " "
+			} else {
+				k = strings.TrimSpace(k)
+			}
+			if k != "" {
+				clean = append(clean, k)
+			}
+		}
+		if len(clean) == 0 {
+			problems = append(problems, fmt.Sprintf("%s: no keys", id))
+			continue
+		}
+		effective[id] = clean
+		overridden[id] = true
+	}
+
+	// Revert conflicting overrides until the bindings are consistent. Defaults
+	// never conflict with each other, so this always terminates.
+	for {
+		a, b, k, found := find_conflict(defs, effective)
+		if !found {
+			break
+		}
+		reverted := false
+		for _, id := range []string{a, b} {
+			if overridden[id] {
+				problems = append(problems, fmt.Sprintf("%s: %q conflicts with %s", id, k, other(id, a, b)))
+				effective[id] = known[id].Keys
+				overridden[id] = false
+				reverted = true
+			}
+		}
+		if !reverted {
+			break // conflicting defaults; nothing a user setting can fix
+		}
+	}
+	return effective, problems
+}
+
+// find_conflict returns the first two actions sharing a key within a scope.
+func find_conflict(defs []KeyDef, effective map[string][]string) (string, string, string, bool) {
+	owner := map[string]string{} // "scope\x00key" → action ID
+	for _, d := range defs {
+		for _, scope := range d.Scopes {
+			for _, k := range effective[d.ID] {
+				slot := scope + "\x00" + k
+				if prev, ok := owner[slot]; ok && prev != d.ID {
+					return prev, d.ID, k, true
+				}
+				owner[slot] = d.ID
+			}
+		}
+	}
+	return "", "", "", false
+}
+
+func other(id, a, b string) string {
+	if id == a {
+		return b
+	}
+	return a
+}
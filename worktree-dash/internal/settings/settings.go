@@ -15,6 +15,17 @@ const (
 	DefaultMaxPanesPerGroup = 4
 	MinMaxPanesPerGroup     = 2
 	MaxMaxPanesPerGroup     = 6
+
+	DefaultStaleDays = 30
+	MinStaleDays     = 1
+	MaxStaleDays     = 365
+)
+
+// Exit policies: what happens to a tab when its command exits.
+const (
+	ExitKeep           = "keep"             // leave the tab open with its exit summary
+	ExitClose          = "close"            // close it as soon as the command exits
+	ExitCloseOnSuccess = "close_on_success" // close on exit code 0, keep it on failure
 )
 
 // Settings holds user preferences that persist across dashboard sessions.
@@ -30,6 +41,41 @@ type Settings struct {
 
 	// ClaudeAutoMode: when true, claude always opens with --enable-auto-mode
 	ClaudeAutoMode bool `json:"claude_auto_mode"`
+
+	// ClaudeCheckpoint: when true, opening claude checkpoints the worktree first
+	ClaudeCheckpoint bool `json:"claude_checkpoint"`
+
+	// ExitPolicies maps a tab's label type ("Logs", "Dev", "Build", ...) to an
+	// exit policy. Types without an entry keep the tab.
+	ExitPolicies map[string]string `json:"exit_policies"`
+
+	// Keybindings remaps actions by ID ("quit", "worktree.shell", "tmux.prefix", ...).
+	// Conflicts are detected when the bindings are applied.
+	Keybindings map[string]KeyList `json:"keybindings,omitempty"`
+
+	// Theme names the color theme: "dark" (default), "light" or "high-contrast".
+	Theme string `json:"theme,omitempty"`
+
+	// Colors overrides single theme colors by name ("border", "hint", ...),
+	// as ANSI 256 indexes or #rrggbb.
+	Colors map[string]string `json:"colors,omitempty"`
+
+	// WorktreeList holds the worktree panel's sort mode, grouping and quick
+	// filters, saved whenever they are changed from the dashboard.
+	WorktreeList WorktreeList `json:"worktree_list"`
+
+	// StaleDays is how long a worktree can go without a commit or a start
+	// before the cleanup view lists it as stale (1-365).
+	StaleDays int `json:"stale_days"`
+}
+
+// WorktreeList controls how the worktree panel is ordered and filtered.
+type WorktreeList struct {
+	Sort    string `json:"sort,omitempty"` // status (default), name, last_used, memory, created
+	Group   bool   `json:"group"`          // group by repo.branchPrefixes
+	Running bool   `json:"running"`        // only running worktrees
+	Docker  bool   `json:"docker"`         // only docker worktrees
+	Dirty   bool   `json:"dirty"`          // only worktrees with uncommitted changes
 }
 
 // PanelDefaults controls which optional panels open by default.
@@ -44,6 +90,14 @@ func Defaults() Settings {
 	return Settings{
 		LeftPanePct:      DefaultLeftPanePct,
 		MaxPanesPerGroup: DefaultMaxPanesPerGroup,
+		StaleDays:        DefaultStaleDays,
+		ExitPolicies: map[string]string{
+			"Logs":        ExitClose,
+			"Replay":      ExitClose,
+			"Diff":        ExitClose,
+			"Commits":     ExitClose,
+			"Checkpoints": ExitClose,
+		},
 	}
 }
 
@@ -55,6 +109,14 @@ func (s *Settings) clamp() {
 	if s.MaxPanesPerGroup < MinMaxPanesPerGroup || s.MaxPanesPerGroup > MaxMaxPanesPerGroup {
 		s.MaxPanesPerGroup = DefaultMaxPanesPerGroup
 	}
+	if s.StaleDays < MinStaleDays || s.StaleDays > MaxStaleDays {
+		s.StaleDays = DefaultStaleDays
+	}
+	for label_type, p := range s.ExitPolicies {
+		if p != ExitKeep && p != ExitClose && p != ExitCloseOnSuccess {
+			delete(s.ExitPolicies, label_type)
+		}
+	}
 }
 
 // ── Persistence ─────────────────────────────────────────────────────────
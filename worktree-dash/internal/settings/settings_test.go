@@ -137,6 +137,16 @@ func TestClampSplitPaneLimits(t *testing.T) {
 	}
 }
 
+func TestStaleDaysClamp(t *testing.T) {
+	for input, want := range map[int]int{0: DefaultStaleDays, 1: 1, 14: 14, 365: 365, 400: DefaultStaleDays, -3: DefaultStaleDays} {
+		s := Settings{LeftPanePct: 20, MaxPanesPerGroup: 4, StaleDays: input}
+		s.clamp()
+		if s.StaleDays != want {
+			t.Errorf("StaleDays %d = %d, want %d", input, s.StaleDays, want)
+		}
+	}
+}
+
 func TestClaudeAutoMode_Default(t *testing.T) {
 	s := Defaults()
 	if s.ClaudeAutoMode {
@@ -163,3 +173,94 @@ func TestClaudeAutoMode_JSON(t *testing.T) {
 		t.Error("ClaudeAutoMode should be true from JSON")
 	}
 }
+
+func TestExitPolicies(t *testing.T) {
+	tmp := t.TempDir()
+	settings_dir = tmp
+
+	os.WriteFile(filepath.Join(tmp, "settings.json"), []byte(`{"exit_policies": {"Build": "close_on_success", "Logs": "keep", "Dev": "bogus"}}`), 0644)
+
+	s := Load()
+	tests := []struct {
+		label_type string
+		want       string
+	}{
+		{"Build", ExitCloseOnSuccess},
+		{"Logs", ExitKeep},    // overrides the default
+		{"Replay", ExitClose}, // default kept alongside user entries
+		{"Dev", ""},           // invalid value dropped
+		{"Shell", ""},         // no entry
+	}
+	for _, tt := range tests {
+		if got := s.ExitPolicies[tt.label_type]; got != tt.want {
+			t.Errorf("ExitPolicies[%q] = %q, want %q", tt.label_type, got, tt.want)
+		}
+	}
+}
+
+func TestKeyListUnmarshal(t *testing.T) {
+	var s Settings
+	data := `{"keybindings": {"quit": "Q", "up": ["up", "i"]}}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := s.Keybindings["quit"]; len(got) != 1 || got[0] != "Q" {
+		t.Errorf("quit = %v, want [Q]", got)
+	}
+	if got := s.Keybindings["up"]; len(got) != 2 || got[1] != "i" {
+		t.Errorf("up = %v, want [up i]", got)
+	}
+}
+
+func TestResolveKeys(t *testing.T) {
+	defs := []KeyDef{
+		{ID: "quit", Keys: []string{"q"}, Scopes: []string{"global"}},
+		{ID: "help", Keys: []string{"?"}, Scopes: []string{"global"}},
+		{ID: "shell", Keys: []string{"b"}, Scopes: []string{"worktree"}},
+		{ID: "tab jumps", Keys: []string{"1"}, Scopes: []string{"global"}, Fixed: true},
+	}
+
+	// Swapping two keys is fine
+	eff, problems := ResolveKeys(defs, map[string]KeyList{"quit": {"?"}, "help": {"q"}})
+	if len(problems) != 0 || eff["quit"][0] != "?" || eff["help"][0] != "q" {
+		t.Errorf("swap: eff=%v problems=%v", eff, problems)
+	}
+
+	// Same key in different scopes doesn't conflict
+	eff, problems = ResolveKeys(defs, map[string]KeyList{"shell": {"q"}})
+	if len(problems) != 0 || eff["shell"][0] != "q" {
+		t.Errorf("cross-scope: eff=%v problems=%v", eff, problems)
+	}
+
+	// A conflicting override falls back to its default
+	eff, problems = ResolveKeys(defs, map[string]KeyList{"quit": {"?"}})
+	if len(problems) != 1 || eff["quit"][0] != "q" {
+		t.Errorf("conflict: eff=%v problems=%v", eff, problems)
+	}
+
+	// Fixed keys are reserved, and can't be remapped themselves
+	eff, problems = ResolveKeys(defs, map[string]KeyList{"help": {"1"}, "tab jumps": {"x"}, "nope": {"z"}})
+	if len(problems) != 3 || eff["help"][0] != "?" || eff["tab jumps"][0] != "1" {
+		t.Errorf("fixed/unknown: eff=%v problems=%v", eff, problems)
+	}
+
+	// "space" names the space bar, which bubbletea reports as " "
+	eff, problems = ResolveKeys(defs, map[string]KeyList{"shell": {"space"}})
+	if len(problems) != 0 || eff["shell"][0] != " " {
+		t.Errorf("space: eff=%q problems=%v", eff["shell"], problems)
+	}
+}
+
+func TestThemeJSON(t *testing.T) {
+	var s Settings
+	data := `{"theme": "light", "colors": {"border": "244", "hint": "#ff8700"}}`
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if s.Theme != "light" {
+		t.Errorf("Theme = %q, want light", s.Theme)
+	}
+	if s.Colors["border"] != "244" || s.Colors["hint"] != "#ff8700" {
+		t.Errorf("Colors = %v", s.Colors)
+	}
+}
@@ -0,0 +1,212 @@
+// Package state keeps per-repo dashboard state that isn't configuration:
+// pins, notes, last starts, pushed upstreams and sync conflicts. It lives
+// in ~/.wt/state/, one file per repo, keyed by worktree name (the
+// directory name) so entries survive alias changes.
+package state
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// State is one repo's saved dashboard state.
+type State struct {
+	Pins  []string          `json:"pins,omitempty"`  // pinned worktree names, in the order pinned
+	Notes map[string]string `json:"notes,omitempty"` // worktree name → note
+
+	Started map[string]time.Time `json:"started,omitempty"` // worktree name → last start from the dashboard
+	Pushed  map[string]string    `json:"pushed,omitempty"`  // worktree name → upstream seen on the remote
+
+	Conflicts map[string]Conflict `json:"conflicts,omitempty"` // worktree name → last sync that hit conflicts
+}
+
+// Conflict is a sync with the base ref that stopped on conflicts and was
+// aborted, leaving the branch as it was.
+type Conflict struct {
+	Base  string    `json:"base"`            // the ref the branch was synced with
+	Files []string  `json:"files,omitempty"` // files that conflicted
+	At    time.Time `json:"at"`
+}
+
+// Path returns the state file for a repo: ~/.wt/state/<repo>-<hash>.json.
+// The hash of the full path keeps repos with the same directory name apart.
+func Path(repo_root string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.TempDir()
+	}
+	sum := sha1.Sum([]byte(filepath.Clean(repo_root)))
+	name := filepath.Base(repo_root) + "-" + hex.EncodeToString(sum[:4]) + ".json"
+	return filepath.Join(home, ".wt", "state", name)
+}
+
+// Load reads a repo's state. Returns an empty state if the file doesn't
+// exist or is malformed.
+func Load(repo_root string) State {
+	var s State
+	data, err := os.ReadFile(Path(repo_root))
+	if err != nil {
+		return State{}
+	}
+	if json.Unmarshal(data, &s) != nil {
+		return State{}
+	}
+	return s
+}
+
+// Save writes a repo's state atomically.
+func Save(repo_root string, s State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	path := Path(repo_root)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Pinned reports whether the worktree is pinned.
+func (s State) Pinned(name string) bool {
+	for _, p := range s.Pins {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TogglePin pins or unpins a worktree and reports whether it is now pinned.
+func (s *State) TogglePin(name string) bool {
+	for i, p := range s.Pins {
+		if p == name {
+			s.Pins = append(s.Pins[:i:i], s.Pins[i+1:]...)
+			return false
+		}
+	}
+	s.Pins = append(s.Pins, name)
+	return true
+}
+
+// Note returns the worktree's note, or "".
+func (s State) Note(name string) string {
+	return s.Notes[name]
+}
+
+// SetNote sets a worktree's note. An empty note removes it.
+func (s *State) SetNote(name, note string) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		delete(s.Notes, name)
+		return
+	}
+	if s.Notes == nil {
+		s.Notes = map[string]string{}
+	}
+	s.Notes[name] = note
+}
+
+// LastStarted returns when the worktree was last started from the
+// dashboard, or the zero time.
+func (s State) LastStarted(name string) time.Time {
+	return s.Started[name]
+}
+
+// SetStarted records that the worktree was started at t.
+func (s *State) SetStarted(name string, t time.Time) {
+	if s.Started == nil {
+		s.Started = map[string]time.Time{}
+	}
+	s.Started[name] = t
+}
+
+// PushedTo returns the upstream the worktree's branch was last seen on the
+// remote as, or "" if it never was.
+func (s State) PushedTo(name string) string {
+	return s.Pushed[name]
+}
+
+// SetPushed records that the worktree's branch was seen on the remote as
+// upstream, and reports whether that's new.
+func (s *State) SetPushed(name, upstream string) bool {
+	if s.Pushed[name] == upstream {
+		return false
+	}
+	if s.Pushed == nil {
+		s.Pushed = map[string]string{}
+	}
+	s.Pushed[name] = upstream
+	return true
+}
+
+// ForgetPushed drops the worktree's pushed upstream and reports whether it
+// had one.
+func (s *State) ForgetPushed(name string) bool {
+	if _, ok := s.Pushed[name]; !ok {
+		return false
+	}
+	delete(s.Pushed, name)
+	return true
+}
+
+// Conflict returns the worktree's unresolved sync conflict, if any.
+func (s State) Conflict(name string) (Conflict, bool) {
+	c, ok := s.Conflicts[name]
+	return c, ok
+}
+
+// SetConflict records that syncing the worktree hit conflicts.
+func (s *State) SetConflict(name string, c Conflict) {
+	if s.Conflicts == nil {
+		s.Conflicts = map[string]Conflict{}
+	}
+	s.Conflicts[name] = c
+}
+
+// ClearConflict forgets the worktree's sync conflict and reports whether
+// there was one.
+func (s *State) ClearConflict(name string) bool {
+	if _, ok := s.Conflicts[name]; !ok {
+		return false
+	}
+	delete(s.Conflicts, name)
+	return true
+}
+
+// Rename moves a worktree's pin, note, start time, pushed upstream and sync
+// conflict to a new name.
+func (s *State) Rename(old_name, new_name string) {
+	for i, p := range s.Pins {
+		if p == old_name {
+			s.Pins[i] = new_name
+		}
+	}
+	if note, ok := s.Notes[old_name]; ok {
+		delete(s.Notes, old_name)
+		s.Notes[new_name] = note
+	}
+	if t, ok := s.Started[old_name]; ok {
+		delete(s.Started, old_name)
+		s.Started[new_name] = t
+	}
+	if u, ok := s.Pushed[old_name]; ok {
+		delete(s.Pushed, old_name)
+		s.Pushed[new_name] = u
+	}
+	if c, ok := s.Conflicts[old_name]; ok {
+		delete(s.Conflicts, old_name)
+		s.Conflicts[new_name] = c
+	}
+}
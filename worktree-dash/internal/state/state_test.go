@@ -0,0 +1,121 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPathPerRepo(t *testing.T) {
+	a, b := Path("/code/a/app"), Path("/code/b/app")
+	if a == b {
+		t.Errorf("repos with the same directory name share %s", a)
+	}
+	if filepath.Base(filepath.Dir(a)) != "state" || filepath.Ext(a) != ".json" {
+		t.Errorf("Path = %s, want ~/.wt/state/<repo>.json", a)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if s := Load("/code/app"); len(s.Pins) != 0 || len(s.Notes) != 0 {
+		t.Fatalf("missing file should load empty, got %+v", s)
+	}
+
+	var s State
+	s.TogglePin("feat-login")
+	s.SetNote("fix-crash", "  repro for ticket 123 ")
+	if err := Save("/code/app", s); err != nil {
+		t.Fatal(err)
+	}
+
+	got := Load("/code/app")
+	if !got.Pinned("feat-login") || got.Note("fix-crash") != "repro for ticket 123" {
+		t.Errorf("round trip = %+v", got)
+	}
+	if other := Load("/code/other"); other.Pinned("feat-login") {
+		t.Error("state leaked into another repo")
+	}
+}
+
+func TestPinsAndNotes(t *testing.T) {
+	var s State
+	if !s.TogglePin("a") || !s.TogglePin("b") || s.TogglePin("a") {
+		t.Fatal("TogglePin should report the new state")
+	}
+	if s.Pinned("a") || !s.Pinned("b") {
+		t.Errorf("pins = %v", s.Pins)
+	}
+
+	s.SetNote("b", "waiting on design review")
+	s.Rename("b", "b2")
+	if !s.Pinned("b2") || s.Pinned("b") || s.Note("b2") != "waiting on design review" || s.Note("b") != "" {
+		t.Errorf("rename: %+v", s)
+	}
+
+	s.SetNote("b2", " ")
+	if _, ok := s.Notes["b2"]; ok {
+		t.Error("an empty note should be removed")
+	}
+}
+
+func TestStarted(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var s State
+	if !s.LastStarted("a").IsZero() {
+		t.Fatal("a worktree never started should have no start time")
+	}
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	s.SetStarted("a", at)
+	s.Rename("a", "a2")
+	if !s.LastStarted("a").IsZero() || !s.LastStarted("a2").Equal(at) {
+		t.Errorf("rename: %+v", s.Started)
+	}
+
+	if err := Save("/code/app", s); err != nil {
+		t.Fatal(err)
+	}
+	if got := Load("/code/app"); !got.LastStarted("a2").Equal(at) {
+		t.Errorf("round trip = %+v", got.Started)
+	}
+}
+
+func TestPushed(t *testing.T) {
+	var s State
+	if s.PushedTo("a") != "" || s.ForgetPushed("a") {
+		t.Fatal("a branch never seen pushed has no upstream")
+	}
+	if !s.SetPushed("a", "origin/feat/a") || s.SetPushed("a", "origin/feat/a") {
+		t.Error("SetPushed should only report a new upstream")
+	}
+	s.Rename("a", "a2")
+	if s.PushedTo("a") != "" || s.PushedTo("a2") != "origin/feat/a" {
+		t.Errorf("rename: %+v", s.Pushed)
+	}
+	if !s.ForgetPushed("a2") || s.PushedTo("a2") != "" {
+		t.Errorf("forget: %+v", s.Pushed)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var s State
+	if _, ok := s.Conflict("a"); ok || s.ClearConflict("a") {
+		t.Fatal("no conflict recorded yet")
+	}
+	s.SetConflict("a", Conflict{Base: "origin/main", Files: []string{"go.mod"}, At: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)})
+	s.Rename("a", "a2")
+	if err := Save("/code/app", s); err != nil {
+		t.Fatal(err)
+	}
+	got := Load("/code/app")
+	if c, ok := got.Conflict("a2"); !ok || c.Base != "origin/main" || len(c.Files) != 1 {
+		t.Fatalf("round trip: %+v", got.Conflicts)
+	}
+	if !got.ClearConflict("a2") || len(got.Conflicts) != 0 {
+		t.Errorf("clear: %+v", got.Conflicts)
+	}
+}
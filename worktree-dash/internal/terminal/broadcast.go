@@ -0,0 +1,50 @@
+package terminal
+
+import "fmt"
+
+// ToggleSync flips tmux synchronize-panes for every pane in the group.
+// The option is set per pane (tmux 3.2+), so the dashboard pane sharing the
+// viewport window never receives broadcast input. Returns the new state.
+func (mgr *Manager) ToggleSync(group_id int) (bool, error) {
+	mgr.mu.Lock()
+	var g *TabGroup
+	for _, grp := range mgr.groups {
+		if grp.ID == group_id {
+			g = grp
+			break
+		}
+	}
+	if g == nil {
+		mgr.mu.Unlock()
+		return false, fmt.Errorf("group %d not found", group_id)
+	}
+	if !g.IsSplit() {
+		mgr.mu.Unlock()
+		return false, fmt.Errorf("broadcast needs a split group")
+	}
+	on := !g.synced
+	sessions := append([]*Session(nil), g.sessions...)
+	g.synced = on
+	mgr.mu.Unlock()
+
+	for _, s := range sessions {
+		s.set_sync(on)
+	}
+	return on, nil
+}
+
+// set_sync sets synchronize-panes on this session's pane.
+func (s *Session) set_sync(on bool) {
+	value := "off"
+	if on {
+		value = "on"
+	}
+	s.server.Run("set-option", "-p", "-t", s.pane_target(), "synchronize-panes", value)
+}
+
+// SendLine types text into the pane literally and presses Enter.
+func (s *Session) SendLine(text string) {
+	target := s.pane_target()
+	s.server.Run("send-keys", "-t", target, "-l", text)
+	s.server.Run("send-keys", "-t", target, "Enter")
+}
@@ -1,5 +1,7 @@
 package terminal
 
+import "github.com/elvisnm/wt/internal/theme"
+
 // MaxGroupPanes is the absolute ceiling for panes per group.
 // The runtime limit comes from settings (2-6).
 const MaxGroupPanes = 6
@@ -95,6 +97,7 @@ type TabGroup struct {
 	ID       int
 	sessions []*Session
 	tree     *SplitNode
+	synced   bool // synchronize-panes is on for every pane in the group
 }
 
 // NewTabGroup creates a group with a single session.
@@ -240,6 +243,11 @@ func (g *TabGroup) Contains(session_id int) bool {
 	return false
 }
 
+// Synced returns true if input typed into one pane is broadcast to all panes.
+func (g *TabGroup) Synced() bool {
+	return g.synced
+}
+
 // Label returns the display label for the group (primary session's label).
 func (g *TabGroup) Label() string {
 	if p := g.Primary(); p != nil {
@@ -280,12 +288,13 @@ func (g *TabGroup) LayoutMapHighlighted(highlight_id int) []string {
 	return render_dot_map(g.tree, highlight_id)
 }
 
-// ANSI color codes for dot map
-const (
-	dotOrange = "\033[38;5;214m"
-	dotDim    = "\033[38;5;240m"
-	dotReset  = "\033[0m"
-)
+// dotReset ends a dot's color. Dot colors come from the current theme.
+const dotReset = "\033[0m"
+
+// dot_highlight returns the ANSI color of the highlighted dot.
+func dot_highlight() string {
+	return theme.FG(theme.Current().Hint)
+}
 
 // render_dot_map produces a compact dot grid from the split tree.
 // Uses position-based layout: walks the tree recursively, assigning each leaf
@@ -350,11 +359,12 @@ func render_dot_map(root *SplitNode, highlight_id int) []string {
 	}
 
 	// Render dot grid
+	dim := theme.FG(theme.Current().Border)
 	dot := func(sid int) string {
 		if highlight_id >= 0 && sid == highlight_id {
-			return dotOrange + "\u25cf" + dotReset
+			return dot_highlight() + "\u25cf" + dotReset
 		}
-		return dotDim + "\u25cf" + dotReset
+		return dim + "\u25cf" + dotReset
 	}
 
 	var lines []string
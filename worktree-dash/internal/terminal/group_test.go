@@ -294,14 +294,14 @@ func TestDotMapHighlight(t *testing.T) {
 	if len(lines) != 2 {
 		t.Fatalf("want 2 lines, got %d", len(lines))
 	}
-	if !strings.Contains(lines[0], dotOrange) {
+	if !strings.Contains(lines[0], dot_highlight()) {
 		t.Error("line 0 should have orange highlight for session 1")
 	}
 	t.Logf("Highlighted(1):\n%s", strings.Join(lines, "\n"))
 
 	// Highlight session 3 — appears only in row 1
 	lines = g.LayoutMapHighlighted(3)
-	if !strings.Contains(lines[1], dotOrange) {
+	if !strings.Contains(lines[1], dot_highlight()) {
 		t.Error("line 1 should have orange highlight for session 3")
 	}
 	t.Logf("Highlighted(3):\n%s", strings.Join(lines, "\n"))
@@ -650,3 +650,26 @@ func TestCountVLeaves(t *testing.T) {
 		t.Errorf("H(V(1,2), 3): got %d, want 1", v)
 	}
 }
+
+func TestRelabelWorktree(t *testing.T) {
+	shell := mock_session(1, "Shell — api")
+	shell.SetWorktree("api", "/wt/feat-api")
+	second := mock_session(2, "Shell — api #2")
+	other := mock_session(3, "Shell — api-v2")
+	logs := mock_session(4, "Logs — api")
+	g := NewTabGroup(1, shell)
+	g.Add(second, 1, SplitH)
+	mgr := &Manager{groups: []*TabGroup{g, NewTabGroup(2, other), NewTabGroup(3, logs)}}
+
+	if n := mgr.RelabelWorktree("api", "billing", "/wt/feat-billing"); n != 3 {
+		t.Errorf("relabeled %d sessions, want 3", n)
+	}
+	got := []string{shell.Label, second.Label, other.Label, logs.Label}
+	want := []string{"Shell — billing", "Shell — billing #2", "Shell — api-v2", "Logs — billing"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+	if shell.WorktreeAlias != "billing" || shell.WorktreeDir != "/wt/feat-billing" {
+		t.Errorf("worktree context: %q %q", shell.WorktreeAlias, shell.WorktreeDir)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/elvisnm/wt/internal/labels"
 )
@@ -318,6 +319,27 @@ func (mgr *Manager) FocusByIndex(idx int) *Session {
 	return mgr.groups[idx].Primary()
 }
 
+// FocusBySessionID makes the group containing the session active and returns the session.
+func (mgr *Manager) FocusBySessionID(session_id int) *Session {
+	idx := -1
+	var target *TabGroup
+	mgr.mu.Lock()
+	for i, g := range mgr.groups {
+		if g.Contains(session_id) {
+			idx = i
+			target = g
+			break
+		}
+	}
+	mgr.mu.Unlock()
+	if target == nil {
+		return nil
+	}
+
+	mgr.FocusByIndex(idx)
+	return target.SessionByID(session_id)
+}
+
 // HasLabel returns true if any session (alive or dead) exists with this label.
 func (mgr *Manager) HasLabel(label string) bool {
 	mgr.mu.Lock()
@@ -346,6 +368,49 @@ func (mgr *Manager) IsLabelAlive(label string) bool {
 	return false
 }
 
+// RelabelWorktree moves a renamed worktree's sessions to its new alias:
+// "Shell — api" and "Shell — api #2" become "Shell — billing" and
+// "Shell — billing #2". A non-empty dir replaces the worktree directory.
+// Returns how many sessions were relabeled.
+func (mgr *Manager) RelabelWorktree(old_alias, new_alias, dir string) int {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	n := 0
+	for _, g := range mgr.groups {
+		for _, s := range g.sessions {
+			label, ok := relabel(s.Label, old_alias, new_alias)
+			if !ok && s.WorktreeAlias != old_alias {
+				continue
+			}
+			s.mu.Lock()
+			s.Label = label
+			if s.WorktreeAlias == old_alias {
+				s.WorktreeAlias = new_alias
+				if dir != "" {
+					s.WorktreeDir = dir
+				}
+			}
+			s.mu.Unlock()
+			n++
+		}
+	}
+	return n
+}
+
+// relabel swaps the alias suffix of a "Prefix — alias" label, keeping a
+// " #n" counter.
+func relabel(label, old_alias, new_alias string) (string, bool) {
+	base, counter := label, ""
+	if i := strings.LastIndex(label, " #"); i > 0 {
+		base, counter = label[:i], label[i:]
+	}
+	prefix, ok := strings.CutSuffix(base, labels.Sep+old_alias)
+	if !ok || prefix == "" {
+		return label, false
+	}
+	return labels.Tab(prefix, new_alias) + counter, true
+}
+
 // Active returns the primary session of the active group, or nil.
 func (mgr *Manager) Active() *Session {
 	mgr.mu.Lock()
@@ -457,8 +522,14 @@ func (mgr *Manager) SplitInto(target_session_id int, label, cmd_name string, arg
 
 	mgr.mu.Lock()
 	target_group.Add(s, target_session_id, split_dir)
+	synced := target_group.synced
 	mgr.mu.Unlock()
 
+	// New panes join an active broadcast
+	if synced {
+		s.set_sync(true)
+	}
+
 	// If this group is currently visible, join the new pane into the viewport.
 	// join-pane -s <new> -t <existing> places the new pane after (right/below) the existing one.
 	if is_active && pl != nil {
@@ -563,6 +634,9 @@ func (mgr *Manager) MoveInto(session_id, target_session_id int, split_dir SplitD
 
 	// Add session to target group
 	target_group.Add(session, target_session_id, split_dir)
+	if target_group.synced {
+		session.set_sync(true)
+	}
 
 	// If target group is visible, rejoin with new pane
 	// Recalculate is_target_active after index shift
@@ -860,6 +934,26 @@ func (mgr *Manager) CloseDeadLogs() bool {
 	return mgr.CloseDeadByPrefix(labels.Logs)
 }
 
+// CloseDeadWhere closes dead sessions for which should_close(label, exit_code)
+// returns true. Returns true if any were closed.
+func (mgr *Manager) CloseDeadWhere(should_close func(label string, exit_code int) bool) bool {
+	mgr.mu.Lock()
+	var ids []int
+	for _, g := range mgr.groups {
+		for _, s := range g.sessions {
+			if !s.IsAlive() && should_close(s.Label, s.exit_code()) {
+				ids = append(ids, s.ID)
+			}
+		}
+	}
+	mgr.mu.Unlock()
+
+	for _, id := range ids {
+		mgr.CloseBySessionID(id)
+	}
+	return len(ids) > 0
+}
+
 // CloseDeadByLabel closes a dead session with the exact label.
 func (mgr *Manager) CloseDeadByLabel(label string) bool {
 	mgr.mu.Lock()
@@ -953,6 +1047,9 @@ func (mgr *Manager) tab_labels_internal(flat_cursor int) []TabLabel {
 				Label:     s.Label,
 				Active:    is_active,
 				Alive:     s.IsAlive(),
+				Recording: s.Recording() != "",
+				ExitCode:  s.exit_code(),
+				Runtime:   s.Runtime(),
 				SessionID: s.ID,
 				GroupID:   g.ID,
 				GroupSize: 1,
@@ -968,6 +1065,7 @@ func (mgr *Manager) tab_labels_internal(flat_cursor int) []TabLabel {
 				Label:       fmt.Sprintf("Group (%d panes)", len(sessions)),
 				Active:      is_active,
 				Alive:       true,
+				Synced:      g.synced,
 				GroupID:     g.ID,
 				IsGroupHead: true,
 				GroupSize:   len(sessions),
@@ -984,6 +1082,9 @@ func (mgr *Manager) tab_labels_internal(flat_cursor int) []TabLabel {
 					Label:        s.Label,
 					Active:       is_active,
 					Alive:        s.IsAlive(),
+					Recording:    s.Recording() != "",
+					ExitCode:     s.exit_code(),
+					Runtime:      s.Runtime(),
 					SessionID:    s.ID,
 					GroupID:      g.ID,
 					IsGroupChild: true,
@@ -1025,6 +1126,10 @@ type TabLabel struct {
 	Label        string
 	Active       bool
 	Alive        bool
+	Recording    bool          // pane output is being recorded to a cast file
+	Synced       bool          // group head: input is broadcast to all panes
+	ExitCode     int           // exit code once the session is dead (-1 if unknown)
+	Runtime      time.Duration // how long the command ran (so far, if alive)
 	SessionID    int
 	GroupID      int
 	IsGroupHead  bool     // true for the group header line (multi-session groups)
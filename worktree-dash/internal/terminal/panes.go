@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+
+	"github.com/elvisnm/wt/internal/theme"
 )
 
 // PaneLayout manages the tmux pane layout for the dashboard.
@@ -113,25 +115,45 @@ func capture_pane_id(ts *TmuxServer, target string) string {
 
 
 
+// TmuxBindings are the user-configurable tmux keys, in tmux key syntax.
+type TmuxBindings struct {
+	Prefix     string // e.g. "C-]"
+	Dashboard  string // prefix+key: return to dashboard
+	Fullscreen string // prefix+key: toggle fullscreen
+	Palette    string // prefix+key: open the command palette
+	PaletteKey string // key sent to the dashboard to open the palette
+}
+
+// DefaultTmuxBindings returns the built-in tmux keys.
+func DefaultTmuxBindings() TmuxBindings {
+	return TmuxBindings{
+		Prefix:     "C-]",
+		Dashboard:  "q",
+		Fullscreen: "f",
+		Palette:    "p",
+		PaletteKey: "C-p",
+	}
+}
+
 // ConfigureBindings sets up tmux key bindings for pane navigation.
-// prefix (Ctrl+]) then q = return focus to left pane (auto-unzooms if zoomed)
+// prefix (Ctrl+] by default) then q = return focus to left pane (auto-unzooms if zoomed)
 // prefix then f = toggle fullscreen (zoom right pane)
-func (pl *PaneLayout) ConfigureBindings() {
+func (pl *PaneLayout) ConfigureBindings(b TmuxBindings) {
 	ts := pl.server
 
 	// Strip all default bindings first
 	ts.Run("unbind-key", "-a")
 
-	// Use Ctrl+] as prefix — rarely used by terminal apps, works in Claude Code
-	ts.Run("set-option", "-g", "prefix", "C-]")
+	// Ctrl+] by default — rarely used by terminal apps, works in Claude Code
+	ts.Run("set-option", "-g", "prefix", b.Prefix)
 	ts.Run("set-option", "-g", "prefix2", "None")
 
 	// prefix+q: return to dashboard — select-pane auto-unzooms if zoomed
-	ts.Run("bind-key", "q", "select-pane", "-t", pl.left_pane_id)
+	ts.Run("bind-key", b.Dashboard, "select-pane", "-t", pl.left_pane_id)
 
 	// prefix+f: toggle zoom on the right viewport (last pane in window 0)
 	right_target := pl.resolve_right_viewport()
-	ts.Run("bind-key", "f", "resize-pane", "-t", right_target, "-Z")
+	ts.Run("bind-key", b.Fullscreen, "resize-pane", "-t", right_target, "-Z")
 
 	// prefix+1-9: jump to tab N — sends Alt+N to bubbletea (pane 0)
 	// Bubbletea's alt_tab_number handler picks this up and calls FocusByIndex + FocusRight
@@ -140,13 +162,16 @@ func (pl *PaneLayout) ConfigureBindings() {
 		ts.Run("bind-key", key, "send-keys", "-t", pl.left_pane_id, fmt.Sprintf("M-%d", i))
 	}
 
+	// prefix+p: open the command palette — focus the dashboard and send its palette key
+	ts.Run("bind-key", b.Palette, "select-pane", "-t", pl.left_pane_id, `\;`,
+		"send-keys", "-t", pl.left_pane_id, b.PaletteKey)
+
 	// Focus indicator: green divider when right pane (terminal) is active,
 	// dim gray when left pane (dashboard) is active.
 	// after-select-pane hook fires on every pane focus change.
 	// #{pane_index}: "0" = falsy (dashboard), "1" = truthy (terminal)
 	// Set BOTH border styles so the entire divider changes color uniformly.
-	ts.Run("set-hook", "-g", "after-select-pane",
-		`if-shell -F "#{pane_index}" "set -g pane-border-style fg=colour34 ; set -g pane-active-border-style fg=colour34" "set -g pane-border-style fg=colour240 ; set -g pane-active-border-style fg=colour240"`)
+	ts.Run("set-hook", "-g", "after-select-pane", border_hook(theme.Current()))
 
 	// Block C-k on the dashboard pane (pane 0). iTerm2 Cmd+K sends C-k to the
 	// terminal process. Without this, it clears the bubbletea display.
@@ -166,6 +191,18 @@ func (pl *PaneLayout) ConfigureBindings() {
 	ts.Run("unbind-key", "-n", "MouseDrag1Border")
 }
 
+// border_hook returns the after-select-pane hook that colors the divider:
+// the theme's focus color when the terminal is active, its border color otherwise.
+// Under NO_COLOR the active divider is bold instead.
+func border_hook(t theme.Theme) string {
+	active, inactive := "fg="+theme.Tmux(t.Focus), "fg="+theme.Tmux(t.Border)
+	if theme.NoColor() {
+		active += ",bold"
+	}
+	return fmt.Sprintf(`if-shell -F "#{pane_index}" "set -g pane-border-style %s ; set -g pane-active-border-style %s" "set -g pane-border-style %s ; set -g pane-active-border-style %s"`,
+		active, active, inactive, inactive)
+}
+
 // ── Notification / Menu Panel ──────────────────────────────────────────
 
 const (
@@ -528,7 +565,7 @@ func (pl *PaneLayout) RightPaneDimensions() (int, int) {
 func (pl *PaneLayout) UpdateStatusBar(left, right string) {
 	ts := pl.server
 	ts.Run("set-option", "-g", "status", "on")
-	ts.Run("set-option", "-g", "status-style", "bg=default,fg=colour240")
+	ts.Run("set-option", "-g", "status-style", "bg=default,fg="+theme.Tmux(theme.Current().Border))
 	ts.Run("set-option", "-g", "status-left-length", "50")
 	ts.Run("set-option", "-g", "status-right-length", "120")
 	ts.Run("set-option", "-g", "status-left", fmt.Sprintf(" %s", left))
@@ -1,6 +1,7 @@
 package terminal
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -150,7 +151,18 @@ func TestConfigureBindings(t *testing.T) {
 	_, pl := setupPaneLayout(t)
 
 	// Should not panic
-	pl.ConfigureBindings()
+	pl.ConfigureBindings(DefaultTmuxBindings())
+
+	b := DefaultTmuxBindings()
+	b.Prefix = "C-a"
+	pl.ConfigureBindings(b)
+	out, err := pl.server.Run("show-options", "-gv", "prefix")
+	if err != nil {
+		t.Fatalf("show-options: %v", err)
+	}
+	if got := strings.TrimSpace(out); got != "C-a" {
+		t.Errorf("prefix = %q, want C-a", got)
+	}
 }
 
 func TestNewPaneLayout(t *testing.T) {
@@ -0,0 +1,63 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StartRecording pipes the pane's output into `<exe> _record <path> <w> <h> <title>`,
+// which writes an asciinema v2 cast file into dir. exe is the wt binary.
+// Returns the path of the cast file being written.
+func (s *Session) StartRecording(exe, dir string) (string, error) {
+	s.mu.Lock()
+	already := s.recording != ""
+	s.mu.Unlock()
+	if already {
+		return "", fmt.Errorf("already recording")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s.cast", file_slug(s.Label), time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+
+	target := s.pane_target()
+	w, h := 80, 24
+	if out, err := s.server.Run("display-message", "-t", target, "-p", "#{pane_width} #{pane_height}"); err == nil {
+		fmt.Sscanf(strings.TrimSpace(out), "%d %d", &w, &h)
+	}
+
+	cmd := quote_args(exe, []string{"_record", path, fmt.Sprint(w), fmt.Sprint(h), s.Label})
+	if out, err := s.server.Run("pipe-pane", "-O", "-t", target, "exec "+cmd); err != nil {
+		return "", fmt.Errorf("pipe-pane failed: %w\n%s", err, out)
+	}
+
+	s.mu.Lock()
+	s.recording = path
+	s.mu.Unlock()
+	return path, nil
+}
+
+// StopRecording closes the pipe and returns the path of the finished recording.
+func (s *Session) StopRecording() string {
+	s.mu.Lock()
+	path := s.recording
+	s.recording = ""
+	s.mu.Unlock()
+	if path == "" {
+		return ""
+	}
+	// pipe-pane with no command closes the existing pipe
+	s.server.Run("pipe-pane", "-t", s.pane_target())
+	return path
+}
+
+// Recording returns the cast file path while the session is being recorded.
+func (s *Session) Recording() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recording
+}
@@ -0,0 +1,79 @@
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRecorder stands in for `wt _record`: it copies the piped pane output
+// into the cast path it's given.
+func fakeRecorder(t *testing.T) string {
+	t.Helper()
+	exe := filepath.Join(t.TempDir(), "wt")
+	if err := os.WriteFile(exe, []byte("#!/bin/sh\ncat > \"$2\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return exe
+}
+
+func TestRecording(t *testing.T) {
+	ts := newTestServer(t)
+	exe, dir := fakeRecorder(t), t.TempDir()
+
+	s, err := NewSession(1, "Shell: api", "bash", []string{"-c", "sleep 0.5; echo recorded; sleep 0.5"}, 80, 24, "", ts)
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	defer s.Close()
+
+	path, err := s.StartRecording(exe, dir)
+	if err != nil {
+		t.Fatalf("StartRecording failed: %v", err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasSuffix(path, ".cast") || s.Recording() != path {
+		t.Errorf("path = %q, Recording = %q", path, s.Recording())
+	}
+	if _, err := s.StartRecording(exe, dir); err == nil {
+		t.Error("a second StartRecording should fail while recording")
+	}
+
+	// The recording ends with the pane, without a StopRecording
+	deadline := time.Now().Add(3 * time.Second)
+	for s.IsAlive() && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if s.IsAlive() {
+		t.Fatal("session should have died")
+	}
+	if s.Recording() != "" || s.StopRecording() != "" {
+		t.Errorf("a dead pane is still recording to %q", s.Recording())
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "recorded") {
+		t.Errorf("recorded output = %q", data)
+	}
+}
+
+func TestStopRecording(t *testing.T) {
+	ts := newTestServer(t)
+	exe, dir := fakeRecorder(t), t.TempDir()
+
+	s, err := NewSession(2, "Shell: api", "bash", []string{"-c", "sleep 10"}, 80, 24, "", ts)
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	defer s.Close()
+
+	if s.StopRecording() != "" {
+		t.Error("StopRecording without a recording should return nothing")
+	}
+	path, err := s.StartRecording(exe, dir)
+	if err != nil {
+		t.Fatalf("StartRecording failed: %v", err)
+	}
+	if got := s.StopRecording(); got != path || s.Recording() != "" {
+		t.Errorf("StopRecording = %q, want %q; Recording = %q", got, path, s.Recording())
+	}
+}
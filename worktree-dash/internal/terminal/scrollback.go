@@ -0,0 +1,105 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ScrollbackMatch is one session's hits for a scrollback search.
+// Line holds the most recent matching line (trimmed), Count the total hits.
+type ScrollbackMatch struct {
+	SessionID int
+	Label     string
+	Line      string
+	Count     int
+}
+
+// pane_target returns the stable pane ID when known, falling back to the window target.
+// The pane may have been swapped into the viewport window by ShowSession.
+func (s *Session) pane_target() string {
+	if s.pane_id != "" {
+		return s.pane_id
+	}
+	return s.target
+}
+
+// CaptureScrollback returns the pane's full history plus the visible screen as plain text.
+// Wrapped lines are joined (-J) so long log lines stay intact for grepping.
+func (s *Session) CaptureScrollback() (string, error) {
+	out, err := s.server.Run("capture-pane", "-p", "-J", "-S", "-", "-t", s.pane_target())
+	if err != nil {
+		return "", fmt.Errorf("capture-pane failed: %w", err)
+	}
+	return out, nil
+}
+
+// SaveScrollback writes the captured scrollback to a timestamped file in dir
+// and returns the file path. The directory is created if needed.
+func (s *Session) SaveScrollback(dir string) (string, error) {
+	text, err := s.CaptureScrollback()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s.log", file_slug(s.Label), time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(text+"\n"), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// RevealMatch puts the pane into copy mode and searches backward for query,
+// so the most recent match is on screen when the tab is focused.
+func (s *Session) RevealMatch(query string) {
+	target := s.pane_target()
+	s.server.Run("copy-mode", "-t", target)
+	s.server.Run("send-keys", "-t", target, "-X", "search-backward-text", query)
+}
+
+// SearchScrollback greps the scrollback of every open session for query
+// (case-insensitive substring). Returns one entry per session with hits,
+// in tab order.
+func (mgr *Manager) SearchScrollback(query string) []ScrollbackMatch {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	needle := strings.ToLower(query)
+
+	var matches []ScrollbackMatch
+	for _, s := range mgr.Sessions() {
+		text, err := s.CaptureScrollback()
+		if err != nil {
+			continue
+		}
+		m := ScrollbackMatch{SessionID: s.ID, Label: s.Label}
+		for _, line := range strings.Split(text, "\n") {
+			if strings.Contains(strings.ToLower(line), needle) {
+				m.Count++
+				m.Line = strings.TrimSpace(line)
+			}
+		}
+		if m.Count > 0 {
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+var slug_re = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// file_slug turns a tab label like "Logs — api" into a filename-safe "Logs-api".
+func file_slug(label string) string {
+	slug := strings.Trim(slug_re.ReplaceAllString(label, "-"), "-")
+	if slug == "" {
+		return "session"
+	}
+	return slug
+}
@@ -26,6 +26,17 @@ type Session struct {
 
 	ExitCode int // process exit code (-1 if unknown)
 
+	// What the pane runs, kept so a finished session can be rerun in place
+	command   string // command line as the user would type it
+	shell_cmd string // what tmux runs (command with exec, or the edited line)
+	dir       string
+	send_keys bool // command is typed into an interactive shell
+
+	started time.Time
+	ended   time.Time // zero while running
+
+	recording string // cast file path while pipe-pane is recording, "" otherwise
+
 	done chan struct{}
 	mu   sync.Mutex
 }
@@ -97,15 +108,19 @@ func NewSession(id int, label string, cmd_name string, args []string, width, hei
 	)
 
 	s := &Session{
-		ID:       id,
-		Label:    label,
-		Alive:    true,
-		server:   server,
-		window:   window,
-		target:   target,
-		pane_id:  pane_id,
-		ExitCode: -1,
-		done:     make(chan struct{}),
+		ID:        id,
+		Label:     label,
+		Alive:     true,
+		server:    server,
+		window:    window,
+		target:    target,
+		pane_id:   pane_id,
+		ExitCode:  -1,
+		command:   quote_args(cmd_name, args),
+		shell_cmd: shell_cmd,
+		dir:       dir,
+		started:   time.Now(),
+		done:      make(chan struct{}),
 	}
 
 	go s.monitor_loop()
@@ -156,15 +171,19 @@ func NewSessionSendKeys(id int, label string, cmd_name string, args []string, wi
 	server.Run("send-keys", "-t", target, "clear && "+shell_cmd, "Enter")
 
 	s := &Session{
-		ID:       id,
-		Label:    label,
-		Alive:    true,
-		server:   server,
-		window:   window,
-		target:   target,
-		pane_id:  pane_id,
-		ExitCode: -1,
-		done:     make(chan struct{}),
+		ID:        id,
+		Label:     label,
+		Alive:     true,
+		server:    server,
+		window:    window,
+		target:    target,
+		pane_id:   pane_id,
+		ExitCode:  -1,
+		command:   shell_cmd,
+		dir:       dir,
+		send_keys: true,
+		started:   time.Now(),
+		done:      make(chan struct{}),
 	}
 
 	go s.monitor_loop()
@@ -204,6 +223,8 @@ func (s *Session) monitor_loop() {
 			// Pane/window was killed externally
 			s.mu.Lock()
 			s.Alive = false
+			s.ended = time.Now()
+			s.recording = ""
 			s.mu.Unlock()
 			return
 		}
@@ -218,6 +239,9 @@ func (s *Session) monitor_loop() {
 			s.mu.Lock()
 			s.Alive = false
 			s.ExitCode = exit_code
+			s.ended = time.Now()
+			// The pane's output ends, and with it any recording of it
+			s.recording = ""
 			s.mu.Unlock()
 			return
 		}
@@ -263,23 +287,89 @@ func (s *Session) PaneID() string {
 // The tmux window and pane stay in place — no pane swapping or window recreation.
 func (s *Session) Respawn(cmd_name string, args []string, dir string) {
 	s.mu.Lock()
+	s.command = quote_args(cmd_name, args)
+	s.shell_cmd = build_shell_cmd(cmd_name, args)
+	s.dir = dir
+	s.send_keys = false
+	s.mu.Unlock()
+	s.respawn()
+}
+
+// Rerun starts the session's last command again in the same pane.
+func (s *Session) Rerun() {
+	s.respawn()
+}
+
+// RerunLine replaces the session's command with an edited command line and runs it.
+// The line goes through the shell as typed, so pipes and && work.
+func (s *Session) RerunLine(line string) {
+	s.mu.Lock()
+	s.command = line
+	s.shell_cmd = line
+	s.mu.Unlock()
+	s.respawn()
+}
+
+// respawn restarts the pane with the current command, resetting exit state.
+// If the previous process already exited, the monitor is restarted too.
+func (s *Session) respawn() {
+	s.mu.Lock()
+	was_dead := !s.Alive
 	s.Alive = true
 	s.ExitCode = -1
+	s.started = time.Now()
+	s.ended = time.Time{}
+	if was_dead {
+		s.done = make(chan struct{})
+	}
+	shell_cmd, command, dir, send_keys := s.shell_cmd, s.command, s.dir, s.send_keys
 	s.mu.Unlock()
 
-	shell_cmd := build_shell_cmd(cmd_name, args)
 	// Use pane_id (e.g. "%5") instead of window target — the pane may have
 	// been swapped into a different window position by ShowSession.
-	target := s.pane_id
-	if target == "" {
-		target = s.target
-	}
+	target := s.pane_target()
 	respawn_args := []string{"respawn-pane", "-k", "-t", target}
 	if dir != "" {
 		respawn_args = append(respawn_args, "-c", dir)
 	}
-	respawn_args = append(respawn_args, shell_cmd)
+	if !send_keys {
+		respawn_args = append(respawn_args, shell_cmd)
+	}
 	s.server.Run(respawn_args...)
+	if send_keys {
+		s.server.Run("send-keys", "-t", target, "clear && "+command, "Enter")
+	}
+
+	if was_dead {
+		go s.monitor_loop()
+	}
+}
+
+// exit_code returns the process exit code (-1 while running or if unknown).
+func (s *Session) exit_code() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ExitCode
+}
+
+// CommandLine returns the command the session runs, as it would be typed.
+func (s *Session) CommandLine() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.command
+}
+
+// Runtime returns how long the command ran (or has been running so far).
+func (s *Session) Runtime() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started.IsZero() {
+		return 0
+	}
+	if s.ended.IsZero() {
+		return time.Since(s.started)
+	}
+	return s.ended.Sub(s.started)
 }
 
 // Close terminates the tmux window.
@@ -287,6 +377,7 @@ func (s *Session) Close() {
 	s.mu.Lock()
 	already_dead := !s.Alive
 	s.Alive = false
+	done := s.done
 	s.mu.Unlock()
 
 	if !already_dead {
@@ -295,7 +386,7 @@ func (s *Session) Close() {
 
 	// Wait for monitor to finish (with timeout)
 	select {
-	case <-s.done:
+	case <-done:
 	case <-time.After(500 * time.Millisecond):
 	}
 }
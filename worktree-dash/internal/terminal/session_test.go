@@ -1,6 +1,9 @@
 package terminal
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -97,3 +100,132 @@ func TestSessionClose(t *testing.T) {
 		t.Fatal("session should not be alive after Close")
 	}
 }
+
+func TestCaptureAndSearchScrollback(t *testing.T) {
+	ts := newTestServer(t)
+	mgr := NewManagerWithServer(ts)
+	defer mgr.CloseAll()
+
+	if _, err := mgr.OpenNew("Logs — api", "bash", []string{"-c", "echo boot ok; echo 'TypeError: boom'; sleep 60"}, 80, 24, ""); err != nil {
+		t.Fatalf("OpenNew failed: %v", err)
+	}
+	if _, err := mgr.OpenNew("Logs — web", "bash", []string{"-c", "echo ready; sleep 60"}, 80, 24, ""); err != nil {
+		t.Fatalf("OpenNew failed: %v", err)
+	}
+
+	var matches []ScrollbackMatch
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if matches = mgr.SearchScrollback("typeerror"); len(matches) > 0 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if len(matches) != 1 {
+		t.Fatalf("matches = %d, want 1", len(matches))
+	}
+	if matches[0].Label != "Logs — api" {
+		t.Errorf("Label = %q, want %q", matches[0].Label, "Logs — api")
+	}
+	if matches[0].Line != "TypeError: boom" {
+		t.Errorf("Line = %q, want %q", matches[0].Line, "TypeError: boom")
+	}
+
+	s := mgr.FocusBySessionID(matches[0].SessionID)
+	if s == nil || s.Label != "Logs — api" {
+		t.Fatal("FocusBySessionID should return the matching session")
+	}
+
+	path, err := s.SaveScrollback(t.TempDir())
+	if err != nil {
+		t.Fatalf("SaveScrollback failed: %v", err)
+	}
+	if !strings.HasPrefix(filepath.Base(path), "Logs-api-") {
+		t.Errorf("capture file = %q, want Logs-api- prefix", filepath.Base(path))
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "TypeError: boom") {
+		t.Error("capture file should contain the scrollback")
+	}
+}
+
+func TestToggleSync(t *testing.T) {
+	ts := newTestServer(t)
+	mgr := NewManagerWithServer(ts)
+	defer mgr.CloseAll()
+
+	first, err := mgr.OpenNew("Shell — api", "bash", []string{"-c", "sleep 60"}, 80, 24, "")
+	if err != nil {
+		t.Fatalf("OpenNew failed: %v", err)
+	}
+	group_id := mgr.ActiveGroup().ID
+	if _, err := mgr.ToggleSync(group_id); err == nil {
+		t.Error("ToggleSync on a single-pane group should fail")
+	}
+
+	second, err := mgr.SplitInto(first.ID, "Shell — web", "bash", []string{"-c", "sleep 60"}, 80, 24, "", SplitH)
+	if err != nil {
+		t.Fatalf("SplitInto failed: %v", err)
+	}
+
+	on, err := mgr.ToggleSync(group_id)
+	if err != nil || !on {
+		t.Fatalf("ToggleSync = %v, %v; want true, nil", on, err)
+	}
+	for _, s := range []*Session{first, second} {
+		out, _ := ts.Run("show-options", "-p", "-v", "-t", s.pane_target(), "synchronize-panes")
+		if strings.TrimSpace(out) != "on" {
+			t.Errorf("%s synchronize-panes = %q, want on", s.Label, out)
+		}
+	}
+	if labels := mgr.TabLabels(); len(labels) == 0 || !labels[0].IsGroupHead || !labels[0].Synced {
+		t.Errorf("group head should be marked Synced: %+v", labels)
+	}
+
+	if on, _ := mgr.ToggleSync(group_id); on {
+		t.Error("second ToggleSync should turn sync off")
+	}
+}
+
+func TestSessionRerun(t *testing.T) {
+	ts := newTestServer(t)
+
+	// Outlive the remain-on-exit setup so the dead pane sticks around
+	s, err := NewSession(7, "Build — api", "bash", []string{"-c", "sleep 0.3; exit 3"}, 80, 24, "", ts)
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+	defer s.Close()
+
+	wait_dead := func() {
+		deadline := time.Now().Add(3 * time.Second)
+		for time.Now().Before(deadline) && s.IsAlive() {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if s.IsAlive() {
+			t.Fatal("session should have died")
+		}
+	}
+
+	wait_dead()
+	if got := s.CommandLine(); got != "bash -c 'sleep 0.3; exit 3'" {
+		t.Errorf("CommandLine = %q", got)
+	}
+
+	s.RerunLine("sleep 0.3; exit 5")
+	if !s.IsAlive() || s.exit_code() != -1 {
+		t.Fatal("RerunLine should reset the session to running")
+	}
+	wait_dead()
+	// tmux occasionally marks a pane dead without a status (see TestSessionExitCode)
+	if code := s.exit_code(); code != 5 && code != -1 {
+		t.Errorf("ExitCode after rerun = %d, want 5", code)
+	}
+	if rt := s.Runtime(); rt < 300*time.Millisecond {
+		t.Errorf("Runtime = %v, want >= 300ms", rt)
+	}
+	if got := s.CommandLine(); got != "sleep 0.3; exit 5" {
+		t.Errorf("CommandLine after edit = %q", got)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+
+	"github.com/elvisnm/wt/internal/theme"
 )
 
 // TmuxServer manages a dedicated tmux server instance for the dashboard.
@@ -134,8 +136,8 @@ func (ts *TmuxServer) EnsureStarted(width, height int) error {
 		{"set-option", "-g", "escape-time", "200"},
 		{"set-option", "-g", "mouse", "on"},
 		// Pane border defaults (overridden by focus hook in ConfigureBindings)
-		{"set-option", "-g", "pane-border-style", "fg=colour240"},
-		{"set-option", "-g", "pane-active-border-style", "fg=colour240"},
+		{"set-option", "-g", "pane-border-style", "fg=" + theme.Tmux(theme.Current().Border)},
+		{"set-option", "-g", "pane-active-border-style", "fg=" + theme.Tmux(theme.Current().Border)},
 		// Set an obscure prefix key to avoid conflicts
 		{"set-option", "-g", "prefix", "C-b"},
 		{"set-option", "-g", "prefix2", "None"},
@@ -0,0 +1,199 @@
+// Package theme holds the dashboard's color palette. The ui package, the raw
+// ANSI renderers (guide, help, pickers, notifications) and the tmux border hook
+// all read colors from the current theme, so they stay consistent.
+package theme
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Default is the theme used when settings don't name one.
+const Default = "dark"
+
+// Theme is a named palette. Colors are ANSI 256 indexes ("34") or hex ("#5fafff").
+type Theme struct {
+	Name string
+
+	Border     string // unfocused panel borders, dividers
+	Focus      string // focused panel border, titles
+	DimText    string // secondary text
+	Highlight  string
+	SelectedBg string // cursor row background
+	SelectedFg string // cursor row text
+	Running    string
+	Stopped    string
+	Starting   string
+	Header     string
+	Hint       string // key hints, in-progress markers
+	Text       string // primary text, headings
+	SoftText   string // values next to labels
+	Muted      string // section labels
+	Error      string
+	Warning    string
+}
+
+var themes = map[string]Theme{
+	"dark": {
+		Name:   "dark",
+		Border: "240", Focus: "34", DimText: "250", Highlight: "34",
+		SelectedBg: "25", SelectedFg: "255",
+		Running: "34", Stopped: "160", Starting: "214",
+		Header: "240", Hint: "214",
+		Text: "255", SoftText: "252", Muted: "248",
+		Error: "196", Warning: "208",
+	},
+	"light": {
+		Name:   "light",
+		Border: "245", Focus: "28", DimText: "240", Highlight: "28",
+		SelectedBg: "153", SelectedFg: "16",
+		Running: "28", Stopped: "124", Starting: "166",
+		Header: "245", Hint: "166",
+		Text: "16", SoftText: "236", Muted: "242",
+		Error: "160", Warning: "166",
+	},
+	"high-contrast": {
+		Name:   "high-contrast",
+		Border: "250", Focus: "46", DimText: "255", Highlight: "46",
+		SelectedBg: "21", SelectedFg: "231",
+		Running: "46", Stopped: "196", Starting: "226",
+		Header: "250", Hint: "226",
+		Text: "231", SoftText: "231", Muted: "253",
+		Error: "196", Warning: "214",
+	},
+}
+
+// Names lists the built-in themes.
+func Names() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fields maps the settings.json color names to t's fields.
+func (t *Theme) fields() map[string]*string {
+	return map[string]*string{
+		"border":      &t.Border,
+		"focus":       &t.Focus,
+		"dim_text":    &t.DimText,
+		"highlight":   &t.Highlight,
+		"selected_bg": &t.SelectedBg,
+		"selected_fg": &t.SelectedFg,
+		"running":     &t.Running,
+		"stopped":     &t.Stopped,
+		"starting":    &t.Starting,
+		"header":      &t.Header,
+		"hint":        &t.Hint,
+		"text":        &t.Text,
+		"soft_text":   &t.SoftText,
+		"muted":       &t.Muted,
+		"error":       &t.Error,
+		"warning":     &t.Warning,
+	}
+}
+
+// Resolve returns the named theme with color overrides applied, plus a
+// description of any unknown name, color or invalid value (which are ignored).
+func Resolve(name string, overrides map[string]string) (Theme, []string) {
+	var problems []string
+	if name == "" {
+		name = Default
+	}
+	t, ok := themes[name]
+	if !ok {
+		problems = append(problems, fmt.Sprintf("unknown theme %q (have %s)", name, strings.Join(Names(), ", ")))
+		t = themes[Default]
+	}
+
+	fields := t.fields()
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		field, ok := fields[k]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown color %q", k))
+			continue
+		}
+		v := strings.TrimSpace(overrides[k])
+		if !valid(v) {
+			problems = append(problems, fmt.Sprintf("%s: %q is not 0-255 or #rrggbb", k, v))
+			continue
+		}
+		*field = v
+	}
+	return t, problems
+}
+
+// valid reports whether c is an ANSI 256 index or a #rrggbb hex color.
+func valid(c string) bool {
+	if n, err := strconv.Atoi(c); err == nil {
+		return n >= 0 && n <= 255
+	}
+	if len(c) != 7 || c[0] != '#' {
+		return false
+	}
+	_, err := strconv.ParseUint(c[1:], 16, 32)
+	return err == nil
+}
+
+var (
+	mu      sync.RWMutex
+	current = themes[Default]
+)
+
+// Current returns the active theme.
+func Current() Theme {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Set makes t the active theme.
+func Set(t Theme) {
+	mu.Lock()
+	current = t
+	mu.Unlock()
+}
+
+// NoColor reports whether NO_COLOR is set (https://no-color.org/).
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// FG returns the ANSI escape that sets the foreground to c, or "" under NO_COLOR.
+func FG(c string) string {
+	if NoColor() || c == "" {
+		return ""
+	}
+	if strings.HasPrefix(c, "#") {
+		v, _ := strconv.ParseUint(c[1:], 16, 32)
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", v>>16&0xff, v>>8&0xff, v&0xff)
+	}
+	return "\033[38;5;" + c + "m"
+}
+
+// Bold returns bold plus FG(c). Under NO_COLOR it is just bold.
+func Bold(c string) string {
+	return "\033[1m" + FG(c)
+}
+
+// Tmux returns c in tmux style syntax ("colour34", "#5fafff"), or "default" under NO_COLOR.
+func Tmux(c string) string {
+	if NoColor() || c == "" {
+		return "default"
+	}
+	if strings.HasPrefix(c, "#") {
+		return c
+	}
+	return "colour" + c
+}
@@ -0,0 +1,88 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveDefault(t *testing.T) {
+	th, problems := Resolve("", nil)
+	if th.Name != Default || len(problems) != 0 {
+		t.Errorf("Resolve(\"\") = %q, %v", th.Name, problems)
+	}
+	if th.Border != "240" || th.Hint != "214" {
+		t.Errorf("dark palette changed: border=%s hint=%s", th.Border, th.Hint)
+	}
+}
+
+func TestResolveNamedThemes(t *testing.T) {
+	for _, name := range Names() {
+		th, problems := Resolve(name, nil)
+		if th.Name != name || len(problems) != 0 {
+			t.Errorf("Resolve(%q) = %q, %v", name, th.Name, problems)
+		}
+		for field, v := range th.fields() {
+			if !valid(*v) {
+				t.Errorf("%s.%s = %q is not a valid color", name, field, *v)
+			}
+		}
+	}
+}
+
+func TestResolveUnknownTheme(t *testing.T) {
+	th, problems := Resolve("solarized", nil)
+	if th.Name != Default {
+		t.Errorf("unknown theme fell back to %q, want %q", th.Name, Default)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], "solarized") {
+		t.Errorf("problems = %v", problems)
+	}
+}
+
+func TestResolveOverrides(t *testing.T) {
+	th, problems := Resolve("light", map[string]string{
+		"border":  "244",
+		"hint":    "#FF8700",
+		"running": "300",
+		"error":   "red",
+		"bogus":   "1",
+	})
+	if th.Border != "244" || th.Hint != "#FF8700" {
+		t.Errorf("overrides not applied: border=%s hint=%s", th.Border, th.Hint)
+	}
+	if th.Running != themes["light"].Running || th.Error != themes["light"].Error {
+		t.Errorf("invalid overrides applied: running=%s error=%s", th.Running, th.Error)
+	}
+	if len(problems) != 3 {
+		t.Errorf("problems = %v, want 3", problems)
+	}
+}
+
+func TestFG(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if got := FG("214"); got != "\033[38;5;214m" {
+		t.Errorf("FG(214) = %q", got)
+	}
+	if got := FG("#ff8700"); got != "\033[38;2;255;135;0m" {
+		t.Errorf("FG(#ff8700) = %q", got)
+	}
+	if got := Tmux("240"); got != "colour240" {
+		t.Errorf("Tmux(240) = %q", got)
+	}
+	if got := Tmux("#ff8700"); got != "#ff8700" {
+		t.Errorf("Tmux(#ff8700) = %q", got)
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if got := FG("214"); got != "" {
+		t.Errorf("FG under NO_COLOR = %q, want empty", got)
+	}
+	if got := Bold("214"); got != "\033[1m" {
+		t.Errorf("Bold under NO_COLOR = %q, want bold only", got)
+	}
+	if got := Tmux("240"); got != "default" {
+		t.Errorf("Tmux under NO_COLOR = %q, want default", got)
+	}
+}
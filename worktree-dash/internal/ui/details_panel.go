@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/elvisnm/wt/internal/config"
 	"github.com/elvisnm/wt/internal/worktree"
@@ -13,7 +14,7 @@ import (
 
 var (
 	label_style = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("248")).
+			Foreground(MutedColor).
 			Width(10)
 
 	value_style = lipgloss.NewStyle()
@@ -106,6 +107,17 @@ func build_detail_lines(wt *worktree.Worktree, inner_w, spin_frame int, cfg *con
 	lines = append(lines, detail_line("Branch", wt.Branch, inner_w))
 	lines = append(lines, detail_line("Alias", wt.Alias, inner_w))
 	lines = append(lines, detail_line("Type", string(wt.Type), inner_w))
+	if len(wt.Sparse) > 0 {
+		lines = append(lines, detail_line("Sparse", wt.SparseProfile+
+			lipgloss.NewStyle().Foreground(DimTextColor).Render(" ("+strings.Join(wt.Sparse, ", ")+")"), inner_w))
+	}
+	lines = append(lines, build_worktree_state_lines(wt, inner_w)...)
+	if wt.Pinned {
+		lines = append(lines, detail_line("Pinned", lipgloss.NewStyle().Foreground(HintColor).Render("★ yes"), inner_w))
+	}
+	if wt.Note != "" {
+		lines = append(lines, detail_line("Note", wt.Note, inner_w))
+	}
 
 	// Action in progress (e.g. "removing...", "starting...")
 	if strings.HasSuffix(wt.Health, "...") {
@@ -136,16 +148,16 @@ func build_detail_lines(wt *worktree.Worktree, inner_w, spin_frame int, cfg *con
 			lipgloss.NewStyle().Foreground(status_color).Render(status_text), inner_w))
 
 		if wt.Mode != "" {
-			mode_color := lipgloss.Color("214") // orange for minimal
+			mode_color := HintColor // orange for minimal
 			if wt.Mode == "full" {
-				mode_color = lipgloss.Color("34") // green for full
+				mode_color = RunningColor // green for full
 			}
 			mode_styled := lipgloss.NewStyle().Foreground(mode_color).Bold(true).Render(wt.Mode)
 			lines = append(lines, detail_line("Mode", mode_styled, inner_w))
 		}
 		if wt.HostBuild {
 			tag := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("214")).
+				Foreground(HintColor).
 				Bold(true).
 				Render("host-build")
 			lines = append(lines, detail_line("Build", tag, inner_w))
@@ -170,12 +182,12 @@ func build_detail_lines(wt *worktree.Worktree, inner_w, spin_frame int, cfg *con
 		// Quick Links
 		lines = append(lines, "")
 		link_style := lipgloss.NewStyle().Foreground(HintColor)
-		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("248")).Render("Quick Links"))
+		lines = append(lines, lipgloss.NewStyle().Foreground(MutedColor).Render("Quick Links"))
 		lines = append(lines, build_quick_links(wt, cfg, link_style, inner_w)...)
 
 		// Service Ports
 		lines = append(lines, "")
-		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("248")).Render(fmt.Sprintf("Ports (%s)", wt.Mode)))
+		lines = append(lines, lipgloss.NewStyle().Foreground(MutedColor).Render(fmt.Sprintf("Ports (%s)", wt.Mode)))
 		lines = append(lines, build_port_lines(wt, cfg)...)
 	} else if wt.Type == worktree.TypeLocal {
 		if wt.Running {
@@ -191,9 +203,9 @@ func build_detail_lines(wt *worktree.Worktree, inner_w, spin_frame int, cfg *con
 		}
 
 		if wt.Mode != "" {
-			mode_color := lipgloss.Color("214") // orange for minimal
+			mode_color := HintColor // orange for minimal
 			if wt.Mode == "full" {
-				mode_color = lipgloss.Color("34") // green for full
+				mode_color = RunningColor // green for full
 			}
 			mode_styled := lipgloss.NewStyle().Foreground(mode_color).Bold(true).Render(wt.Mode)
 			lines = append(lines, detail_line("Mode", mode_styled, inner_w))
@@ -221,12 +233,167 @@ func build_detail_lines(wt *worktree.Worktree, inner_w, spin_frame int, cfg *con
 		}
 	}
 
+	lines = append(lines, build_git_lines(wt.Git, inner_w)...)
+	lines = append(lines, build_overlap_lines(wt.Overlaps, inner_w)...)
+
 	lines = append(lines, "")
 	lines = append(lines, detail_line("Path", wt.Path, inner_w))
+	for _, s := range wt.Set {
+		lines = append(lines, detail_line(s.Repo, s.Path, inner_w))
+	}
+
+	return lines
+}
+
+// build_worktree_state_lines returns lines for git worktree states worth
+// knowing before acting on a worktree: an operation stopped midway, a sync
+// that conflicted, locked,
+// prunable, detached HEAD, or registered outside the worktrees dir.
+func build_worktree_state_lines(wt *worktree.Worktree, inner_w int) []string {
+	var lines []string
+	if wt.Operation != "" {
+		lines = append(lines, detail_line("State",
+			lipgloss.NewStyle().Foreground(ErrorColor).Render(wt.Operation+" in progress"), inner_w))
+	}
+	if wt.SyncConflict != "" {
+		lines = append(lines, detail_line("Sync",
+			lipgloss.NewStyle().Foreground(ErrorColor).Render("conflicts with "+wt.SyncConflict), inner_w))
+		if len(wt.ConflictFiles) > 0 {
+			lines = append(lines, detail_line("Conflicts", strings.Join(wt.ConflictFiles, ", "), inner_w))
+		}
+	}
+	if wt.Prunable {
+		lines = append(lines, detail_line("Prunable", or_yes(wt.PrunableReason), inner_w))
+	}
+	if wt.Locked {
+		lines = append(lines, detail_line("Locked", or_yes(wt.LockReason), inner_w))
+	}
+	if wt.Detached {
+		lines = append(lines, detail_line("HEAD", "detached", inner_w))
+	}
+	if wt.Outside {
+		lines = append(lines, detail_line("Location", "outside the worktrees dir", inner_w))
+	}
+	return lines
+}
+
+// or_yes returns reason, or "yes" when git gave none.
+func or_yes(reason string) string {
+	if reason == "" {
+		return "yes"
+	}
+	return reason
+}
+
+// build_git_lines returns the Git section: working tree changes, ahead/behind
+// versus upstream and the base ref, and the last commit. Nothing until the
+// status has been read.
+func build_git_lines(s worktree.GitStatus, inner_w int) []string {
+	if !s.Known() {
+		return nil
+	}
+	lines := []string{"", lipgloss.NewStyle().Foreground(MutedColor).Render("Git")}
+
+	changes := lipgloss.NewStyle().Foreground(RunningColor).Render("clean")
+	if s.Dirty() {
+		var parts []string
+		for _, c := range []struct {
+			n     int
+			label string
+		}{{s.Staged, "staged"}, {s.Unstaged, "unstaged"}, {s.Untracked, "untracked"}, {s.Conflicts, "conflicted"}} {
+			if c.n > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", c.n, c.label))
+			}
+		}
+		color := WarningColor
+		if s.Conflicts > 0 {
+			color = ErrorColor
+		}
+		changes = lipgloss.NewStyle().Foreground(color).Render(strings.Join(parts, ", "))
+	}
+	lines = append(lines, detail_line("Changes", changes, inner_w))
+
+	upstream := lipgloss.NewStyle().Foreground(DimTextColor).Render("none")
+	if s.UpstreamGone {
+		upstream = s.Upstream + " " + lipgloss.NewStyle().Foreground(StoppedColor).Render("(gone)")
+	} else if s.UpstreamMissing {
+		upstream = s.Upstream + " " + lipgloss.NewStyle().Foreground(DimTextColor).Render("(not pushed)")
+	} else if s.Upstream != "" {
+		upstream = s.Upstream + " " + ahead_behind(s.Ahead, s.Behind)
+	}
+	lines = append(lines, detail_line_nowrap("Upstream", upstream, inner_w))
+	if s.Base != "" {
+		lines = append(lines, detail_line_nowrap("Base", s.Base+" "+ahead_behind(s.BaseAhead, s.BaseBehind), inner_w))
+	}
+
+	if s.Subject != "" {
+		lines = append(lines, detail_line("Commit", s.Subject, inner_w))
+		author := s.Author
+		if !s.CommitTime.IsZero() {
+			author += lipgloss.NewStyle().Foreground(DimTextColor).Render(" · " + FormatAge(s.CommitTime))
+		}
+		lines = append(lines, detail_line_nowrap("Author", author, inner_w))
+	}
+	return lines
+}
+
+// overlapFilesShown caps the files listed per worktree in the Overlaps section.
+const overlapFilesShown = 5
 
+// build_overlap_lines returns the Overlaps section: the files this worktree
+// changes that others change too, grouped by the worktrees they overlap
+// with ("also modified in search: src/api/orders.ts").
+func build_overlap_lines(overlaps []worktree.Overlap, inner_w int) []string {
+	if len(overlaps) == 0 {
+		return nil
+	}
+	var order []string
+	files := map[string][]string{}
+	for _, o := range overlaps {
+		with := strings.Join(o.With, ", ")
+		if _, ok := files[with]; !ok {
+			order = append(order, with)
+		}
+		files[with] = append(files[with], o.Path)
+	}
+
+	lines := []string{"", lipgloss.NewStyle().Foreground(MutedColor).Render("Overlaps")}
+	wrap := lipgloss.NewStyle().Width(inner_w)
+	for _, with := range order {
+		paths := files[with]
+		if len(paths) > overlapFilesShown {
+			paths = append(paths[:overlapFilesShown:overlapFilesShown], fmt.Sprintf("%d more", len(files[with])-overlapFilesShown))
+		}
+		text := lipgloss.NewStyle().Foreground(WarningColor).Render("also modified in "+with+":") + " " + strings.Join(paths, ", ")
+		lines = append(lines, wrap.Render(text))
+	}
 	return lines
 }
 
+// ahead_behind renders commit counts as "↑2 ↓1", or "in sync".
+func ahead_behind(ahead, behind int) string {
+	if ahead == 0 && behind == 0 {
+		return lipgloss.NewStyle().Foreground(DimTextColor).Render("in sync")
+	}
+	return lipgloss.NewStyle().Foreground(HintColor).Render(fmt.Sprintf("↑%d", ahead)) + " " +
+		lipgloss.NewStyle().Foreground(MutedColor).Render(fmt.Sprintf("↓%d", behind))
+}
+
+// FormatAge formats how long ago t was: "just now", "5m ago", "3h ago", "4d ago".
+func FormatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours())/24)
+	}
+}
+
 // build_quick_links returns the quick link lines using config when available,
 // falling back to hardcoded defaults otherwise.
 func build_quick_links(wt *worktree.Worktree, cfg *config.Config, link_style lipgloss.Style, inner_w int) []string {
@@ -264,8 +431,8 @@ func build_quick_links(wt *worktree.Worktree, cfg *config.Config, link_style lip
 // build_port_lines returns the service port table using config when available,
 // falling back to hardcoded defaults otherwise.
 func build_port_lines(wt *worktree.Worktree, cfg *config.Config) []string {
-	port_name_style := lipgloss.NewStyle().Foreground(lipgloss.Color("248"))
-	port_val_style := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	port_name_style := lipgloss.NewStyle().Foreground(MutedColor)
+	port_val_style := lipgloss.NewStyle().Foreground(SoftTextColor)
 
 	if cfg != nil && len(cfg.Services.Ports) > 0 {
 		// Determine which services to show based on mode
@@ -12,7 +12,19 @@ type HelpSection struct {
 	Items []HintPair
 }
 
-func HelpSections() []HelpSection {
+// HelpKeys are the help sections generated from the app's key registries.
+type HelpKeys struct {
+	Tabs       []HintPair
+	Splits     []HintPair
+	Worktrees  []HintPair
+	Services   []HintPair
+	Tasks      []HintPair
+	TasksKey   string // the key that opens the tasks panel
+	Operations []HintPair
+}
+
+// HelpSections returns the help overlay's sections.
+func HelpSections(keys HelpKeys) []HelpSection {
 	return []HelpSection{
 		{
 			Title: "Navigation",
@@ -27,62 +39,33 @@ func HelpSections() []HelpSection {
 		},
 		{
 			Title: "a - Active Tabs",
-			Items: []HintPair{
-				{Key: "j/k", Desc: "Navigate tabs"},
-				{Key: "Enter", Desc: "Focus terminal"},
-				{Key: "f", Desc: "Fullscreen"},
-				{Key: "x", Desc: "Close tab"},
-			},
+			Items: capitalize_descs(keys.Tabs),
+		},
+		{
+			Title: "Split Panels",
+			Items: capitalize_descs(keys.Splits),
 		},
 		{
 			Title: "w - Worktrees",
-			Items: []HintPair{
-				{Key: "Enter", Desc: "Action menu"},
-				{Key: "b", Desc: "Shell"},
-				{Key: "c", Desc: "Claude Code"},
-				{Key: "g", Desc: "Pull latest"},
-				{Key: "l", Desc: "Logs"},
-				{Key: "n", Desc: "Create worktree"},
-				{Key: "r", Desc: "Restart container"},
-				{Key: "t", Desc: "Stop container"},
-				{Key: "u", Desc: "Start container"},
-				{Key: "i", Desc: "Info"},
-			{Key: "x", Desc: "Remove worktree"},
-			},
+			Items: capitalize_descs(keys.Worktrees),
 		},
 		{
 			Title: "s - Services",
-			Items: []HintPair{
-				{Key: "Enter", Desc: "Preview logs"},
-				{Key: "l", Desc: "Pin logs (tab)"},
-				{Key: "r", Desc: "Restart service"},
-			},
+			Items: capitalize_descs(keys.Services),
 		},
 		{
-			Title: "Tasks (Shift+T)",
-			Items: []HintPair{
-				{Key: "j/k", Desc: "Navigate tasks"},
-				{Key: "Enter", Desc: "Task detail"},
-				{Key: "c", Desc: "Close task"},
-				{Key: "d", Desc: "Delete task"},
-			},
+			Title: "Tasks (" + keys.TasksKey + ")",
+			Items: capitalize_descs(keys.Tasks),
 		},
 		{
 			Title: "Operations",
-			Items: []HintPair{
-				{Key: "Shift+A", Desc: "AWS Keys"},
-				{Key: "Shift+B", Desc: "Database picker"},
-				{Key: "Shift+D", Desc: "Details toggle"},
-				{Key: "Shift+L", Desc: "LAN toggle"},
-				{Key: "Shift+X", Desc: "Admin toggle"},
-				{Key: "Shift+T", Desc: "Tasks"},
-				{Key: "Shift+U", Desc: "Claude usage"},
-			},
+			Items: capitalize_descs(keys.Operations),
 		},
 		{
 			Title: "General",
 			Items: []HintPair{
 				{Key: "?", Desc: "This help"},
+				{Key: ":/Ctrl+P", Desc: "Command palette"},
 				{Key: "q", Desc: "Quit"},
 				{Key: "Ctrl+C", Desc: "Quit"},
 			},
@@ -90,9 +73,21 @@ func HelpSections() []HelpSection {
 	}
 }
 
+// capitalize_descs upper-cases the first letter of each description.
+func capitalize_descs(items []HintPair) []HintPair {
+	out := make([]HintPair, len(items))
+	for i, it := range items {
+		if it.Desc != "" {
+			it.Desc = strings.ToUpper(it.Desc[:1]) + it.Desc[1:]
+		}
+		out[i] = it
+	}
+	return out
+}
+
 // RenderHelpModal returns the help box (to be composited via OverlayCentered).
-func RenderHelpModal(max_w, max_h int) string {
-	sections := HelpSections()
+func RenderHelpModal(max_w, max_h int, keys HelpKeys) string {
+	sections := HelpSections(keys)
 
 	key_style := lipgloss.NewStyle().
 		Bold(true).
@@ -101,11 +96,11 @@ func RenderHelpModal(max_w, max_h int) string {
 		Align(lipgloss.Right)
 
 	desc_style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(SoftTextColor)
 
 	section_title_style := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("255")).
+		Foreground(TextColor).
 		MarginTop(1)
 
 	var all_lines []string
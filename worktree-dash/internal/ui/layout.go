@@ -112,18 +112,28 @@ func (l Layout) Resize(w, h int, opts ResizeOpts) Layout {
 	return l
 }
 
-// Style helpers — lazygit-inspired palette
+// Style helpers — lazygit-inspired palette, replaced by ApplyTheme
 var (
 	BorderColor      = lipgloss.Color("240")
 	FocusBorderColor = lipgloss.Color("34")
 	DimTextColor     = lipgloss.Color("250")
 	HighlightColor   = lipgloss.Color("34")
 	SelectedBgColor  = lipgloss.Color("25")
+	SelectedFgColor  = lipgloss.Color("255")
 	RunningColor     = lipgloss.Color("34")
 	StoppedColor     = lipgloss.Color("160")
 	StartingColor    = lipgloss.Color("214")
 	HeaderColor      = lipgloss.Color("240")
 	HintColor        = lipgloss.Color("214")
+	TextColor        = lipgloss.Color("255")
+	SoftTextColor    = lipgloss.Color("252")
+	MutedColor       = lipgloss.Color("248")
+	ErrorColor       = lipgloss.Color("196")
+	WarningColor     = lipgloss.Color("208")
+
+	// ReverseSelection marks cursor rows with reverse video, for when colors
+	// are off and the selected background wouldn't show.
+	ReverseSelection = false
 )
 
 func PanelStyle(width, height int, focused bool) lipgloss.Style {
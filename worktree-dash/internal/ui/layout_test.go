@@ -3,6 +3,7 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -274,3 +275,26 @@ func TestLayoutResizeDetailsHidden(t *testing.T) {
 		t.Errorf("panel heights sum to %d, want 50", total)
 	}
 }
+
+func TestExitSummary(t *testing.T) {
+	tests := []struct {
+		code    int
+		runtime time.Duration
+		want    string
+	}{
+		{-1, time.Second, "dead"},
+		{0, 12 * time.Second, "✓ 12s"},
+		{2, 3*time.Minute + 5*time.Second, "✗ 2 · 3m"},
+		{0, 64 * time.Minute, "✓ 1h4m"},
+	}
+	for _, tt := range tests {
+		if got := ExitSummary(tt.code, tt.runtime); got != tt.want {
+			t.Errorf("ExitSummary(%d, %v) = %q, want %q", tt.code, tt.runtime, got, tt.want)
+		}
+	}
+
+	tabs := []TabInfo{{Index: 1, Label: "Build — api", Active: true, Alive: false, Exit: "✗ 2 · 3m"}}
+	if result := RenderTabsPanel(tabs, 0, 40, 10, false); !strings.Contains(result, "✗ 2 · 3m") {
+		t.Error("Expected exit summary for finished tab")
+	}
+}
@@ -16,6 +16,8 @@ const (
 	NotifyPicker                     // interactive picker
 	NotifyConfirm                    // yes/no confirm
 	NotifyInput                      // text input
+	NotifyPalette                    // command palette (query + matches)
+	NotifySteps                      // progress of a multi-step operation
 )
 
 // NotifyHeight returns the number of rows the notification area needs.
@@ -35,6 +37,17 @@ func NotifyHeight(state NotifyState, picker_count int) int {
 		return 5 // border + prompt + blank + hints + border
 	case NotifyInput:
 		return 3
+	case NotifyPalette:
+		n := picker_count
+		if n > PaletteMaxRows {
+			n = PaletteMaxRows
+		}
+		if n < 1 {
+			n = 1
+		}
+		return n + 3 // query line + matches + top/bottom border
+	case NotifySteps:
+		return picker_count + 2 // one row per step + top/bottom border
 	default:
 		return 2
 	}
@@ -80,6 +93,65 @@ func RenderNotifyMessage(title, message string, width, height int) string {
 	return inject_title(rendered, title_rendered)
 }
 
+// Step states for RenderNotifySteps.
+const (
+	StepPending = iota
+	StepRunning
+	StepDone
+	StepSkipped
+	StepFailed
+)
+
+// Step is one line of a multi-step operation's progress.
+type Step struct {
+	Label  string
+	State  int
+	Detail string // shown dim after the label: what a step did, or why it failed
+}
+
+// RenderNotifySteps renders the steps of an operation, one per row, with a
+// mark for each step's state.
+func RenderNotifySteps(title string, steps []Step, width int) string {
+	style := PanelStyle(width, len(steps)+2, false).BorderForeground(FocusBorderColor)
+	title_rendered := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(FocusBorderColor).
+		Render(fmt.Sprintf(" %s ", title))
+
+	dim := lipgloss.NewStyle().Foreground(DimTextColor)
+	inner_w := width - 4
+	lines := make([]string, len(steps))
+	for i, step := range steps {
+		var mark string
+		switch step.State {
+		case StepRunning:
+			mark = lipgloss.NewStyle().Foreground(HintColor).Render("●")
+		case StepDone:
+			mark = lipgloss.NewStyle().Foreground(RunningColor).Render("✓")
+		case StepSkipped:
+			mark = dim.Render("–")
+		case StepFailed:
+			mark = lipgloss.NewStyle().Foreground(StoppedColor).Render("✗")
+		default:
+			mark = dim.Render("·")
+		}
+		line := step.Label
+		if step.State == StepPending {
+			line = dim.Render(line)
+		}
+		if step.Detail != "" {
+			room := inner_w - 2 - lipgloss.Width(step.Label) - 3
+			if detail := []rune(step.Detail); room >= 4 && len(detail) > room {
+				line += dim.Render(" — " + string(detail[:room-1]) + "~")
+			} else if room >= 4 {
+				line += dim.Render(" — " + step.Detail)
+			}
+		}
+		lines[i] = mark + " " + line
+	}
+	return inject_title(style.Render(strings.Join(lines, "\n")), title_rendered)
+}
+
 // RenderNotifyPicker renders the picker inline at the top of the view.
 func RenderNotifyPicker(actions []PickerAction, cursor int, width int, title string) string {
 	height := len(actions) + 2
@@ -121,7 +193,7 @@ func RenderNotifyInput(prompt, value string, width, height int) string {
 		Foreground(FocusBorderColor).
 		Render(" Input ")
 
-	cursor_style := lipgloss.NewStyle().Background(lipgloss.Color("240"))
+	cursor_style := lipgloss.NewStyle().Background(BorderColor)
 	prompt_style := lipgloss.NewStyle().Foreground(DimTextColor)
 
 	line := prompt_style.Render(prompt+" ") + value + cursor_style.Render(" ")
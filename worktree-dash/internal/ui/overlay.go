@@ -80,7 +80,7 @@ func render_modal_box(content string, content_width, term_width int) string {
 // RenderConfirmModal renders a centered confirmation box.
 func RenderConfirmModal(prompt string, width, height int) string {
 	prompt_style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("255")).
+		Foreground(TextColor).
 		Bold(true)
 
 	dim_style := lipgloss.NewStyle().Foreground(DimTextColor)
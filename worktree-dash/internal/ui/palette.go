@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PaletteMaxRows is the number of matches shown below the palette query.
+const PaletteMaxRows = 10
+
+// PaletteItem is one match in the command palette.
+type PaletteItem struct {
+	Title string
+	Hint  string
+}
+
+// RenderNotifyPalette renders the command palette inline at the top: the query
+// line followed by a window of matches that keeps the cursor visible.
+func RenderNotifyPalette(query string, items []PaletteItem, cursor int, width int) string {
+	return RenderNotifyPaletteTitled("Command Palette", query, items, cursor, width)
+}
+
+// RenderNotifyPaletteTitled renders the palette under another title, for
+// fuzzy pickers built on it.
+func RenderNotifyPaletteTitled(title, query string, items []PaletteItem, cursor int, width int) string {
+	rows := len(items)
+	if rows > PaletteMaxRows {
+		rows = PaletteMaxRows
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	height := rows + 3
+
+	style := PanelStyle(width, height, false).BorderForeground(FocusBorderColor)
+	title_rendered := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(FocusBorderColor).
+		Render(fmt.Sprintf(" %s (%d) ", title, len(items)))
+
+	inner_w := width - 4
+	cursor_style := lipgloss.NewStyle().Background(BorderColor)
+	prompt := lipgloss.NewStyle().Foreground(HintColor).Render("> ")
+	lines := []string{prompt + query + cursor_style.Render(" ")}
+
+	if len(items) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(DimTextColor).Render("No matches"))
+	}
+
+	start := 0
+	if cursor >= rows {
+		start = cursor - rows + 1
+	}
+	for i := start; i < len(items) && i < start+rows; i++ {
+		it := items[i]
+		title := it.Title
+		hint_w := lipgloss.Width(it.Hint)
+		max_title := inner_w - hint_w - 3
+		if max_title < 4 {
+			max_title = 4
+		}
+		if r := []rune(title); len(r) > max_title {
+			title = string(r[:max_title-1]) + "~"
+		}
+		pad := inner_w - lipgloss.Width(title) - hint_w - 2
+		if pad < 1 {
+			pad = 1
+		}
+
+		if i == cursor {
+			line := " " + title + strings.Repeat(" ", pad) + it.Hint
+			lines = append(lines, lipgloss.NewStyle().
+				Background(SelectedBgColor).
+				Foreground(SelectedFgColor).
+				Reverse(ReverseSelection).
+				Bold(true).
+				Width(inner_w).
+				MaxHeight(1).
+				Render(line))
+			continue
+		}
+		hint := lipgloss.NewStyle().Foreground(DimTextColor).Render(it.Hint)
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(inner_w).Render(" "+title+strings.Repeat(" ", pad)+hint))
+	}
+
+	rendered := style.Render(strings.Join(lines, "\n"))
+	return inject_title(rendered, title_rendered)
+}
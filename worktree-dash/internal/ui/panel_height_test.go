@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/elvisnm/wt/internal/worktree"
@@ -113,6 +115,25 @@ func TestNotifyPickerExactHeight(t *testing.T) {
 	}
 }
 
+// TestNotifyStepsExactHeight verifies the step list matches expected height,
+// with details long enough to need truncating.
+func TestNotifyStepsExactHeight(t *testing.T) {
+	steps := []Step{
+		{Label: "Fetch origin/main", State: StepSkipped, Detail: "fatal: unable to access 'https://example.com/repo.git/'"},
+		{Label: "Add worktree", State: StepDone, Detail: "/Users/dev/code/app-worktrees/feat-login"},
+		{Label: "Set up Docker", State: StepRunning},
+		{Label: "Seed database", State: StepPending},
+	}
+	h := NotifyHeight(NotifySteps, len(steps)) // 6
+	for _, w := range []int{30, 38, 44, 60} {
+		rendered := RenderNotifySteps("Creating feat/login", steps, w)
+		lines := strings.Count(rendered, "\n") + 1
+		if lines != h {
+			t.Errorf("RenderNotifySteps(%d): rendered %d lines, want %d", w, lines, h)
+		}
+	}
+}
+
 // TestDetailsPanelExactHeight verifies the details panel never exceeds its allocated height.
 // This is the specific regression test for the off-by-one bug where detail_line wrapping
 // caused the panel to render 1 extra line.
@@ -168,7 +189,7 @@ func TestJoinVerticalTotalHeight(t *testing.T) {
 
 				notify := RenderNotifyIdle(w)
 				tabs := RenderTabsPanel(nil, -1, w, l.TabsHeight, false)
-				wt := RenderWorktreePanel(wts, 0, w, l.WorktreeHeight, true, nil)
+				wt := RenderWorktreePanel(wts, 0, w, l.WorktreeHeight, true, nil, WorktreeList{})
 				svc := RenderServicesPanel(nil, 0, w, l.ServicesHeight, false)
 
 				panels := []string{notify, tabs, wt, svc}
@@ -195,3 +216,150 @@ func TestJoinVerticalTotalHeight(t *testing.T) {
 		}
 	}
 }
+
+// TestWorktreePanelFilteredHeight verifies the filter line and group headers
+// fit inside the panel's allocated height.
+func TestWorktreePanelFilteredHeight(t *testing.T) {
+	var wts []worktree.Worktree
+	var groups []string
+	for i := 0; i < 12; i++ {
+		wts = append(wts, worktree.Worktree{Name: fmt.Sprintf("wt-%d", i), Alias: fmt.Sprintf("wt%d", i), Type: worktree.TypeLocal})
+		groups = append(groups, []string{"feat/", "fix/", ""}[i/4])
+	}
+	list := WorktreeList{Groups: groups, Query: "wt", Editing: true, Summary: "running · by name", Total: 20}
+
+	for _, h := range []int{5, 8, 12, 30} {
+		for _, cursor := range []int{0, 6, 11} {
+			out := RenderWorktreePanel(wts, cursor, 40, h, true, nil, list)
+			if got := strings.Count(out, "\n") + 1; got != h {
+				t.Errorf("height %d cursor %d: rendered %d lines", h, cursor, got)
+			}
+			if !strings.Contains(out, "12/20") {
+				t.Errorf("height %d: title should show the filtered count", h)
+			}
+		}
+	}
+
+	out := RenderWorktreePanel(nil, 0, 40, 8, true, nil, WorktreeList{Query: "zzz", Total: 3})
+	if !strings.Contains(out, "No matches") {
+		t.Error("empty filtered list should say No matches")
+	}
+}
+
+// TestWorktreePanelMarks verifies marked rows, the marked count and bulk progress.
+func TestWorktreePanelMarks(t *testing.T) {
+	wts := []worktree.Worktree{
+		{Name: "a", Alias: "alpha", Type: worktree.TypeDocker, Running: true, CPU: "1%", Mem: "10MiB"},
+		{Name: "b", Alias: "beta", Type: worktree.TypeLocal},
+	}
+	list := WorktreeList{
+		Marked:   map[string]bool{"a": true, "b": true},
+		Progress: map[string]string{"a": "stopping"},
+		Total:    2,
+	}
+	out := RenderWorktreePanel(wts, 1, 40, 8, true, nil, list)
+	if !strings.Contains(out, "2 marked") {
+		t.Error("title should show the marked count")
+	}
+	if !strings.Contains(out, "stopping") || strings.Contains(out, "10MiB") {
+		t.Error("bulk progress should replace the stats column")
+	}
+	if !strings.Contains(out, "*◇ beta") {
+		t.Error("marked rows should carry a mark")
+	}
+}
+
+// TestWorktreePanelPinsAndNotes verifies pinned rows carry a star and notes
+// fill the gap before the stats, truncated to fit.
+func TestWorktreePanelPinsAndNotes(t *testing.T) {
+	wts := []worktree.Worktree{
+		{Name: "a", Alias: "alpha", Type: worktree.TypeLocal, Pinned: true, Note: "waiting on design review"},
+		{Name: "b", Alias: "beta", Type: worktree.TypeLocal},
+	}
+	out := RenderWorktreePanel(wts, 1, 40, 6, true, nil, WorktreeList{Groups: []string{PinnedGroup, ""}, Total: 2})
+	if !strings.Contains(out, "★") || !strings.Contains(out, "pinned") {
+		t.Error("pinned row should have a star and a pinned header")
+	}
+	if !strings.Contains(out, "waiting on") || strings.Contains(out, "design review") {
+		t.Error("note should show, truncated to the row width")
+	}
+	for _, w := range []int{30, 40, 60} {
+		for _, line := range strings.Split(RenderWorktreePanel(wts, 0, w, 6, true, nil, WorktreeList{}), "\n") {
+			if got := lipgloss.Width(line); got != w {
+				t.Errorf("width %d: line is %d wide: %q", w, got, line)
+			}
+		}
+	}
+
+	det := RenderDetailsPanel(&wts[0], 40, 12, 0, 0, false, nil)
+	if !strings.Contains(det, "Pinned") || !strings.Contains(det, "Note") {
+		t.Error("details should show the pin and note")
+	}
+}
+
+func TestWorktreePanelGitBadges(t *testing.T) {
+	git := worktree.GitStatus{Staged: 1, Untracked: 2, Upstream: "origin/feat/a", Ahead: 3, Behind: 1,
+		Subject: "Fix login redirect", Author: "Sam", CommitTime: time.Now().Add(-2 * time.Hour), Fetched: time.Now()}
+	wts := []worktree.Worktree{
+		{Name: "a", Alias: "alpha", Type: worktree.TypeLocal, Git: git},
+		{Name: "b", Alias: "beta", Type: worktree.TypeLocal, Git: worktree.GitStatus{Fetched: time.Now()}},
+	}
+	if out := RenderWorktreePanel(wts, 1, 40, 6, true, nil, WorktreeList{}); !strings.Contains(out, "alpha ±3 ↑3 ↓1") {
+		t.Errorf("row should show the badges after the name:\n%s", out)
+	}
+	if out := RenderWorktreePanel(wts, 1, 16, 6, true, nil, WorktreeList{}); strings.Contains(out, "±") {
+		t.Errorf("badges should give way to the name when narrow:\n%s", out)
+	}
+	for _, w := range []int{16, 24, 40} {
+		out := RenderWorktreePanel(wts, 1, w, 6, true, nil, WorktreeList{})
+		for _, line := range strings.Split(out, "\n") {
+			if got := lipgloss.Width(line); got != w {
+				t.Errorf("width %d: line is %d wide: %q", w, got, line)
+			}
+		}
+	}
+
+	det := RenderDetailsPanel(&wts[0], 50, 30, 0, 0, false, nil)
+	for _, want := range []string{"1 staged, 2 untracked", "origin/feat/a", "↑3", "Fix login redirect", "Sam · 2h ago"} {
+		if !strings.Contains(det, want) {
+			t.Errorf("details missing %q:\n%s", want, det)
+		}
+	}
+	if det := RenderDetailsPanel(&wts[1], 50, 30, 0, 0, false, nil); !strings.Contains(det, "clean") {
+		t.Error("a clean worktree should say so")
+	}
+}
+
+func TestWorktreePanelOverlaps(t *testing.T) {
+	wt := worktree.Worktree{Name: "a", Alias: "alpha", Type: worktree.TypeLocal, Git: worktree.GitStatus{Fetched: time.Now()},
+		Overlaps: []worktree.Overlap{
+			{Path: "src/api/orders.ts", With: []string{"search"}},
+			{Path: "src/db.ts", With: []string{"billing", "search"}},
+			{Path: "src/api/users.ts", With: []string{"search"}},
+		}}
+	if out := RenderWorktreePanel([]worktree.Worktree{wt}, 0, 40, 4, true, nil, WorktreeList{}); !strings.Contains(out, "alpha ∩3") {
+		t.Errorf("row should show the overlap badge:\n%s", out)
+	}
+	det := RenderDetailsPanel(&wt, 80, 30, 0, 0, false, nil)
+	for _, want := range []string{"Overlaps", "also modified in search: src/api/orders.ts, src/api/users.ts",
+		"also modified in billing, search: src/db.ts"} {
+		if !strings.Contains(det, want) {
+			t.Errorf("details missing %q:\n%s", want, det)
+		}
+	}
+}
+
+func TestWorktreePanelGitStates(t *testing.T) {
+	wt := worktree.Worktree{Name: "s", Alias: "spike", Type: worktree.TypeLocal, Branch: "3f2a9c1d",
+		Operation: "rebase", Locked: true, LockReason: "demo", Detached: true, Outside: true}
+	out := RenderWorktreePanel([]worktree.Worktree{wt}, 0, 60, 4, true, nil, WorktreeList{})
+	if !strings.Contains(out, "spike rebase locked detached ext") {
+		t.Errorf("row should show the git states:\n%s", out)
+	}
+	det := RenderDetailsPanel(&wt, 50, 20, 0, 0, false, nil)
+	for _, want := range []string{"rebase in progress", "demo", "detached", "outside the worktrees dir"} {
+		if !strings.Contains(det, want) {
+			t.Errorf("details missing %q:\n%s", want, det)
+		}
+	}
+}
@@ -22,37 +22,9 @@ func FormatPickerLabel(a PickerAction) string {
 	return fmt.Sprintf("[%s] %s — %s", a.Key, a.Label, a.Desc)
 }
 
-// Shared actions reused across multiple picker slices.
-var (
-	actionClaude           = PickerAction{Key: "c", Label: labels.Claude, Desc: "Claude Code"}
-	ActionClaudeAuto       = PickerAction{Key: "C", Label: labels.Claude + " (Auto)", Desc: "Claude Code — auto mode"}
-	actionRemove           = PickerAction{Key: "x", Label: labels.Remove, Desc: "Remove worktree"}
-	actionZsh              = PickerAction{Key: "z", Label: labels.Zsh, Desc: "Host shell"}
-	actionContainerShell   = PickerAction{Key: "b", Label: labels.Shell, Desc: "Container shell"}
-	actionLocalShell       = PickerAction{Key: "b", Label: labels.Shell, Desc: "Worktree shell"}
-	actionContainerRestart = PickerAction{Key: "r", Label: "Restart", Desc: "Restart container"}
-	actionContainerStop    = PickerAction{Key: "t", Label: "Stop", Desc: "Stop container"}
-	actionInfo             = PickerAction{Key: "i", Label: "Info", Desc: "Worktree info"}
-	actionPull             = PickerAction{Key: "g", Label: labels.Pull, Desc: "Pull latest changes"}
-)
-
-var WorktreeActions = []PickerAction{
-	actionContainerShell,
-	actionZsh,
-	actionClaude,
-	actionPull,
-	actionContainerRestart,
-	actionContainerStop,
-	actionRemove,
-}
-
-var StoppedActions = []PickerAction{
-	{Key: "u", Label: "Start", Desc: "Start container"},
-	actionZsh,
-	actionClaude,
-	actionPull,
-	actionRemove,
-}
+// ActionClaudeAuto is offered after Claude when auto mode is off.
+// Worktree actions themselves are defined in the app's action registry.
+var ActionClaudeAuto = PickerAction{Key: "C", Label: labels.Claude + " (Auto)", Desc: "Claude Code — auto mode"}
 
 var DatabaseActions = []PickerAction{
 	{Key: "s", Label: "Seed", Desc: "Copy shared → worktree db"},
@@ -65,38 +37,8 @@ var MaintenanceActions = []PickerAction{
 	{Key: "p", Label: "Prune", Desc: "Remove orphaned volumes"},
 	{Key: "s", Label: "Autostop", Desc: "Stop idle containers"},
 	{Key: "r", Label: "Rebuild", Desc: "Rebuild base image"},
-}
-
-var actionSwitchMode = PickerAction{Key: "m", Label: "Switch mode", Desc: "Toggle minimal/full"}
-
-var LocalActions = []PickerAction{
-	{Key: "u", Label: "Start", Desc: "Start dev server"},
-	actionLocalShell,
-	actionClaude,
-	actionPull,
-	actionSwitchMode,
-	{Key: "n", Label: labels.Create, Desc: "Create container"},
-	actionInfo,
-	actionRemove,
-}
-
-var HostBuildRunningActions = []PickerAction{
-	{Key: "e", Label: labels.Build, Desc: "Esbuild watch"},
-	actionContainerShell,
-	actionZsh,
-	actionClaude,
-	actionPull,
-	actionContainerRestart,
-	actionContainerStop,
-	actionRemove,
-}
-
-var HostBuildStoppedActions = []PickerAction{
-	{Key: "u", Label: "Start + Build", Desc: "Container + esbuild"},
-	actionZsh,
-	actionClaude,
-	actionPull,
-	actionRemove,
+	{Key: "c", Label: "Cleanup", Desc: "Merged and stale worktrees"},
+	{Key: "o", Label: "Overlaps", Desc: "Files changed in more than one worktree"},
 }
 
 var SplitSessionActions = []PickerAction{
@@ -116,20 +58,6 @@ var RemoveActions = []PickerAction{
 	{Key: "f", Label: "Force", Desc: "Even if dirty"},
 }
 
-var LocalRunningActions = []PickerAction{
-	actionLocalShell,
-	actionClaude,
-	actionPull,
-	{Key: "l", Label: labels.Logs, Desc: "Dev logs"},
-	{Key: "o", Label: "Start service", Desc: "Start a stopped service"},
-	{Key: "p", Label: "Stop service", Desc: "Stop a running service"},
-	actionSwitchMode,
-	{Key: "r", Label: "Restart", Desc: "Restart services"},
-	actionInfo,
-	{Key: "t", Label: "Stop", Desc: "Stop dev server"},
-	actionRemove,
-}
-
 // FilterDatabaseActions returns DatabaseActions filtered by config feature flags.
 // When cfg is nil, returns the full list.
 func FilterDatabaseActions(cfg *config.Config) []PickerAction {
@@ -199,7 +127,8 @@ func RenderPicker(actions []PickerAction, cursor int, width, height int, title s
 			line := fmt.Sprintf(" %s %s %s", key_plain, label_plain, a.Desc)
 			line = lipgloss.NewStyle().
 				Background(SelectedBgColor).
-				Foreground(lipgloss.Color("255")).
+				Foreground(SelectedFgColor).
+				Reverse(ReverseSelection).
 				Bold(true).
 				Width(inner_w).
 				MaxHeight(1).
@@ -32,3 +32,15 @@ func TestPickerRender(t *testing.T) {
 	}
 }
 
+func TestPaletteRenderHeight(t *testing.T) {
+	var items []PaletteItem
+	for i := 0; i < PaletteMaxRows+5; i++ {
+		items = append(items, PaletteItem{Title: "Restart — feat-login", Hint: "Restart container"})
+	}
+	for _, n := range []int{0, 3, len(items)} {
+		result := RenderNotifyPalette("rest", items[:n], n-1, 50)
+		if got, want := lipgloss.Height(result), NotifyHeight(NotifyPalette, n); got != want {
+			t.Errorf("%d items: height %d, want %d", n, got, want)
+		}
+	}
+}
@@ -73,7 +73,8 @@ func format_service_line(svc worktree.Service, width int, selected bool, panel_f
 		return lipgloss.NewStyle().
 			Bold(true).
 			Background(SelectedBgColor).
-			Foreground(lipgloss.Color("255")).
+			Foreground(SelectedFgColor).
+			Reverse(ReverseSelection).
 			Width(width).
 			Render(line)
 	}
@@ -17,7 +17,7 @@ func RenderInputBar(width int, prompt string, value string) string {
 		Foreground(FocusBorderColor)
 
 	cursor := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("255")).
+		Foreground(TextColor).
 		Background(FocusBorderColor).
 		Render(" ")
 
@@ -34,7 +34,7 @@ func RenderInputBar(width int, prompt string, value string) string {
 
 func RenderResultBar(width int, result string) string {
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("252"))
+		Foreground(SoftTextColor)
 
 	return lipgloss.NewStyle().
 		Width(width).
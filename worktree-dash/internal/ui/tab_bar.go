@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
@@ -15,6 +16,9 @@ type TabInfo struct {
 	Active       bool
 	Alive        bool
 	Idle         bool     // agent is waiting for input
+	Recording    bool     // pane output is being recorded
+	Status       string   // right-aligned status (broadcast result, "sync")
+	Exit         string   // exit summary once dead (see ExitSummary); "dead" if empty
 	IsGroupHead  bool     // group header line (multi-session groups)
 	IsGroupChild bool     // session entry within a group
 	GroupSize    int      // total sessions in this group
@@ -84,15 +88,47 @@ func RenderTabsPanel(tabs []TabInfo, cursor int, width, height int, focused bool
 	return inject_title(styled, title)
 }
 
+// ExitSummary renders a finished command's outcome: "✓ 12s", "✗ 1 · 3m", or "dead"
+// when the exit code is unknown.
+func ExitSummary(exit_code int, runtime time.Duration) string {
+	if exit_code < 0 {
+		return "dead"
+	}
+	d := short_duration(runtime)
+	if exit_code == 0 {
+		return "✓ " + d
+	}
+	return fmt.Sprintf("✗ %d · %s", exit_code, d)
+}
+
+// short_duration formats d with its largest unit: "12s", "3m", "1h4m".
+func short_duration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
 func format_tab_line(tab TabInfo, width int, pos int, selected bool, panel_focused bool) string {
 	name := tab.Label
 
 	var right string
 	if !tab.Alive && !tab.IsGroupHead {
-		right = "dead"
+		right = tab.Exit
+		if right == "" {
+			right = "dead"
+		}
+	} else if tab.Status != "" {
+		right = tab.Status
+	} else if tab.Recording {
+		right = "rec"
 	}
 
-	right_w := len(right)
+	right_w := lipgloss.Width(right)
 
 	// Determine prefix based on entry type
 	var prefix string
@@ -137,7 +173,8 @@ func format_tab_line(tab TabInfo, width int, pos int, selected bool, panel_focus
 		return lipgloss.NewStyle().
 			Bold(true).
 			Background(SelectedBgColor).
-			Foreground(lipgloss.Color("255")).
+			Foreground(SelectedFgColor).
+			Reverse(ReverseSelection).
 			Width(width).
 			MaxHeight(1).
 			Render(line)
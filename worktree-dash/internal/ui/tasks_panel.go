@@ -65,7 +65,8 @@ func render_task_list_panel(tasks []beads.Task, cursor int, width, height int, f
 				line := fmt.Sprintf(" %s %s %s %s", pri, typ, id, task_title)
 				line = lipgloss.NewStyle().
 					Background(SelectedBgColor).
-					Foreground(lipgloss.Color("255")).
+					Foreground(SelectedFgColor).
+					Reverse(ReverseSelection).
 					Bold(true).
 					Width(inner_w).
 					Render(line)
@@ -127,7 +128,7 @@ func build_task_detail_lines(task *beads.Task, wrap_w int) []string {
 	var lines []string
 
 	// Title
-	title_style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("255"))
+	title_style := lipgloss.NewStyle().Bold(true).Foreground(TextColor)
 	lines = append(lines, " "+title_style.Render(word_wrap(task.Title, wrap_w)))
 
 	// Metadata
@@ -154,7 +155,7 @@ func build_task_detail_lines(task *beads.Task, wrap_w int) []string {
 
 	// Description
 	if task.Description != "" {
-		label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+		label := lipgloss.NewStyle().Bold(true).Foreground(SoftTextColor)
 		lines = append(lines, " "+label.Render("Description"))
 		for _, para := range strings.Split(task.Description, "\n") {
 			if para == "" {
@@ -171,7 +172,7 @@ func build_task_detail_lines(task *beads.Task, wrap_w int) []string {
 
 	// Labels
 	if len(task.Labels) > 0 {
-		label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+		label := lipgloss.NewStyle().Bold(true).Foreground(SoftTextColor)
 		lines = append(lines, " "+label.Render("Labels"))
 		for _, l := range task.Labels {
 			lines = append(lines, "  "+lipgloss.NewStyle().Foreground(DimTextColor).Render(l))
@@ -181,7 +182,7 @@ func build_task_detail_lines(task *beads.Task, wrap_w int) []string {
 
 	// Dependencies
 	if len(task.Dependencies) > 0 {
-		label := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("252"))
+		label := lipgloss.NewStyle().Bold(true).Foreground(SoftTextColor)
 		lines = append(lines, " "+label.Render("Dependencies"))
 		for _, dep := range task.Dependencies {
 			dep_line := fmt.Sprintf("  %s %s (%s)",
@@ -211,11 +212,11 @@ func task_detail_lines(task *beads.Task) int {
 func priority_icon(p int) string {
 	switch {
 	case p <= 0:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("!!")
+		return lipgloss.NewStyle().Foreground(ErrorColor).Render("!!")
 	case p == 1:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Render("! ")
+		return lipgloss.NewStyle().Foreground(WarningColor).Render("! ")
 	case p == 2:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("- ")
+		return lipgloss.NewStyle().Foreground(HintColor).Render("- ")
 	case p == 3:
 		return lipgloss.NewStyle().Foreground(DimTextColor).Render(". ")
 	default:
@@ -226,7 +227,7 @@ func priority_icon(p int) string {
 func type_tag(t string) string {
 	switch t {
 	case "bug":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("bug")
+		return lipgloss.NewStyle().Foreground(ErrorColor).Render("bug")
 	case "feature":
 		return lipgloss.NewStyle().Foreground(RunningColor).Render("feat")
 	default:
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"github.com/elvisnm/wt/internal/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ApplyTheme sets the palette from t. Under NO_COLOR lipgloss drops colors on
+// its own, so cursor rows switch to reverse video to stay visible.
+func ApplyTheme(t theme.Theme) {
+	BorderColor = lipgloss.Color(t.Border)
+	FocusBorderColor = lipgloss.Color(t.Focus)
+	DimTextColor = lipgloss.Color(t.DimText)
+	HighlightColor = lipgloss.Color(t.Highlight)
+	SelectedBgColor = lipgloss.Color(t.SelectedBg)
+	SelectedFgColor = lipgloss.Color(t.SelectedFg)
+	RunningColor = lipgloss.Color(t.Running)
+	StoppedColor = lipgloss.Color(t.Stopped)
+	StartingColor = lipgloss.Color(t.Starting)
+	HeaderColor = lipgloss.Color(t.Header)
+	HintColor = lipgloss.Color(t.Hint)
+	TextColor = lipgloss.Color(t.Text)
+	SoftTextColor = lipgloss.Color(t.SoftText)
+	MutedColor = lipgloss.Color(t.Muted)
+	ErrorColor = lipgloss.Color(t.Error)
+	WarningColor = lipgloss.Color(t.Warning)
+	ReverseSelection = theme.NoColor()
+}
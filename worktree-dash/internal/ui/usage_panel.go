@@ -75,7 +75,7 @@ func render_bar(pct float64, width int) string {
 	var color lipgloss.Color
 	switch {
 	case pct >= 80:
-		color = lipgloss.Color("196") // bright red (distinct from StoppedColor)
+		color = ErrorColor // bright red (distinct from StoppedColor)
 	case pct >= 50:
 		color = StartingColor
 	default:
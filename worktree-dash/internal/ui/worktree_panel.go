@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"unicode/utf8"
 
@@ -11,40 +12,137 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-func RenderWorktreePanel(worktrees []worktree.Worktree, cursor int, width, height int, focused bool, cfg *config.Config) string {
-	title := TitleStyle(focused).Render(" w - Worktrees ")
+// PinnedGroup is the group label of pinned worktrees, listed above the branch groups.
+const PinnedGroup = "pinned"
+
+// WorktreeList describes how the worktree list was narrowed and grouped.
+type WorktreeList struct {
+	Groups  []string // group label per worktree; a header is drawn where it changes
+	Query   string   // search text
+	Editing bool     // the search text is being typed
+	Summary string   // active quick filters and sort ("running · name")
+	Total   int      // worktrees before filtering
+
+	Marked   map[string]bool   // worktrees marked for a bulk action, by name
+	Progress map[string]string // bulk action state shown instead of stats, by name
+	Expanded map[string]bool   // worktree sets listing their members, by name
+}
+
+func RenderWorktreePanel(worktrees []worktree.Worktree, cursor int, width, height int, focused bool, cfg *config.Config, list WorktreeList) string {
+	title_text := " w - Worktrees "
+	if len(worktrees) < list.Total {
+		title_text = fmt.Sprintf(" w - Worktrees %d/%d ", len(worktrees), list.Total)
+	}
+	if n := len(list.Marked); n > 0 {
+		title_text = fmt.Sprintf("%s· %d marked ", title_text, n)
+	}
+	title := TitleStyle(focused).Render(title_text)
 	style := PanelStyle(width, height, focused)
 
 	inner_w := width - 4
 	inner_h := height - 2 // border
 
+	var header []string
+	if list.Editing || list.Query != "" || list.Summary != "" {
+		header = append(header, format_filter_line(list, inner_w))
+	}
+
 	var lines []string
+	cursor_line := 0
+	group := ""
 	for i, wt := range worktrees {
-		line := format_worktree_line(wt, inner_w, i == cursor, focused, cfg)
+		if i < len(list.Groups) && (i == 0 || list.Groups[i] != group) {
+			group = list.Groups[i]
+			lines = append(lines, format_group_line(group, inner_w))
+		}
+		if i == cursor {
+			cursor_line = len(lines)
+		}
+		line := format_worktree_line(wt, inner_w, i == cursor, focused, cfg, list.Marked[wt.Name], list.Progress[wt.Name])
 		lines = append(lines, line)
+		if list.Expanded[wt.Name] {
+			lines = append(lines, format_set_lines(wt.Set, inner_w)...)
+		}
+	}
+	if len(worktrees) == 0 && list.Total > 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(DimTextColor).Render(" No matches"))
 	}
 
+	list_h := inner_h - len(header)
+	if list_h < 1 {
+		list_h = 1
+	}
 	total := len(lines)
-	start, end := visible_window(total, cursor, inner_h)
-	lines = lines[start:end]
+	start, end := visible_window(total, cursor_line, list_h)
+	lines = append(header, lines[start:end]...)
 
 	content := strings.Join(lines, "\n")
 
 	styled := style.Render(content)
-	styled = OverlayScrollbar(styled, total, inner_h, start, focused)
+	styled = OverlayScrollbar(styled, total, list_h, start, focused)
 	styled = inject_title(styled, title)
 
 	return styled
 }
 
-func format_worktree_line(wt worktree.Worktree, width int, selected bool, panel_focused bool, cfg *config.Config) string {
+// format_filter_line renders the search text and active filters above the list.
+func format_filter_line(list WorktreeList, width int) string {
+	query := list.Query
+	if list.Editing {
+		query += "█"
+	}
+	left := " /" + query
+	right := list.Summary
+	if right != "" {
+		right += " "
+	}
+	pad := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if pad < 1 {
+		right = ""
+		pad = width - utf8.RuneCountInString(left)
+	}
+	if pad < 0 {
+		pad = 0
+	}
+	query_style := lipgloss.NewStyle().Foreground(HintColor)
+	if !list.Editing && list.Query == "" {
+		query_style = lipgloss.NewStyle().Foreground(DimTextColor)
+	}
+	return query_style.Render(left) + strings.Repeat(" ", pad) + lipgloss.NewStyle().Foreground(DimTextColor).Render(right)
+}
+
+// format_group_line renders a branch prefix header. Worktrees that match no
+// prefix are grouped under "other".
+func format_group_line(group string, width int) string {
+	if group == "" {
+		group = "other"
+	}
+	label := " " + group + " "
+	fill := width - utf8.RuneCountInString(label) - 1
+	if fill < 0 {
+		fill = 0
+	}
+	return lipgloss.NewStyle().Foreground(HeaderColor).Render("─" + label + strings.Repeat("─", fill))
+}
+
+func format_worktree_line(wt worktree.Worktree, width int, selected bool, panel_focused bool, cfg *config.Config, marked bool, progress string) string {
 	name := wt.Alias
 	if name == "" {
 		name = wt.Name
 	}
+	mark := " "
+	if marked {
+		mark = "*"
+	}
+	pin := ""
+	if wt.Pinned {
+		pin = "★ "
+	}
 
 	var right string
-	if strings.HasSuffix(wt.Health, "...") && !wt.Running {
+	if progress != "" {
+		right = progress
+	} else if strings.HasSuffix(wt.Health, "...") && !wt.Running {
 		right = strings.TrimSuffix(wt.Health, "...")
 	} else if wt.Running && wt.Type == worktree.TypeLocal {
 		right = "dev"
@@ -61,10 +159,19 @@ func format_worktree_line(wt worktree.Worktree, width int, selected bool, panel_
 		right = "local"
 	}
 
+	badges := git_badges(wt, false)
+	if badges != "" {
+		badges = " " + badges
+	}
+
 	status := status_indicator_plain(wt)
 	right_w := len(right)
 	// 4 = " " + status + " " + min 1 pad; +1 trailing space
-	max_name := width - right_w - 5
+	max_name := width - right_w - 5 - len([]rune(pin))
+	if max_name-lipgloss.Width(badges) < 4 {
+		badges = "" // too narrow: the name comes first
+	}
+	max_name -= lipgloss.Width(badges)
 	if max_name < 4 {
 		max_name = 4
 	}
@@ -72,19 +179,20 @@ func format_worktree_line(wt worktree.Worktree, width int, selected bool, panel_
 		runes := []rune(name)
 		name = string(runes[:max_name-1]) + "~"
 	}
-	label := fmt.Sprintf(" %s %s", status, name)
+	label := fmt.Sprintf("%s%s %s%s%s", mark, status, pin, name, badges)
 	pad := width - lipgloss.Width(label) - right_w - 1
 	if pad < 1 {
 		pad = 1
 	}
 
-	line := label + strings.Repeat(" ", pad) + right + " "
+	line := label + format_note(wt.Note, pad, lipgloss.NewStyle()) + right + " "
 
 	if selected && panel_focused {
 		return lipgloss.NewStyle().
 			Bold(true).
 			Background(SelectedBgColor).
-			Foreground(lipgloss.Color("255")).
+			Foreground(SelectedFgColor).
+			Reverse(ReverseSelection).
 			Width(width).
 			Render(line)
 	}
@@ -98,15 +206,116 @@ func format_worktree_line(wt worktree.Worktree, width int, selected bool, panel_
 
 	// Color the status indicator for non-selected lines
 	colored_status := status_indicator(wt)
-	label = fmt.Sprintf(" %s %s", colored_status, name)
+	if marked {
+		mark = lipgloss.NewStyle().Foreground(HintColor).Bold(true).Render(mark)
+	}
+	if pin != "" {
+		pin = lipgloss.NewStyle().Foreground(HintColor).Render("★") + " "
+	}
+	if badges != "" {
+		badges = " " + git_badges(wt, true)
+	}
+	label = fmt.Sprintf("%s%s %s%s%s", mark, colored_status, pin, name, badges)
 	pad = width - lipgloss.Width(label) - right_w - 1
 	if pad < 1 {
 		pad = 1
 	}
-	line = label + strings.Repeat(" ", pad) + right + " "
+	line = label + format_note(wt.Note, pad, lipgloss.NewStyle().Foreground(DimTextColor)) + right + " "
 	return lipgloss.NewStyle().Width(width).Render(line)
 }
 
+// git_badges returns a worktree's git state in short: an operation in
+// progress, "conflict" (a sync with the base ref was aborted), "prunable", "locked", "detached", "ext" (outside the worktrees
+// dir), then "±N" changed files, "↑N" unpushed commits, "↓N" commits
+// behind upstream and "∩N" files other worktrees change too. Empty for a
+// clean worktree on a branch, in sync.
+func git_badges(wt worktree.Worktree, colored bool) string {
+	type badge struct {
+		text  string
+		color lipgloss.Color
+	}
+	var badges []badge
+	if wt.Operation != "" {
+		badges = append(badges, badge{wt.Operation, ErrorColor})
+	}
+	if wt.SyncConflict != "" {
+		badges = append(badges, badge{"conflict", ErrorColor})
+	}
+	if wt.Prunable {
+		badges = append(badges, badge{"prunable", ErrorColor})
+	}
+	if wt.Locked {
+		badges = append(badges, badge{"locked", HintColor})
+	}
+	if wt.Detached {
+		badges = append(badges, badge{"detached", MutedColor})
+	}
+	if wt.Outside {
+		badges = append(badges, badge{"ext", MutedColor})
+	}
+	if wt.SparseProfile != "" {
+		badges = append(badges, badge{"⊂" + wt.SparseProfile, MutedColor})
+	}
+	s := wt.Git
+	if n := s.Changes(); n > 0 {
+		badges = append(badges, badge{fmt.Sprintf("±%d", n), WarningColor})
+	}
+	if n := s.Unpushed(); n > 0 {
+		badges = append(badges, badge{fmt.Sprintf("↑%d", n), HintColor})
+	}
+	if s.Behind > 0 {
+		badges = append(badges, badge{fmt.Sprintf("↓%d", s.Behind), MutedColor})
+	}
+	if n := len(wt.Overlaps); n > 0 {
+		badges = append(badges, badge{fmt.Sprintf("∩%d", n), WarningColor})
+	}
+	if len(wt.Set) > 0 {
+		badges = append(badges, badge{"+" + strings.Join(wt.SetNames(), "+"), HintColor})
+	}
+	parts := make([]string, len(badges))
+	for i, b := range badges {
+		parts[i] = b.text
+		if colored {
+			parts[i] = lipgloss.NewStyle().Foreground(b.color).Render(b.text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// format_set_lines renders an expanded worktree set's members under its
+// row: the related repo and the worktree's directory.
+func format_set_lines(set []worktree.SetMember, width int) []string {
+	lines := make([]string, len(set))
+	for i, s := range set {
+		branch := "├ "
+		if i == len(set)-1 {
+			branch = "└ "
+		}
+		repo := lipgloss.NewStyle().Foreground(HintColor).Render(s.Repo)
+		room := width - 5 - utf8.RuneCountInString(s.Repo) - 2
+		dir := ""
+		if room >= 4 {
+			dir = "  " + lipgloss.NewStyle().Foreground(DimTextColor).Render(truncate(filepath.Base(s.Path), room))
+		}
+		lines[i] = lipgloss.NewStyle().Foreground(DimTextColor).Render("   "+branch) + repo + dir
+	}
+	return lines
+}
+
+// format_note fills the gap between a worktree's name and its stats, showing
+// as much of the note as fits. Notes only show when at least 4 characters fit.
+func format_note(note string, gap int, style lipgloss.Style) string {
+	avail := gap - 2 // a space on either side
+	if note == "" || avail < 4 {
+		return strings.Repeat(" ", gap)
+	}
+	runes := []rune(strings.Join(strings.Fields(note), " "))
+	if len(runes) > avail {
+		runes = append(runes[:avail-1], '~')
+	}
+	return " " + style.Render(string(runes)) + strings.Repeat(" ", gap-1-len(runes))
+}
+
 func status_indicator(wt worktree.Worktree) string {
 	switch {
 	case strings.HasSuffix(wt.Health, "..."):
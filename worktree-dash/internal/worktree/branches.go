@@ -0,0 +1,58 @@
+package worktree
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Branch is a local or remote-tracking branch a worktree can be created from.
+type Branch struct {
+	Name       string // "feat/login", or "origin/feat/login" for a remote branch
+	Remote     bool
+	CommitTime time.Time
+	Subject    string
+}
+
+// ListBranches lists the repo's local and remote-tracking branches, most
+// recently committed first.
+func ListBranches(repo_root string) ([]Branch, error) {
+	out, err := exec.Command("git", "-C", repo_root, "for-each-ref", "--sort=-committerdate",
+		"--format=%(refname)%1f%(committerdate:unix)%1f%(subject)", "refs/heads", "refs/remotes").Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseBranches(string(out)), nil
+}
+
+// ParseBranches parses ListBranches' for-each-ref output. Symbolic remote
+// HEADs (origin/HEAD) are skipped.
+func ParseBranches(out string) []Branch {
+	var result []Branch
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\x1f", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		var b Branch
+		switch {
+		case strings.HasPrefix(parts[0], "refs/heads/"):
+			b.Name = strings.TrimPrefix(parts[0], "refs/heads/")
+		case strings.HasPrefix(parts[0], "refs/remotes/"):
+			b.Name = strings.TrimPrefix(parts[0], "refs/remotes/")
+			b.Remote = true
+			if strings.HasSuffix(b.Name, "/HEAD") {
+				continue
+			}
+		default:
+			continue
+		}
+		if secs, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+			b.CommitTime = time.Unix(secs, 0)
+		}
+		b.Subject = parts[2]
+		result = append(result, b)
+	}
+	return result
+}
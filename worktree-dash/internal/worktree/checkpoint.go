@@ -0,0 +1,269 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// checkpointRefs is where a worktree's checkpoints live, one numbered ref per
+// checkpoint under its alias. Refs outside refs/heads and refs/tags stay out
+// of branch lists, logs and pushes.
+const checkpointRefs = "refs/wt/checkpoints/"
+
+// Checkpoint is a snapshot of a worktree's files, untracked ones included,
+// kept as a commit on a hidden ref. Its parent is HEAD when it was taken.
+type Checkpoint struct {
+	N       int
+	Ref     string
+	SHA     string
+	Parent  string // "" on a branch without commits
+	Date    time.Time
+	Message string
+
+	// What changed since the previous checkpoint, or since Parent for the first
+	From                  string
+	Files, Added, Deleted int
+}
+
+// Short returns the abbreviated SHA.
+func (c Checkpoint) Short() string {
+	if len(c.SHA) > 7 {
+		return c.SHA[:7]
+	}
+	return c.SHA
+}
+
+// checkpoint_git runs git in worktree_path with extra environment, returning
+// its trimmed output, or the last line of it as the error.
+func checkpoint_git(worktree_path string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", worktree_path}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		lines := strings.Split(text, "\n")
+		return "", fmt.Errorf("git %s: %s", args[0], lines[len(lines)-1])
+	}
+	return text, nil
+}
+
+// CreateCheckpoint snapshots the working tree of worktree_path as the next
+// checkpoint of alias. It stages into a copy of the index, so HEAD, the
+// branch, the index and the stash list are left alone. Ignored files aren't
+// included.
+func CreateCheckpoint(worktree_path, alias, message string) (Checkpoint, error) {
+	index, err := checkpoint_git(worktree_path, nil, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	if !filepath.IsAbs(index) {
+		index = filepath.Join(worktree_path, index)
+	}
+	tmp, err := os.MkdirTemp("", "wt-checkpoint-")
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	defer os.RemoveAll(tmp)
+	// Starting from the real index keeps its stat cache, so unchanged files aren't rehashed
+	tmp_index := filepath.Join(tmp, "index")
+	if data, err := os.ReadFile(index); err == nil {
+		if err := os.WriteFile(tmp_index, data, 0644); err != nil {
+			return Checkpoint{}, err
+		}
+	}
+	env := []string{"GIT_INDEX_FILE=" + tmp_index}
+	if _, err := checkpoint_git(worktree_path, env, "add", "-A"); err != nil {
+		return Checkpoint{}, err
+	}
+	tree, err := checkpoint_git(worktree_path, env, "write-tree")
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	args := []string{"-c", "user.name=wt", "-c", "user.email=wt@localhost", "commit-tree", tree, "-m", message}
+	parent, _ := checkpoint_git(worktree_path, nil, "rev-parse", "--verify", "-q", "HEAD")
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	sha, err := checkpoint_git(worktree_path, nil, args...)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+
+	existing, err := list_checkpoint_refs(worktree_path, alias)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	n := 1
+	if len(existing) > 0 {
+		n = existing[len(existing)-1].N + 1
+	}
+	c := Checkpoint{N: n, Ref: checkpoint_ref(alias, n), SHA: sha, Parent: parent, Date: time.Now(), Message: message}
+	// The empty old value makes this fail rather than overwrite a checkpoint taken meanwhile
+	if _, err := checkpoint_git(worktree_path, nil, "update-ref", c.Ref, sha, ""); err != nil {
+		return Checkpoint{}, err
+	}
+	return c, nil
+}
+
+func checkpoint_ref(alias string, n int) string {
+	return checkpointRefs + alias + "/" + strconv.Itoa(n)
+}
+
+// list_checkpoint_refs reads alias's checkpoint refs, oldest first, without
+// their diffstats.
+func list_checkpoint_refs(worktree_path, alias string) ([]Checkpoint, error) {
+	prefix := checkpointRefs + alias + "/"
+	out, err := checkpoint_git(worktree_path, nil, "for-each-ref",
+		"--format=%(refname)%00%(objectname)%00%(parent)%00%(committerdate:unix)%00%(contents:subject)", prefix)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCheckpointRefs(out, prefix), nil
+}
+
+// ParseCheckpointRefs parses `git for-each-ref` output of checkpoint refs
+// under prefix, sorted by number. Refs that aren't numbered are skipped.
+func ParseCheckpointRefs(out, prefix string) []Checkpoint {
+	var list []Checkpoint
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) < 5 {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimPrefix(fields[0], prefix))
+		if err != nil || !strings.HasPrefix(fields[0], prefix) {
+			continue
+		}
+		c := Checkpoint{N: n, Ref: fields[0], SHA: fields[1], Parent: fields[2], Message: fields[4]}
+		if ts, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			c.Date = time.Unix(ts, 0)
+		}
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].N < list[j].N })
+	return list
+}
+
+// ListCheckpoints returns alias's checkpoints, newest first, each with the
+// diffstat against the one before it.
+func ListCheckpoints(worktree_path, alias string) ([]Checkpoint, error) {
+	list, err := list_checkpoint_refs(worktree_path, alias)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		c := &list[i]
+		c.From = c.Parent
+		if i > 0 {
+			c.From = list[i-1].SHA
+		}
+		if c.From == "" {
+			c.From = emptyTree
+		}
+		out, err := checkpoint_git(worktree_path, nil, "diff", "-z", "--numstat", "-M", c.From, c.SHA)
+		if err != nil {
+			continue
+		}
+		for _, f := range ParseNumstat(out) {
+			c.Files++
+			c.Added += f.Added
+			c.Deleted += f.Deleted
+		}
+	}
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+	return list, nil
+}
+
+// CheckpointFiles lists the files that differ between two checkpoints (or
+// any two commits).
+func CheckpointFiles(worktree_path, from, to string) ([]FileChange, error) {
+	return diff_files(worktree_path, "diff", from, to)
+}
+
+// CheckpointFileDiff returns the unified diff of one file between two
+// checkpoints.
+func CheckpointFileDiff(worktree_path, from, to string, f FileChange) (string, error) {
+	args := []string{"-C", worktree_path, "diff", "--no-color", "-M", from, to, "--"}
+	if f.OldPath != "" {
+		args = append(args, f.OldPath)
+	}
+	out, err := exec.Command("git", append(args, f.Path)...).Output()
+	return string(out), err
+}
+
+// RestoreCheckpoint puts the files of worktree_path back to checkpoint c:
+// checkpointed files get their content back and files created since are
+// deleted. HEAD, the branch and the index are left alone, so the difference
+// to HEAD shows as uncommitted changes. The current state is checkpointed
+// first and returned, which makes the restore itself undoable.
+func RestoreCheckpoint(worktree_path, alias string, c Checkpoint) (Checkpoint, error) {
+	saved, err := CreateCheckpoint(worktree_path, alias, fmt.Sprintf("Before restoring #%d", c.N))
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	added, err := checkpoint_git(worktree_path, nil, "diff", "-z", "--name-only", "--no-renames", "--diff-filter=A", c.SHA, saved.SHA)
+	if err != nil {
+		return saved, err
+	}
+	for _, path := range strings.Split(added, "\x00") {
+		if path == "" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(worktree_path, path)); err != nil && !os.IsNotExist(err) {
+			return saved, err
+		}
+	}
+	_, err = checkpoint_git(worktree_path, nil, "restore", "--source="+c.SHA, "--worktree", "--", ".")
+	return saved, err
+}
+
+// DeleteCheckpoint removes checkpoint c's ref.
+func DeleteCheckpoint(worktree_path string, c Checkpoint) error {
+	_, err := checkpoint_git(worktree_path, nil, "update-ref", "-d", c.Ref, c.SHA)
+	return err
+}
+
+// DeleteCheckpoints removes every checkpoint of alias, once its worktree is
+// removed: a later worktree reusing the alias mustn't inherit them, and
+// their objects can then be garbage collected. The refs are shared by all
+// of a repo's worktrees, so repo_root can be any of them.
+func DeleteCheckpoints(repo_root, alias string) error {
+	list, err := list_checkpoint_refs(repo_root, alias)
+	if err != nil {
+		return err
+	}
+	for _, c := range list {
+		if err := DeleteCheckpoint(repo_root, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenameCheckpoints moves a worktree's checkpoints from its old alias to
+// its new one, e.g. after a rename. Numbers already taken under new_alias are
+// left where they are.
+func RenameCheckpoints(worktree_path, old_alias, new_alias string) error {
+	list, err := list_checkpoint_refs(worktree_path, old_alias)
+	if err != nil {
+		return err
+	}
+	for _, c := range list {
+		if _, err := checkpoint_git(worktree_path, nil, "update-ref", checkpoint_ref(new_alias, c.N), c.SHA, ""); err != nil {
+			continue
+		}
+		if _, err := checkpoint_git(worktree_path, nil, "update-ref", "-d", c.Ref, c.SHA); err != nil {
+			return err
+		}
+	}
+	return nil
+}
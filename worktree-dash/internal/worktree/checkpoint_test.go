@@ -0,0 +1,115 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCheckpointRefs(t *testing.T) {
+	prefix := "refs/wt/checkpoints/api/"
+	out := strings.Join([]string{
+		prefix + "10\x00ccc\x00aaa\x001700000200\x00Before Claude",
+		prefix + "2\x00bbb\x00\x001700000100\x00Checkpoint",
+		prefix + "tmp\x00ddd\x00aaa\x001700000300\x00stray",
+	}, "\n")
+	list := ParseCheckpointRefs(out, prefix)
+	if len(list) != 2 || list[0].N != 2 || list[0].Parent != "" || list[1].N != 10 ||
+		list[1].Message != "Before Claude" || list[1].Date.Unix() != 1700000200 {
+		t.Errorf("parsed: %+v", list)
+	}
+}
+
+func TestCheckpoints(t *testing.T) {
+	dir := t.TempDir()
+	git := git_runner(t, dir)
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	write(".gitignore", "*.log\n")
+	write("a.txt", "one\n")
+	git("add", ".")
+	git("commit", "-qm", "init")
+
+	// An unstaged edit, a staged file and an untracked one all go in; HEAD and the index stay
+	write("a.txt", "two\n")
+	write("b.txt", "staged\n")
+	git("add", "b.txt")
+	write("c.txt", "untracked\n")
+	write("debug.log", "ignored\n")
+	first, err := CreateCheckpoint(dir, "api", "Checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, _ := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if first.N != 1 || first.Parent == "" || string(status) != " M a.txt\nA  b.txt\n?? c.txt\n" {
+		t.Errorf("first: %+v, status:\n%s", first, status)
+	}
+
+	write("a.txt", "three\n")
+	os.Remove(filepath.Join(dir, "c.txt"))
+	write("d.txt", "new\n")
+	if _, err := CreateCheckpoint(dir, "api", "Before Claude"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := ListCheckpoints(dir, "api")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("list: %+v, %v", list, err)
+	}
+	if list[0].N != 2 || list[0].From != first.SHA || list[0].Files != 3 || list[0].Added != 2 || list[0].Deleted != 2 {
+		t.Errorf("second: %+v", list[0])
+	}
+	if list[1].Files != 3 || list[1].Message != "Checkpoint" {
+		t.Errorf("first: %+v", list[1])
+	}
+	files, err := CheckpointFiles(dir, list[0].From, list[0].SHA)
+	if err != nil || len(files) != 3 {
+		t.Errorf("files: %+v, %v", files, err)
+	}
+
+	saved, err := RestoreCheckpoint(dir, "api", list[1])
+	if err != nil || saved.N != 3 || saved.Message != "Before restoring #1" {
+		t.Fatalf("restore: %+v, %v", saved, err)
+	}
+	for name, want := range map[string]string{"a.txt": "two\n", "c.txt": "untracked\n", "debug.log": "ignored\n"} {
+		if data, _ := os.ReadFile(filepath.Join(dir, name)); string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "d.txt")); err == nil {
+		t.Error("d.txt was created after the checkpoint and should be gone")
+	}
+
+	if err := RenameCheckpoints(dir, "api", "billing"); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := ListCheckpoints(dir, "api"); len(list) != 0 {
+		t.Errorf("old alias still has %d checkpoints", len(list))
+	}
+	list, _ = ListCheckpoints(dir, "billing")
+	if len(list) != 3 || DeleteCheckpoint(dir, list[0]) != nil {
+		t.Fatalf("renamed: %+v", list)
+	}
+	if list, _ := ListCheckpoints(dir, "billing"); len(list) != 2 {
+		t.Errorf("after delete: %+v", list)
+	}
+
+	// Removing the worktree drops all of its checkpoints, and only its
+	if _, err := CreateCheckpoint(dir, "bill", "Checkpoint"); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteCheckpoints(dir, "billing"); err != nil {
+		t.Fatal(err)
+	}
+	billing, _ := ListCheckpoints(dir, "billing")
+	bill, _ := ListCheckpoints(dir, "bill")
+	if len(billing) != 0 || len(bill) != 1 {
+		t.Errorf("after removal: billing %+v, bill %+v", billing, bill)
+	}
+}
@@ -0,0 +1,197 @@
+package worktree
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileChange is a file that differs from the merge-base with the base ref,
+// in a commit or in the working tree.
+type FileChange struct {
+	Path    string
+	OldPath string // path before a rename or copy
+	Status  string // "M", "A", "D", "R", "C", "T", or "?" for an untracked file
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// MergeBase returns the commit where HEAD forked from base.
+func MergeBase(worktree_path, base string) (string, error) {
+	out, err := exec.Command("git", "-C", worktree_path, "merge-base", "HEAD", base).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ChangedFiles lists the files the working tree changes relative to
+// merge_base, which covers both commits and uncommitted changes, followed by
+// untracked files. Sorted by path.
+func ChangedFiles(worktree_path, merge_base string) ([]FileChange, error) {
+	files, err := diff_files(worktree_path, "diff", merge_base)
+	if err != nil {
+		return nil, err
+	}
+
+	if out, err := exec.Command("git", "-C", worktree_path, "ls-files", "-z", "--others", "--exclude-standard").Output(); err == nil {
+		for _, path := range strings.Split(string(out), "\x00") {
+			if path == "" {
+				continue
+			}
+			f := FileChange{Path: path, Status: "?"}
+			if data, err := os.ReadFile(filepath.Join(worktree_path, path)); err == nil {
+				f.Binary = bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0
+				if !f.Binary {
+					f.Added = bytes.Count(data, []byte("\n"))
+					if len(data) > 0 && data[len(data)-1] != '\n' {
+						f.Added++
+					}
+				}
+			}
+			files = append(files, f)
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// ChangedPaths lists the paths the working tree changes since it forked
+// from base_ref, committed or not, untracked files included. Sorted; nil when
+// there's no merge-base.
+func ChangedPaths(worktree_path, base_ref string) []string {
+	mb, err := MergeBase(worktree_path, base_ref)
+	if err != nil {
+		return nil
+	}
+	out, err := exec.Command("git", "-C", worktree_path, "diff", "--name-only", "-z", "--no-renames", mb).Output()
+	if err != nil {
+		return nil
+	}
+	untracked, _ := exec.Command("git", "-C", worktree_path, "ls-files", "-z", "--others", "--exclude-standard").Output()
+	var paths []string
+	for _, path := range strings.Split(string(out)+string(untracked), "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// diff_files runs a git diff command (diff or diff-tree) over revs for the
+// file statuses, then again for their line counts.
+func diff_files(worktree_path, command string, revs ...string) ([]FileChange, error) {
+	git := func(format string) ([]byte, error) {
+		args := append([]string{"-C", worktree_path, command, "-r", "-z", "-M", format}, revs...)
+		return exec.Command("git", args...).Output()
+	}
+	status, err := git("--name-status")
+	if err != nil {
+		return nil, err
+	}
+	numstat, err := git("--numstat")
+	if err != nil {
+		return nil, err
+	}
+	files := ParseNameStatus(string(status))
+	counts := ParseNumstat(string(numstat))
+	for i := range files {
+		if c, ok := counts[files[i].Path]; ok {
+			files[i].Added, files[i].Deleted, files[i].Binary = c.Added, c.Deleted, c.Binary
+		}
+	}
+	return files, nil
+}
+
+// ParseNameStatus parses `git diff -z --name-status` output. Renames and
+// copies carry the old path before the new one.
+func ParseNameStatus(out string) []FileChange {
+	fields := strings.Split(out, "\x00")
+	var files []FileChange
+	for i := 0; i < len(fields); i++ {
+		code := fields[i]
+		if code == "" {
+			continue
+		}
+		f := FileChange{Status: code[:1]}
+		switch {
+		case (f.Status == "R" || f.Status == "C") && i+2 < len(fields):
+			f.OldPath, f.Path = fields[i+1], fields[i+2]
+			i += 2
+		case i+1 < len(fields):
+			f.Path = fields[i+1]
+			i++
+		default:
+			continue
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// ParseNumstat parses `git diff -z --numstat` output into line counts by
+// path (the new path for renames). Binary files count "-" lines.
+func ParseNumstat(out string) map[string]FileChange {
+	counts := map[string]FileChange{}
+	fields := strings.Split(out, "\x00")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		var f FileChange
+		if parts[0] == "-" && parts[1] == "-" {
+			f.Binary = true
+		} else {
+			f.Added, _ = strconv.Atoi(parts[0])
+			f.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		f.Path = parts[2]
+		if f.Path == "" && i+2 < len(fields) {
+			// A rename: the old and new paths follow as their own fields
+			f.OldPath, f.Path = fields[i+1], fields[i+2]
+			i += 2
+		}
+		counts[f.Path] = f
+	}
+	return counts
+}
+
+// FileDiff returns the unified diff of one changed file against merge_base.
+// An untracked file is diffed against /dev/null.
+func FileDiff(worktree_path, merge_base string, f FileChange) (string, error) {
+	var cmd *exec.Cmd
+	if f.Status == "?" {
+		cmd = exec.Command("git", "-C", worktree_path, "diff", "--no-color", "--no-index", "--", "/dev/null", f.Path)
+	} else {
+		args := []string{"-C", worktree_path, "diff", "--no-color", "-M", merge_base, "--"}
+		if f.OldPath != "" {
+			args = append(args, f.OldPath)
+		}
+		cmd = exec.Command("git", append(args, f.Path)...)
+	}
+	out, err := cmd.Output()
+	// --no-index exits 1 when the files differ, which they always do here
+	if exit, ok := err.(*exec.ExitError); ok && f.Status == "?" && exit.ExitCode() == 1 {
+		err = nil
+	}
+	return string(out), err
+}
+
+// HunkStarts returns the indexes of the "@@" hunk headers in a diff's lines.
+func HunkStarts(lines []string) []int {
+	var starts []int
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
@@ -0,0 +1,84 @@
+package worktree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNameStatusAndNumstat(t *testing.T) {
+	files := ParseNameStatus("M\x00src/a.ts\x00R087\x00old/b.go\x00new/b.go\x00A\x00logo.png\x00")
+	if len(files) != 3 || files[1].Status != "R" || files[1].OldPath != "old/b.go" || files[1].Path != "new/b.go" || files[2].Path != "logo.png" {
+		t.Fatalf("name-status: %+v", files)
+	}
+	counts := ParseNumstat("3\t1\tsrc/a.ts\x002\t2\t\x00old/b.go\x00new/b.go\x00-\t-\tlogo.png\x00")
+	if c := counts["src/a.ts"]; c.Added != 3 || c.Deleted != 1 {
+		t.Errorf("modified: %+v", c)
+	}
+	if c := counts["new/b.go"]; c.Added != 2 || c.OldPath != "old/b.go" {
+		t.Errorf("renamed: %+v", c)
+	}
+	if !counts["logo.png"].Binary {
+		t.Error("binary file not detected")
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	git := git_runner(t, dir)
+	git("init", "-q", "-b", "main")
+	write_file(t, dir, "a.txt", "one\ntwo\n")
+	write_file(t, dir, "gone.txt", "x\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Initial commit")
+	git("checkout", "-q", "-b", "feat/x")
+	write_file(t, dir, "b.txt", "b\n")
+	git("rm", "-q", "gone.txt")
+	git("add", ".")
+	git("commit", "-q", "-m", "Add b")
+	// main moves on; its changes aren't the branch's
+	git("checkout", "-q", "main")
+	write_file(t, dir, "main.txt", "m\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Main work")
+	git("checkout", "-q", "feat/x")
+	write_file(t, dir, "a.txt", "one\n2\nthree\n")
+	write_file(t, dir, "new.txt", "n1\nn2")
+
+	base, err := MergeBase(dir, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := ChangedFiles(dir, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range files {
+		got = append(got, f.Status+" "+f.Path)
+	}
+	if strings.Join(got, ", ") != "M a.txt, A b.txt, D gone.txt, ? new.txt" {
+		t.Fatalf("files = %v", got)
+	}
+	if files[0].Added != 2 || files[0].Deleted != 1 || files[3].Added != 2 {
+		t.Errorf("counts: %+v", files)
+	}
+
+	diff, err := FileDiff(dir, base, files[0])
+	if err != nil || !strings.Contains(diff, "-two\n+2\n+three\n") {
+		t.Errorf("diff of a.txt (%v):\n%s", err, diff)
+	}
+	diff, err = FileDiff(dir, base, files[3])
+	if err != nil || !strings.Contains(diff, "+n1\n") {
+		t.Errorf("diff of untracked new.txt (%v):\n%s", err, diff)
+	}
+	if starts := HunkStarts(strings.Split(diff, "\n")); len(starts) != 1 {
+		t.Errorf("hunks: %v", starts)
+	}
+
+	if got := strings.Join(ChangedPaths(dir, "main"), ", "); got != "a.txt, b.txt, gone.txt, new.txt" {
+		t.Errorf("changed paths = %s", got)
+	}
+	if got := ChangedPaths(dir, "no-such-ref"); got != nil {
+		t.Errorf("no merge-base: %v", got)
+	}
+}
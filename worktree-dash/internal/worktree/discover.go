@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/elvisnm/wt/internal/config"
 )
@@ -33,213 +34,224 @@ func Discover(worktrees_dir string, existing []Worktree, cfg *config.Config) []W
 		if !entry.IsDir() {
 			continue
 		}
-
 		full_path := filepath.Join(worktrees_dir, entry.Name())
-		has_compose := file_exists(filepath.Join(full_path, "docker-compose.worktree.yml"))
-		env_filename := ".env.worktree"
-		if cfg != nil && cfg.Env.Filename != "" {
-			env_filename = cfg.Env.Filename
+		if wt, ok := read_worktree(full_path, entry.Name(), traefik_port_map, existing_map, cfg); ok {
+			results = append(results, wt)
 		}
-		has_env := file_exists(filepath.Join(full_path, env_filename))
-		has_pm2_home := file_exists(filepath.Join(full_path, ".pm2"))
-		has_git := file_exists(filepath.Join(full_path, ".git"))
+	}
 
-		// Determine worktree type: check WORKTREE_TYPE env var first (written
-		// by Phase 4 local-setup), fall back to compose file presence.
-		has_docker := has_compose
-		if has_env {
-			if wt_type := read_env_file(full_path, env_filename, "WORKTREE_TYPE"); wt_type != "" {
-				has_docker = (wt_type == "docker")
-			}
-		}
+	for i := range results {
+		results[i].Created, results[i].LastUsed = read_git_times(results[i].Path)
+		results[i].Operation = read_git_operation(results[i].Path)
+		results[i].Sparse = read_sparse(results[i].Path)
+		results[i].SparseProfile = SparseProfile(results[i].Sparse, cfg)
+	}
+	return results
+}
 
-		if !has_docker && !has_git && !has_env {
-			continue
+// read_worktree reads one worktree directory's metadata. It reports false
+// when the directory has neither a git link nor worktree env/compose files.
+func read_worktree(full_path, name string, traefik_port_map map[int][]traefik_route, existing_map map[string]*Worktree, cfg *config.Config) (Worktree, bool) {
+	has_compose := file_exists(filepath.Join(full_path, "docker-compose.worktree.yml"))
+	env_filename := ".env.worktree"
+	if cfg != nil && cfg.Env.Filename != "" {
+		env_filename = cfg.Env.Filename
+	}
+	has_env := file_exists(filepath.Join(full_path, env_filename))
+	has_pm2_home := file_exists(filepath.Join(full_path, ".pm2"))
+	has_git := file_exists(filepath.Join(full_path, ".git"))
+
+	// Determine worktree type: check WORKTREE_TYPE env var first (written
+	// by Phase 4 local-setup), fall back to compose file presence.
+	has_docker := has_compose
+	if has_env {
+		if wt_type := read_env_file(full_path, env_filename, "WORKTREE_TYPE"); wt_type != "" {
+			has_docker = (wt_type == "docker")
 		}
+	}
 
-		name := entry.Name()
-		branch := read_branch(full_path)
+	if !has_docker && !has_git && !has_env {
+		return Worktree{}, false
+	}
 
-		if !has_docker {
-			wt := Worktree{
-				Path:        full_path,
-				Name:        name,
-				Type:        TypeLocal,
-				Alias:       name,
-				Branch:      branch,
-				IsolatedPM2: has_pm2_home,
-			}
+	branch := read_branch(full_path)
 
-			// Read metadata from .env.worktree if present
-			if has_env {
-				alias_var := "WORKTREE_ALIAS"
-				if cfg != nil {
-					if v := cfg.WorktreeVar("alias"); v != "" {
-						alias_var = v
+	if !has_docker {
+		wt := Worktree{
+			Path:        full_path,
+			Name:        name,
+			Type:        TypeLocal,
+			Alias:       name,
+			Branch:      branch,
+			IsolatedPM2: has_pm2_home,
+		}
+
+		// Read metadata from .env.worktree if present
+		if has_env {
+			alias_var := "WORKTREE_ALIAS"
+			if cfg != nil {
+				if v := cfg.WorktreeVar("alias"); v != "" {
+					alias_var = v
+				}
+			}
+			if a := read_env_file(full_path, env_filename, alias_var); a != "" {
+				wt.Alias = a
+			}
+			wt.Offset = read_offset(full_path, cfg)
+			if cfg != nil {
+				if app_port := cfg.PrimaryPort() + wt.Offset; app_port > 0 {
+					if routes, ok := traefik_port_map[app_port]; ok {
+						wt.Domain = resolve_traefik_domain(routes, wt.Alias)
 					}
 				}
-				if a := read_env_file(full_path, env_filename, alias_var); a != "" {
-					wt.Alias = a
+				if svc_var := cfg.WorktreeVar("services"); svc_var != "" {
+					wt.Mode = read_env_file(full_path, env_filename, svc_var)
 				}
-				wt.Offset = read_offset(full_path, cfg)
-				if cfg != nil {
-					if app_port := cfg.PrimaryPort() + wt.Offset; app_port > 0 {
-						if routes, ok := traefik_port_map[app_port]; ok {
-							wt.Domain = resolve_traefik_domain(routes, wt.Alias)
-						}
-					}
-					if svc_var := cfg.WorktreeVar("services"); svc_var != "" {
-						wt.Mode = read_env_file(full_path, env_filename, svc_var)
-					}
-					if wt.Mode == "" {
-						wt.Mode = cfg.Services.DefaultMode
-					}
+				if wt.Mode == "" {
+					wt.Mode = cfg.Services.DefaultMode
 				}
 			}
-
-			// Preserve runtime state from previous discovery
-			if prev, ok := existing_map[full_path]; ok {
-				wt.Running = prev.Running
-				wt.CPU = prev.CPU
-				wt.Mem = prev.Mem
-				wt.Uptime = prev.Uptime
-			}
-
-			results = append(results, wt)
-			continue
 		}
 
-		// Resolve env var names from config or fall back to hardcoded
-		alias_var := "WORKTREE_ALIAS"
-		host_build_var := "WORKTREE_HOST_BUILD"
-		lan_domain_var := ""
-		db_conn_var := ""
-		if cfg != nil {
-			if v := cfg.WorktreeVar("alias"); v != "" {
-				alias_var = v
-			}
-			if v := cfg.WorktreeVar("hostBuild"); v != "" {
-				host_build_var = v
-			}
-			if v := cfg.EnvVar("lanDomain"); v != "" {
-				lan_domain_var = v
-			}
-			if v := cfg.EnvVar("dbConnection"); v != "" {
-				db_conn_var = v
-			}
+		// Preserve runtime state from previous discovery
+		if prev, ok := existing_map[full_path]; ok {
+			wt.Running = prev.Running
+			wt.CPU = prev.CPU
+			wt.Mem = prev.Mem
+			wt.Uptime = prev.Uptime
 		}
 
-		alias := read_env_file(full_path, env_filename, alias_var)
-		if alias == "" {
-			alias = name
+		return wt, true
+	}
+
+	// Resolve env var names from config or fall back to hardcoded
+	alias_var := "WORKTREE_ALIAS"
+	host_build_var := "WORKTREE_HOST_BUILD"
+	lan_domain_var := ""
+	db_conn_var := ""
+	if cfg != nil {
+		if v := cfg.WorktreeVar("alias"); v != "" {
+			alias_var = v
+		}
+		if v := cfg.WorktreeVar("hostBuild"); v != "" {
+			host_build_var = v
+		}
+		if v := cfg.EnvVar("lanDomain"); v != "" {
+			lan_domain_var = v
 		}
+		if v := cfg.EnvVar("dbConnection"); v != "" {
+			db_conn_var = v
+		}
+	}
 
-		container := read_container_name(full_path)
-		if container == "" {
-			// For shared compose: container name is project-service (e.g. bc-test-workflow-web)
-			is_shared_compose := cfg != nil && cfg.Docker.ComposeStrategy != "generate" && cfg.ComposeFileAbs != ""
-			if is_shared_compose {
-				slug := read_env_file(full_path, env_filename, "BRANCH_SLUG")
-				if slug == "" {
-					slug = alias
-				}
-				primary := cfg.Services.Primary
-				if primary == "" {
-					for k := range cfg.Services.Ports {
-						primary = k
-						break
-					}
+	alias := read_env_file(full_path, env_filename, alias_var)
+	if alias == "" {
+		alias = name
+	}
+
+	container := read_container_name(full_path)
+	if container == "" {
+		// For shared compose: container name is project-service (e.g. bc-test-workflow-web)
+		is_shared_compose := cfg != nil && cfg.Docker.ComposeStrategy != "generate" && cfg.ComposeFileAbs != ""
+		if is_shared_compose {
+			slug := read_env_file(full_path, env_filename, "BRANCH_SLUG")
+			if slug == "" {
+				slug = alias
+			}
+			primary := cfg.Services.Primary
+			if primary == "" {
+				for k := range cfg.Services.Ports {
+					primary = k
+					break
 				}
-				container = fmt.Sprintf("%s-%s-%s", cfg.Name, slug, primary)
-			} else if cfg != nil {
-				container = cfg.ContainerName(name)
-			} else {
-				container = name
 			}
+			container = fmt.Sprintf("%s-%s-%s", cfg.Name, slug, primary)
+		} else if cfg != nil {
+			container = cfg.ContainerName(name)
+		} else {
+			container = name
 		}
+	}
 
-		mode := read_service_mode(full_path, cfg)
-		offset := read_offset(full_path, cfg)
-		host_build := read_env_file(full_path, env_filename, host_build_var) == "true"
-		lan_domain := read_env_file(full_path, env_filename, lan_domain_var)
+	mode := read_service_mode(full_path, cfg)
+	offset := read_offset(full_path, cfg)
+	host_build := read_env_file(full_path, env_filename, host_build_var) == "true"
+	lan_domain := read_env_file(full_path, env_filename, lan_domain_var)
 
-		var app_port int
-		if cfg != nil {
-			app_port = cfg.PrimaryPort() + offset
-		} else {
-			app_port = 3001 + offset
-		}
+	var app_port int
+	if cfg != nil {
+		app_port = cfg.PrimaryPort() + offset
+	} else {
+		app_port = 3001 + offset
+	}
 
-		var container_prefix string
-		if cfg != nil {
-			container_prefix = cfg.ContainerPrefix()
-		} else {
-			container_prefix = ""
-		}
-		container_alias := strings.TrimPrefix(container, container_prefix)
+	var container_prefix string
+	if cfg != nil {
+		container_prefix = cfg.ContainerPrefix()
+	} else {
+		container_prefix = ""
+	}
+	container_alias := strings.TrimPrefix(container, container_prefix)
 
-		domain := lan_domain
-		if domain == "" {
-			if routes, ok := traefik_port_map[app_port]; ok {
-				domain = resolve_traefik_domain(routes, container_alias)
-			}
-		}
-		if domain == "" && cfg != nil {
-			domain = cfg.DomainFor(alias)
+	domain := lan_domain
+	if domain == "" {
+		if routes, ok := traefik_port_map[app_port]; ok {
+			domain = resolve_traefik_domain(routes, container_alias)
 		}
+	}
+	if domain == "" && cfg != nil {
+		domain = cfg.DomainFor(alias)
+	}
 
-		mongo_url := read_env_file(full_path, env_filename, db_conn_var)
-		var db_name string
-		if mongo_url != "" {
-			parts := strings.Split(mongo_url, "/")
-			db_name = parts[len(parts)-1]
-			if db_name == "" {
-				if cfg != nil && cfg.Database != nil {
-					db_name = cfg.Database.DefaultDb
-				} else {
-					db_name = "db"
-				}
-			}
-		} else {
-			if cfg != nil {
-				db_name = cfg.DbName(alias)
+	mongo_url := read_env_file(full_path, env_filename, db_conn_var)
+	var db_name string
+	if mongo_url != "" {
+		parts := strings.Split(mongo_url, "/")
+		db_name = parts[len(parts)-1]
+		if db_name == "" {
+			if cfg != nil && cfg.Database != nil {
+				db_name = cfg.Database.DefaultDb
 			} else {
-				safe_alias := regexp.MustCompile(`[^a-zA-Z0-9_]`).ReplaceAllString(alias, "_")
-				db_name = fmt.Sprintf("db_%s", safe_alias)
+				db_name = "db"
 			}
 		}
-
-		wt := Worktree{
-			Path:      full_path,
-			Name:      name,
-			Type:      TypeDocker,
-			Alias:     alias,
-			Container: container,
-			Mode:      mode,
-			Branch:    branch,
-			HostBuild: host_build,
-			Domain:    domain,
-			LANDomain: lan_domain,
-			DBName:    db_name,
-			Offset:    offset,
+	} else {
+		if cfg != nil {
+			db_name = cfg.DbName(alias)
+		} else {
+			safe_alias := regexp.MustCompile(`[^a-zA-Z0-9_]`).ReplaceAllString(alias, "_")
+			db_name = fmt.Sprintf("db_%s", safe_alias)
 		}
+	}
 
-		// Preserve runtime state from previous discovery
-		if prev, ok := existing_map[full_path]; ok {
-			wt.Running = prev.Running
-			wt.ContainerExists = prev.ContainerExists
-			wt.Health = prev.Health
-			wt.Started = prev.Started
-			wt.Uptime = prev.Uptime
-			wt.CPU = prev.CPU
-			wt.Mem = prev.Mem
-			wt.MemPct = prev.MemPct
-		}
+	wt := Worktree{
+		Path:      full_path,
+		Name:      name,
+		Type:      TypeDocker,
+		Alias:     alias,
+		Container: container,
+		Mode:      mode,
+		Branch:    branch,
+		HostBuild: host_build,
+		Domain:    domain,
+		LANDomain: lan_domain,
+		DBName:    db_name,
+		Offset:    offset,
+	}
 
-		results = append(results, wt)
+	// Preserve runtime state from previous discovery
+	if prev, ok := existing_map[full_path]; ok {
+		wt.Running = prev.Running
+		wt.ContainerExists = prev.ContainerExists
+		wt.Health = prev.Health
+		wt.Started = prev.Started
+		wt.Uptime = prev.Uptime
+		wt.CPU = prev.CPU
+		wt.Mem = prev.Mem
+		wt.MemPct = prev.MemPct
 	}
 
-	return results
+	return wt, true
 }
 
 // ResolveWorktreesDir computes the worktrees directory from a repo root.
@@ -419,9 +431,10 @@ func read_offset(worktree_path string, cfg *config.Config) int {
 	return 0
 }
 
-func read_branch(worktree_path string) string {
-	git_path := filepath.Join(worktree_path, ".git")
-	data, err := os.ReadFile(git_path)
+// git_dir returns the worktree's private git dir from the gitdir: line of its
+// .git file, or "" when .git isn't a worktree link.
+func git_dir(worktree_path string) string {
+	data, err := os.ReadFile(filepath.Join(worktree_path, ".git"))
 	if err != nil {
 		return ""
 	}
@@ -436,6 +449,57 @@ func read_branch(worktree_path string) string {
 	if !filepath.IsAbs(gitdir) {
 		gitdir = filepath.Join(worktree_path, gitdir)
 	}
+	return gitdir
+}
+
+// read_git_times returns when the worktree was added (the .git link is written
+// once by git worktree add) and when git last touched it (HEAD, its reflog or
+// the index, whichever is newest).
+func read_git_times(worktree_path string) (created, last_used time.Time) {
+	if info, err := os.Stat(filepath.Join(worktree_path, ".git")); err == nil {
+		created = info.ModTime()
+	}
+	gitdir := git_dir(worktree_path)
+	if gitdir == "" {
+		return created, created
+	}
+	last_used = created
+	for _, name := range []string{"HEAD", filepath.Join("logs", "HEAD"), "index"} {
+		if info, err := os.Stat(filepath.Join(gitdir, name)); err == nil && info.ModTime().After(last_used) {
+			last_used = info.ModTime()
+		}
+	}
+	return created, last_used
+}
+
+// read_git_operation returns the operation stopped midway in the worktree
+// ("rebase", "merge", "cherry-pick", "revert" or "bisect"), from the state
+// files git leaves in its git dir, or "".
+func read_git_operation(worktree_path string) string {
+	gitdir := git_dir(worktree_path)
+	if gitdir == "" {
+		return ""
+	}
+	for _, op := range []struct{ file, name string }{
+		{"rebase-merge", "rebase"},
+		{"rebase-apply", "rebase"},
+		{"MERGE_HEAD", "merge"},
+		{"CHERRY_PICK_HEAD", "cherry-pick"},
+		{"REVERT_HEAD", "revert"},
+		{"BISECT_LOG", "bisect"},
+	} {
+		if file_exists(filepath.Join(gitdir, op.file)) {
+			return op.name
+		}
+	}
+	return ""
+}
+
+func read_branch(worktree_path string) string {
+	gitdir := git_dir(worktree_path)
+	if gitdir == "" {
+		return ""
+	}
 
 	head_path := filepath.Join(gitdir, "HEAD")
 	head_data, err := os.ReadFile(head_path)
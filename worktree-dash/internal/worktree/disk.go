@@ -0,0 +1,22 @@
+package worktree
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DiskUsage returns the bytes a worktree directory takes on disk, from du.
+// du still prints a total when some files can't be read, so that counts.
+func DiskUsage(path string) (int64, error) {
+	out, err := exec.Command("du", "-sk", path).Output()
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return 0, err
+	}
+	kb, parse_err := strconv.ParseInt(fields[0], 10, 64)
+	if parse_err != nil {
+		return 0, parse_err
+	}
+	return kb * 1024, nil
+}
@@ -31,3 +31,8 @@ func WriteEnvVar(worktree_path, filename, key, value string) error {
 
 	return os.WriteFile(env_path, []byte(content), 0644)
 }
+
+// ReadEnvVar returns a key's value from the worktree env file, or "".
+func ReadEnvVar(worktree_path, filename, key string) string {
+	return read_env_file(worktree_path, filename, key)
+}
@@ -0,0 +1,45 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ExcludeFromGit adds pattern to the repo's info/exclude unless it's listed
+// already, as dc-worktree-up does for the files it generates. The exclude
+// file lives in the common git dir, so it covers every worktree.
+func ExcludeFromGit(worktree_path, pattern string) error {
+	out, err := exec.Command("git", "-C", worktree_path, "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return err
+	}
+	common := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(common) {
+		common = filepath.Join(worktree_path, common)
+	}
+	file := filepath.Join(common, "info", "exclude")
+	content, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		pattern = "\n" + pattern
+	}
+	_, err = f.WriteString(pattern + "\n")
+	return err
+}
@@ -0,0 +1,34 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExcludeFromGit(t *testing.T) {
+	root := t.TempDir()
+	repo, wt := filepath.Join(root, "app"), filepath.Join(root, "feat-x")
+	os.MkdirAll(repo, 0755)
+	git := git_runner(t, repo)
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "init")
+	git("worktree", "add", "-q", "-b", "feat/x", wt)
+	os.MkdirAll(filepath.Join(wt, ".wt-captures"), 0755)
+	write_file(t, filepath.Join(wt, ".wt-captures"), "shell.txt", "$ ls\n")
+
+	for i := 0; i < 2; i++ {
+		if err := ExcludeFromGit(wt, ".wt-captures/"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(repo, ".git", "info", "exclude"))
+	if strings.Count(string(data), ".wt-captures/\n") != 1 {
+		t.Errorf("exclude file:\n%s", data)
+	}
+	if out, _ := exec.Command("git", "-C", wt, "status", "--porcelain").Output(); len(out) != 0 {
+		t.Errorf("captures should be excluded, status:\n%s", out)
+	}
+}
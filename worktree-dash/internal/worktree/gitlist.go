@@ -0,0 +1,152 @@
+package worktree
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/config"
+)
+
+// GitWorktree is one entry of `git worktree list --porcelain`.
+type GitWorktree struct {
+	Path           string
+	Head           string // commit hash
+	Branch         string // e.g. "feat/login"; "" when detached
+	Bare           bool
+	Detached       bool
+	Locked         bool
+	LockReason     string
+	Prunable       bool
+	PrunableReason string
+}
+
+// ListGitWorktrees runs `git worktree list --porcelain` in the repo. The
+// first entry is the main working tree.
+func ListGitWorktrees(repo_root string) ([]GitWorktree, error) {
+	out, err := exec.Command("git", "-C", repo_root, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return nil, err
+	}
+	return ParseWorktreeList(string(out)), nil
+}
+
+// ParseWorktreeList parses `git worktree list --porcelain` output: one
+// block of "key value" lines per worktree, separated by blank lines.
+func ParseWorktreeList(out string) []GitWorktree {
+	var result []GitWorktree
+	var cur *GitWorktree
+	for _, line := range strings.Split(out, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		if key == "worktree" {
+			result = append(result, GitWorktree{Path: value})
+			cur = &result[len(result)-1]
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		switch key {
+		case "HEAD":
+			cur.Head = value
+		case "branch":
+			cur.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			cur.Bare = true
+		case "detached":
+			cur.Detached = true
+		case "locked":
+			cur.Locked, cur.LockReason = true, value
+		case "prunable":
+			cur.Prunable, cur.PrunableReason = true, value
+		}
+	}
+	return result
+}
+
+// DiscoverRepo is Discover merged with the worktrees git knows about. Git's
+// locked, prunable and detached states are copied onto the scanned worktrees,
+// and worktrees registered elsewhere are added with Outside set. If git
+// can't list worktrees, the directory scan is used as is. Worktrees of the
+// related repos in config are attached as set members.
+func DiscoverRepo(repo_root, worktrees_dir string, existing []Worktree, cfg *config.Config) []Worktree {
+	results := Discover(worktrees_dir, existing, cfg)
+	if listed, err := ListGitWorktrees(repo_root); err == nil {
+		results = merge_git_worktrees(results, listed, repo_root, worktrees_dir, existing, cfg)
+	}
+	if cfg != nil {
+		results = AttachSets(results, cfg.Repo.Related)
+	}
+	return results
+}
+
+// merge_git_worktrees applies git's view of the worktrees to a directory
+// scan. The main working tree, bare repos and the dashboard's own checkout
+// aren't added.
+func merge_git_worktrees(results []Worktree, listed []GitWorktree, repo_root, worktrees_dir string, existing []Worktree, cfg *config.Config) []Worktree {
+	by_path := make(map[string]int, len(results))
+	names := make(map[string]bool, len(results))
+	for i, wt := range results {
+		by_path[path_key(wt.Path)] = i
+		names[wt.Name] = true
+	}
+
+	existing_map := make(map[string]*Worktree)
+	for i := range existing {
+		existing_map[existing[i].Path] = &existing[i]
+	}
+
+	for i, gw := range listed {
+		if i == 0 || gw.Bare {
+			continue
+		}
+
+		idx, found := by_path[path_key(gw.Path)]
+		if !found && path_key(gw.Path) == path_key(repo_root) {
+			continue // the checkout the dashboard runs from
+		}
+		if !found {
+			name := filepath.Base(gw.Path)
+			if names[name] {
+				// Keep names unique: they key pins, marks and pending actions
+				name = filepath.Base(filepath.Dir(gw.Path)) + "-" + name
+			}
+			wt, ok := read_worktree(gw.Path, name, nil, existing_map, cfg)
+			if !ok {
+				wt = Worktree{Path: gw.Path, Name: name, Alias: name, Type: TypeLocal}
+			}
+			if wt.Branch == "" {
+				wt.Branch = gw.Branch
+			}
+			wt.Outside = !under_dir(gw.Path, worktrees_dir)
+			wt.Created, wt.LastUsed = read_git_times(wt.Path)
+			wt.Operation = read_git_operation(wt.Path)
+			wt.Sparse = read_sparse(wt.Path)
+			wt.SparseProfile = SparseProfile(wt.Sparse, cfg)
+			results = append(results, wt)
+			names[name] = true
+			idx = len(results) - 1
+		}
+
+		wt := &results[idx]
+		wt.Detached = gw.Detached
+		wt.Locked, wt.LockReason = gw.Locked, gw.LockReason
+		wt.Prunable, wt.PrunableReason = gw.Prunable, gw.PrunableReason
+	}
+	return results
+}
+
+// path_key normalizes a path for comparison; git reports paths with
+// symlinks resolved (e.g. /private/var on macOS).
+func path_key(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return filepath.Clean(path)
+}
+
+// under_dir reports whether path is inside dir.
+func under_dir(path, dir string) bool {
+	rel, err := filepath.Rel(path_key(dir), path_key(path))
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
@@ -0,0 +1,148 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseWorktreeList(t *testing.T) {
+	out := strings.Join([]string{
+		"worktree /code/app",
+		"HEAD 1111111111111111111111111111111111111111",
+		"branch refs/heads/main",
+		"",
+		"worktree /code/app-worktrees/feat-login",
+		"HEAD 2222222222222222222222222222222222222222",
+		"branch refs/heads/feat/login",
+		"locked waiting on review",
+		"",
+		"worktree /tmp/spike",
+		"HEAD 3333333333333333333333333333333333333333",
+		"detached",
+		"prunable gitdir file points to non-existent location",
+		"",
+	}, "\n")
+
+	got := ParseWorktreeList(out)
+	if len(got) != 3 {
+		t.Fatalf("parsed %d entries: %+v", len(got), got)
+	}
+	if got[1].Branch != "feat/login" || !got[1].Locked || got[1].LockReason != "waiting on review" {
+		t.Errorf("locked entry: %+v", got[1])
+	}
+	if !got[2].Detached || got[2].Branch != "" || !got[2].Prunable || got[2].PrunableReason != "gitdir file points to non-existent location" {
+		t.Errorf("detached prunable entry: %+v", got[2])
+	}
+}
+
+func TestMergeGitWorktrees(t *testing.T) {
+	root := t.TempDir()
+	wt_dir := filepath.Join(root, "app-worktrees")
+	outside := filepath.Join(root, "elsewhere", "spike")
+	os.MkdirAll(filepath.Join(wt_dir, "feat-login"), 0755)
+	os.MkdirAll(outside, 0755)
+	write_file(t, outside, ".git", "gitdir: /nonexistent\n")
+
+	scanned := []Worktree{{Path: filepath.Join(wt_dir, "feat-login"), Name: "feat-login", Alias: "login", Type: TypeLocal}}
+	listed := []GitWorktree{
+		{Path: filepath.Join(root, "app"), Branch: "main"},
+		{Path: filepath.Join(wt_dir, "feat-login"), Branch: "feat/login", Locked: true, LockReason: "demo"},
+		{Path: outside, Detached: true},
+		{Path: filepath.Join(wt_dir, "gone"), Branch: "old", Prunable: true},
+	}
+
+	got := merge_git_worktrees(scanned, listed, filepath.Join(root, "app"), wt_dir, nil, nil)
+	if len(got) != 3 {
+		t.Fatalf("merged %d worktrees: %v", len(got), names(got))
+	}
+	if !got[0].Locked || got[0].LockReason != "demo" || got[0].Outside {
+		t.Errorf("scanned worktree: %+v", got[0])
+	}
+	if got[1].Name != "spike" || !got[1].Outside || !got[1].Detached || got[1].Type != TypeLocal {
+		t.Errorf("outside worktree: %+v", got[1])
+	}
+	if got[2].Name != "gone" || got[2].Outside || !got[2].Prunable || got[2].Branch != "old" {
+		t.Errorf("prunable worktree: %+v", got[2])
+	}
+}
+
+func TestAttachSetMembers(t *testing.T) {
+	wts := []Worktree{{Name: "feat-login", Branch: "feat/login"}, {Name: "fix-pay", Branch: "fix/pay"}, {Name: "spike"}}
+	attach_set_members(wts, "lib", []GitWorktree{
+		{Path: "/lib", Branch: "fix/pay"},
+		{Path: "/lib-worktrees/feat-login", Branch: "feat/login"},
+		{Path: "/lib-worktrees/detached", Detached: true},
+		{Path: "/lib-worktrees/gone", Branch: "fix/pay", Prunable: true},
+	})
+	attach_set_members(wts, "ui", []GitWorktree{{Path: "/ui"}, {Path: "/ui-worktrees/feat-login", Branch: "feat/login"}})
+
+	if got := wts[0].SetNames(); len(got) != 2 || got[0] != "lib" || got[1] != "ui" || wts[0].Set[0].Path != "/lib-worktrees/feat-login" {
+		t.Errorf("feat-login set: %+v", wts[0].Set)
+	}
+	// The main checkout and prunable worktrees aren't members
+	if len(wts[1].Set) != 0 || len(wts[2].Set) != 0 {
+		t.Errorf("fix-pay set: %+v, spike set: %+v", wts[1].Set, wts[2].Set)
+	}
+}
+
+func TestReadGitOperation(t *testing.T) {
+	dir := t.TempDir()
+	gitdir := filepath.Join(dir, "gitdir")
+	os.MkdirAll(filepath.Join(gitdir, "rebase-merge"), 0755)
+	write_file(t, dir, ".git", "gitdir: "+gitdir+"\n")
+	if got := read_git_operation(dir); got != "rebase" {
+		t.Errorf("operation = %q", got)
+	}
+	os.RemoveAll(filepath.Join(gitdir, "rebase-merge"))
+	write_file(t, gitdir, "CHERRY_PICK_HEAD", "abc\n")
+	if got := read_git_operation(dir); got != "cherry-pick" {
+		t.Errorf("operation = %q", got)
+	}
+}
+
+func TestDiscoverRepo(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "app")
+	wt_dir := filepath.Join(root, "app-worktrees")
+	os.MkdirAll(repo, 0755)
+	os.MkdirAll(wt_dir, 0755)
+	git := git_runner(t, repo)
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "Initial commit")
+	git("worktree", "add", "-q", "-b", "feat/a", filepath.Join(wt_dir, "feat-a"))
+	git("worktree", "add", "-q", "--detach", filepath.Join(root, "spike"))
+	git("worktree", "lock", "--reason", "demo", filepath.Join(wt_dir, "feat-a"))
+
+	got := DiscoverRepo(repo, wt_dir, nil, nil)
+	if len(got) != 2 {
+		t.Fatalf("found %v", names(got))
+	}
+	if got[0].Name != "feat-a" || got[0].Branch != "feat/a" || !got[0].Locked || got[0].LockReason != "demo" {
+		t.Errorf("feat-a: %+v", got[0])
+	}
+	if got[1].Name != "spike" || !got[1].Outside || !got[1].Detached {
+		t.Errorf("spike: %+v", got[1])
+	}
+}
+
+func TestParseBranches(t *testing.T) {
+	out := strings.Join([]string{
+		"refs/heads/feat/login\x1f1767225600\x1fAdd login form",
+		"refs/remotes/origin/HEAD\x1f1767225600\x1f",
+		"refs/remotes/origin/feat/pay\x1f1767139200\x1fWire up: payments",
+		"refs/tags/v1\x1f1767139200\x1fRelease",
+		"",
+	}, "\n")
+	got := ParseBranches(out)
+	if len(got) != 2 {
+		t.Fatalf("parsed %d branches: %+v", len(got), got)
+	}
+	if got[0].Name != "feat/login" || got[0].Remote || got[0].CommitTime.Unix() != 1767225600 || got[0].Subject != "Add login form" {
+		t.Errorf("local branch: %+v", got[0])
+	}
+	if got[1].Name != "origin/feat/pay" || !got[1].Remote || got[1].Subject != "Wire up: payments" {
+		t.Errorf("remote branch: %+v", got[1])
+	}
+}
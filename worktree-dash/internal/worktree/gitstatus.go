@@ -0,0 +1,201 @@
+package worktree
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitStatus is a worktree's working tree and branch state, read with git.
+// Reading it runs several git commands, so the dashboard collects it in the
+// background and caches it (see app.cmd_refresh_git_status).
+type GitStatus struct {
+	Staged    int // files with staged changes
+	Unstaged  int // tracked files with unstaged changes
+	Untracked int
+	Conflicts int // unmerged paths
+
+	Upstream        string // e.g. "origin/feat/login", or "" if the branch has none
+	UpstreamMissing bool   // Upstream has no remote-tracking ref: never pushed, or deleted
+	UpstreamGone    bool   // Upstream was pushed and then deleted (e.g. after its PR merged)
+	Ahead, Behind   int    // commits versus Upstream
+
+	Base                  string // base ref compared against, e.g. "origin/main"
+	BaseAhead, BaseBehind int
+	Changed               []string // paths changed since the fork from Base (see ChangedPaths)
+
+	// Last commit on HEAD
+	Subject    string
+	Author     string
+	CommitTime time.Time
+
+	Fetched time.Time // when this was read; zero means never
+}
+
+// Known reports whether the status has been read.
+func (s GitStatus) Known() bool {
+	return !s.Fetched.IsZero()
+}
+
+// Changes returns the number of changed, untracked and conflicted files.
+func (s GitStatus) Changes() int {
+	return s.Staged + s.Unstaged + s.Untracked + s.Conflicts
+}
+
+// Dirty reports whether the worktree has uncommitted changes or untracked files.
+func (s GitStatus) Dirty() bool {
+	return s.Changes() > 0
+}
+
+// Unpushed returns the number of commits the upstream doesn't have. Without
+// an upstream, or while it's missing, commits ahead of the base ref count as
+// unpushed.
+func (s GitStatus) Unpushed() int {
+	if s.Upstream != "" && !s.UpstreamMissing {
+		return s.Ahead
+	}
+	return s.BaseAhead
+}
+
+// BaseRefCandidates returns the refs tried as the base branch, in order:
+// repo.baseRefs from the config (remote refs, as dc-worktree-up uses them),
+// else origin/main, main, origin/master, master.
+func BaseRefCandidates(base_refs []string) []string {
+	if len(base_refs) == 0 {
+		return []string{"refs/remotes/origin/main", "refs/heads/main", "refs/remotes/origin/master", "refs/heads/master"}
+	}
+	refs := make([]string, len(base_refs))
+	for i, r := range base_refs {
+		refs[i] = "refs/remotes/" + strings.TrimPrefix(r, "refs/remotes/")
+	}
+	return refs
+}
+
+// ResolveBaseRef returns the first of BaseRefCandidates that exists in the
+// repo, or "" when none does.
+func ResolveBaseRef(repo_root string, base_refs []string) string {
+	for _, ref := range BaseRefCandidates(base_refs) {
+		if exec.Command("git", "-C", repo_root, "rev-parse", "--verify", "--quiet", ref).Run() == nil {
+			return ref
+		}
+	}
+	return ""
+}
+
+// short_ref strips refs/remotes/ or refs/heads/ for display.
+func short_ref(ref string) string {
+	ref = strings.TrimPrefix(ref, "refs/remotes/")
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
+
+// ReadGitStatus reads a worktree's git status. base_ref is a ref from
+// ResolveBaseRef, or "" to skip the base comparison. Commands that fail
+// (not a git checkout, no commits yet) leave their fields zero.
+func ReadGitStatus(worktree_path, base_ref string) GitStatus {
+	var s GitStatus
+	if out, err := exec.Command("git", "-C", worktree_path, "status", "--porcelain=v2", "--branch").Output(); err == nil {
+		s = ParseStatusV2(string(out))
+	}
+
+	if base_ref != "" {
+		out, err := exec.Command("git", "-C", worktree_path, "rev-list", "--left-right", "--count", base_ref+"...HEAD").Output()
+		if err == nil {
+			if fields := strings.Fields(string(out)); len(fields) == 2 {
+				s.Base = short_ref(base_ref)
+				s.BaseBehind, _ = strconv.Atoi(fields[0])
+				s.BaseAhead, _ = strconv.Atoi(fields[1])
+			}
+		}
+		s.Changed = ChangedPaths(worktree_path, base_ref)
+	}
+
+	if out, err := exec.Command("git", "-C", worktree_path, "log", "-1", "--format=%s%x1f%an%x1f%ct").Output(); err == nil {
+		s.Subject, s.Author, s.CommitTime = parse_last_commit(string(out))
+	}
+
+	s.Fetched = time.Now()
+	return s
+}
+
+// ParseStatusV2 parses `git status --porcelain=v2 --branch` output into the
+// file counts and upstream fields of a GitStatus.
+func ParseStatusV2(out string) GitStatus {
+	var s GitStatus
+	has_ab := false
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.upstream "):
+			s.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			has_ab = true
+			for _, f := range strings.Fields(strings.TrimPrefix(line, "# branch.ab ")) {
+				n, _ := strconv.Atoi(f[1:])
+				if f[0] == '+' {
+					s.Ahead = n
+				} else {
+					s.Behind = n
+				}
+			}
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			// "1 XY ..." — X is the index (staged) state, Y the worktree state
+			if len(line) < 4 {
+				continue
+			}
+			if line[2] != '.' {
+				s.Staged++
+			}
+			if line[3] != '.' {
+				s.Unstaged++
+			}
+		case strings.HasPrefix(line, "u "):
+			s.Conflicts++
+		case strings.HasPrefix(line, "? "):
+			s.Untracked++
+		}
+	}
+	// git leaves out the ahead/behind line when the upstream ref is missing,
+	// which can't tell a deleted branch from one not pushed yet
+	s.UpstreamMissing = s.Upstream != "" && !has_ab
+	return s
+}
+
+// MarkGone sets UpstreamGone when the upstream is missing though the branch
+// was seen on the remote as it before (pushed, from the dashboard's state).
+// dc-worktree-up and quick create set the upstream before the first push, so
+// a missing upstream alone doesn't mean it was deleted.
+func (s *GitStatus) MarkGone(pushed string) {
+	s.UpstreamGone = s.UpstreamMissing && pushed != "" && pushed == s.Upstream
+}
+
+// NeverCommitted reports whether branch has had no commits since it was
+// created, from its reflog. A branch like that is reachable from its base
+// without having been merged. Without a reflog, it reports false.
+func NeverCommitted(worktree_path, branch string) bool {
+	out, err := exec.Command("git", "-C", worktree_path, "reflog", "show", "--format=%gs", "refs/heads/"+branch).Output()
+	if err != nil {
+		return false
+	}
+	entries := strings.TrimSpace(string(out))
+	if entries == "" {
+		return false
+	}
+	for _, line := range strings.Split(entries, "\n") {
+		if !strings.HasPrefix(line, "branch: Created") {
+			return false
+		}
+	}
+	return true
+}
+
+// parse_last_commit splits `git log -1 --format=%s%x1f%an%x1f%ct` output.
+func parse_last_commit(out string) (subject, author string, at time.Time) {
+	parts := strings.Split(strings.TrimRight(out, "\n"), "\x1f")
+	if len(parts) != 3 {
+		return "", "", time.Time{}
+	}
+	if secs, err := strconv.ParseInt(parts[2], 10, 64); err == nil {
+		at = time.Unix(secs, 0)
+	}
+	return parts[0], parts[1], at
+}
@@ -0,0 +1,170 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// git_runner returns a function running git in dir with a fixed identity,
+// skipping the test when git isn't installed.
+func git_runner(t *testing.T, dir string) func(args ...string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	return func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Sam", "GIT_AUTHOR_EMAIL=sam@example.com",
+			"GIT_COMMITTER_NAME=Sam", "GIT_COMMITTER_EMAIL=sam@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestParseStatusV2(t *testing.T) {
+	out := strings.Join([]string{
+		"# branch.oid 1f2e3d",
+		"# branch.head feat/login",
+		"# branch.upstream origin/feat/login",
+		"# branch.ab +2 -5",
+		"1 M. N... 100644 100644 100644 aaa bbb src/a.ts",
+		"1 .M N... 100644 100644 100644 aaa bbb src/b.ts",
+		"1 MM N... 100644 100644 100644 aaa bbb src/c.ts",
+		"2 R. N... 100644 100644 100644 aaa bbb R100 src/new.ts\tsrc/old.ts",
+		"u UU N... 100644 100644 100644 100644 aaa bbb ccc src/d.ts",
+		"? notes.txt",
+		"? tmp/",
+		"",
+	}, "\n")
+
+	s := ParseStatusV2(out)
+	if s.Staged != 3 || s.Unstaged != 2 || s.Untracked != 2 || s.Conflicts != 1 {
+		t.Errorf("counts: staged=%d unstaged=%d untracked=%d conflicts=%d", s.Staged, s.Unstaged, s.Untracked, s.Conflicts)
+	}
+	if s.Upstream != "origin/feat/login" || s.Ahead != 2 || s.Behind != 5 {
+		t.Errorf("upstream %q +%d -%d", s.Upstream, s.Ahead, s.Behind)
+	}
+	if s.Changes() != 8 || !s.Dirty() || s.Unpushed() != 2 {
+		t.Errorf("Changes=%d Dirty=%v Unpushed=%d", s.Changes(), s.Dirty(), s.Unpushed())
+	}
+
+	// Without an upstream, commits ahead of the base count as unpushed
+	clean := ParseStatusV2("# branch.oid 1f2e3d\n# branch.head wip\n")
+	clean.BaseAhead = 4
+	if clean.Dirty() || clean.Upstream != "" || clean.Unpushed() != 4 {
+		t.Errorf("no upstream: %+v", clean)
+	}
+
+	// git leaves out branch.ab when the upstream ref is missing: deleted, or
+	// set before the first push. Only a branch seen pushed counts as gone.
+	gone := ParseStatusV2("# branch.oid 1f2e3d\n# branch.head feat/x\n# branch.upstream origin/feat/x\n")
+	if !gone.UpstreamMissing || s.UpstreamMissing || clean.UpstreamMissing {
+		t.Errorf("UpstreamMissing: gone=%v tracked=%v none=%v", gone.UpstreamMissing, s.UpstreamMissing, clean.UpstreamMissing)
+	}
+	unpushed := gone
+	unpushed.MarkGone("")
+	gone.MarkGone("origin/feat/x")
+	s.MarkGone("origin/feat/login")
+	if !gone.UpstreamGone || unpushed.UpstreamGone || s.UpstreamGone {
+		t.Errorf("UpstreamGone: gone=%v never pushed=%v tracked=%v", gone.UpstreamGone, unpushed.UpstreamGone, s.UpstreamGone)
+	}
+	gone.BaseAhead, unpushed.BaseAhead = 3, 3
+	if gone.Unpushed() != 3 || unpushed.Unpushed() != 3 {
+		t.Errorf("with the upstream missing, commits ahead of the base are unpushed: %d, %d", gone.Unpushed(), unpushed.Unpushed())
+	}
+}
+
+func TestParseLastCommit(t *testing.T) {
+	subject, author, at := parse_last_commit("Fix: a | b\x1fSam Lee\x1f1700000000\n")
+	if subject != "Fix: a | b" || author != "Sam Lee" || at.Unix() != 1700000000 {
+		t.Errorf("got %q %q %v", subject, author, at)
+	}
+	if subject, _, at := parse_last_commit(""); subject != "" || !at.IsZero() {
+		t.Error("empty output should parse to nothing")
+	}
+}
+
+func TestBaseRefCandidates(t *testing.T) {
+	if got := BaseRefCandidates(nil); got[0] != "refs/remotes/origin/main" || got[1] != "refs/heads/main" {
+		t.Errorf("defaults = %v", got)
+	}
+	got := BaseRefCandidates([]string{"origin/develop", "refs/remotes/origin/main"})
+	if strings.Join(got, " ") != "refs/remotes/origin/develop refs/remotes/origin/main" {
+		t.Errorf("configured = %v", got)
+	}
+}
+
+func TestReadGitStatus(t *testing.T) {
+	dir := t.TempDir()
+	git := git_runner(t, dir)
+	git("init", "-q", "-b", "main")
+	write_file(t, dir, "a.txt", "a\n")
+	git("add", "a.txt")
+	git("commit", "-q", "-m", "Initial commit")
+	git("checkout", "-q", "-b", "feat/x")
+	write_file(t, dir, "b.txt", "b\n")
+	git("add", "b.txt")
+	git("commit", "-q", "-m", "Add b")
+	write_file(t, dir, "a.txt", "changed\n")
+	write_file(t, dir, "c.txt", "c\n")
+
+	base := ResolveBaseRef(dir, nil)
+	if base != "refs/heads/main" {
+		t.Fatalf("base = %q", base)
+	}
+	s := ReadGitStatus(dir, base)
+	if s.Unstaged != 1 || s.Untracked != 1 || s.Staged != 0 {
+		t.Errorf("changes: %+v", s)
+	}
+	if s.Base != "main" || s.BaseAhead != 1 || s.BaseBehind != 0 {
+		t.Errorf("base: %s +%d -%d", s.Base, s.BaseAhead, s.BaseBehind)
+	}
+	if s.Subject != "Add b" || s.Author != "Sam" || time.Since(s.CommitTime) > time.Hour || !s.Known() {
+		t.Errorf("last commit: %q %q %v", s.Subject, s.Author, s.CommitTime)
+	}
+
+	// dc-worktree-up sets the upstream before the first push
+	git("remote", "add", "origin", filepath.Join(dir, "origin.git"))
+	git("config", "branch.feat/x.remote", "origin")
+	git("config", "branch.feat/x.merge", "refs/heads/feat/x")
+	s = ReadGitStatus(dir, base)
+	s.MarkGone("")
+	if s.Upstream != "origin/feat/x" || !s.UpstreamMissing || s.UpstreamGone || s.Unpushed() != 1 {
+		t.Errorf("never pushed: %+v", s)
+	}
+
+	if s := ReadGitStatus(filepath.Join(dir, "missing"), ""); s.Dirty() || s.Subject != "" {
+		t.Errorf("a missing worktree should read as empty: %+v", s)
+	}
+}
+
+func TestNeverCommitted(t *testing.T) {
+	dir := t.TempDir()
+	git := git_runner(t, dir)
+	git("init", "-q", "-b", "main")
+	write_file(t, dir, "a.txt", "a\n")
+	git("add", "a.txt")
+	git("commit", "-q", "-m", "Initial commit")
+	git("branch", "fresh")
+	git("checkout", "-q", "-b", "worked")
+	write_file(t, dir, "b.txt", "b\n")
+	git("add", "b.txt")
+	git("commit", "-q", "-m", "Add b")
+
+	if !NeverCommitted(dir, "fresh") {
+		t.Error("a branch only created should count as never committed")
+	}
+	if NeverCommitted(dir, "worked") || NeverCommitted(dir, "missing") {
+		t.Error("a branch with commits, or without a reflog, should not")
+	}
+
+	if n, err := DiskUsage(dir); err != nil || n <= 0 {
+		t.Errorf("DiskUsage = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,117 @@
+package worktree
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sort modes for the worktree list.
+const (
+	SortStatus   = "status"    // running docker > running local > stopped > local (SortWorktrees)
+	SortName     = "name"      // alias, A-Z
+	SortLastUsed = "last_used" // most recently used first (LastActive)
+	SortMemory   = "memory"    // highest container memory first
+	SortCreated  = "created"   // newest first
+)
+
+// SortModes lists the sort modes in the order they are offered.
+var SortModes = []string{SortStatus, SortName, SortLastUsed, SortMemory, SortCreated}
+
+// SortLabel returns the display name of a sort mode.
+func SortLabel(mode string) string {
+	switch mode {
+	case SortName:
+		return "name"
+	case SortLastUsed:
+		return "last used"
+	case SortMemory:
+		return "memory"
+	case SortCreated:
+		return "created"
+	}
+	return "status"
+}
+
+// SortBy returns worktrees ordered by mode. Unknown modes sort by status.
+// Ties keep the status order, so e.g. worktrees without memory stats stay grouped.
+func SortBy(worktrees []Worktree, mode string) []Worktree {
+	result := SortWorktrees(worktrees)
+	var less func(a, b Worktree) bool
+	switch mode {
+	case SortName:
+		less = func(a, b Worktree) bool { return strings.ToLower(display_name(a)) < strings.ToLower(display_name(b)) }
+	case SortLastUsed:
+		less = func(a, b Worktree) bool { return a.LastActive().After(b.LastActive()) }
+	case SortMemory:
+		less = func(a, b Worktree) bool { return MemBytes(a.Mem) > MemBytes(b.Mem) }
+	case SortCreated:
+		less = func(a, b Worktree) bool { return a.Created.After(b.Created) }
+	default:
+		return result
+	}
+	sort.SliceStable(result, func(i, j int) bool { return less(result[i], result[j]) })
+	return result
+}
+
+// LastActive returns when wt was last used: its latest git activity or its
+// last start from the dashboard, whichever is later.
+func (wt Worktree) LastActive() time.Time {
+	if wt.LastStarted.After(wt.LastUsed) {
+		return wt.LastStarted
+	}
+	return wt.LastUsed
+}
+
+func display_name(wt Worktree) string {
+	if wt.Alias != "" {
+		return wt.Alias
+	}
+	return wt.Name
+}
+
+// MemBytes parses a docker stats memory figure ("512.3MiB", "1.2GiB", "800kB")
+// into bytes. Returns 0 when mem is empty or unparseable.
+func MemBytes(mem string) float64 {
+	mem = strings.TrimSpace(mem)
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+		{"GB", 1e9}, {"MB", 1e6}, {"kB", 1e3}, {"B", 1},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(mem, u.suffix) {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(mem, u.suffix), 64)
+			if err != nil {
+				return 0
+			}
+			return v * u.scale
+		}
+	}
+	return 0
+}
+
+// Matches reports whether every whitespace-separated term of query appears,
+// case-insensitively, in the worktree's alias, name, branch or domain.
+func Matches(wt Worktree, query string) bool {
+	haystack := strings.ToLower(strings.Join([]string{wt.Alias, wt.Name, wt.Branch, wt.Domain}, " "))
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(haystack, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// BranchGroup returns the first of prefixes that branch starts with, or "".
+func BranchGroup(branch string, prefixes []string) string {
+	for _, p := range prefixes {
+		if p != "" && strings.HasPrefix(branch, p) {
+			return p
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func names(wts []Worktree) []string {
+	out := make([]string, len(wts))
+	for i, wt := range wts {
+		out[i] = wt.Name
+	}
+	return out
+}
+
+func TestSortBy(t *testing.T) {
+	now := time.Now()
+	worktrees := []Worktree{
+		{Name: "b", Alias: "Bravo", Type: TypeLocal, Created: now.Add(-3 * time.Hour), LastUsed: now.Add(-time.Minute)},
+		{Name: "a", Alias: "alpha", Type: TypeDocker, Running: true, Mem: "512MiB", Created: now.Add(-time.Hour), LastUsed: now.Add(-time.Hour)},
+		{Name: "c", Alias: "charlie", Type: TypeDocker, Running: true, Mem: "1.5GiB", Created: now.Add(-2 * time.Hour), LastUsed: now.Add(-2 * time.Hour)},
+	}
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{SortStatus, "acb"},
+		{"", "acb"},
+		{"bogus", "acb"},
+		{SortName, "abc"},
+		{SortMemory, "cab"},
+		{SortCreated, "acb"},
+		{SortLastUsed, "bac"},
+	}
+	for _, tt := range tests {
+		got := ""
+		for _, n := range names(SortBy(worktrees, tt.mode)) {
+			got += n
+		}
+		if got != tt.want {
+			t.Errorf("SortBy(%q) = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestSortByLastActive(t *testing.T) {
+	now := time.Now()
+	worktrees := []Worktree{
+		{Name: "a", LastUsed: now.Add(-time.Minute)},
+		{Name: "b", LastUsed: now.Add(-time.Hour), LastStarted: now},
+		{Name: "c", LastUsed: now.Add(-2 * time.Hour), LastStarted: now.Add(-3 * time.Hour)},
+	}
+	got := ""
+	for _, n := range names(SortBy(worktrees, SortLastUsed)) {
+		got += n
+	}
+	if got != "bac" {
+		t.Errorf("SortBy(last_used) = %q, want a dashboard start to count as use (bac)", got)
+	}
+}
+
+func TestMemBytes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"512MiB", 512 << 20},
+		{"1.5GiB", 1.5 * (1 << 30)},
+		{"800kB", 800e3},
+		{"12B", 12},
+		{"", 0},
+		{"lots", 0},
+	}
+	for _, tt := range tests {
+		if got := MemBytes(tt.in); got != tt.want {
+			t.Errorf("MemBytes(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	wt := Worktree{Name: "feat-login", Alias: "login", Branch: "feat/login-form", Domain: "login.myapp.localhost"}
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"LOGIN", true},
+		{"feat/", true},
+		{"myapp", true},
+		{"form login", true},
+		{"form payment", false},
+	}
+	for _, tt := range tests {
+		if got := Matches(wt, tt.query); got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestBranchGroup(t *testing.T) {
+	prefixes := []string{"feat/", "fix/"}
+	if g := BranchGroup("fix/crash", prefixes); g != "fix/" {
+		t.Errorf("BranchGroup(fix/crash) = %q", g)
+	}
+	if g := BranchGroup("main", prefixes); g != "" {
+		t.Errorf("BranchGroup(main) = %q, want empty", g)
+	}
+}
+
+func TestReadGitTimes(t *testing.T) {
+	dir := t.TempDir()
+	if created, last := read_git_times(dir); !created.IsZero() || !last.IsZero() {
+		t.Errorf("no .git: got %v, %v", created, last)
+	}
+
+	gitdir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".git"), []byte("gitdir: "+gitdir+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitdir, "HEAD"), []byte("ref: refs/heads/main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	added := time.Now().Add(-24 * time.Hour)
+	os.Chtimes(filepath.Join(dir, ".git"), added, added)
+
+	created, last := read_git_times(dir)
+	if !created.Equal(added) {
+		t.Errorf("created = %v, want %v", created, added)
+	}
+	if !last.After(created) {
+		t.Errorf("last used %v should be after created %v", last, created)
+	}
+}
@@ -0,0 +1,127 @@
+package worktree
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// emptyTree is git's empty tree object, what a root commit is diffed against.
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// logFormat puts a NUL before each field so the graph drawing in front of the
+// first one can be split off.
+const logFormat = "%x00%H%x00%P%x00%an%x00%ae%x00%at%x00%D%x00%m%x00%s"
+
+// Commit is one commit in a worktree's history.
+type Commit struct {
+	SHA     string
+	Parents []string
+	Author  string
+	Email   string
+	Date    time.Time
+	Refs    string // decorations, e.g. "HEAD -> feat/x, origin/feat/x"
+	Subject string
+	Side    string // "<" only on the branch, ">" only on the base, "-" where they forked
+}
+
+// Short returns the abbreviated SHA.
+func (c Commit) Short() string {
+	if len(c.SHA) > 7 {
+		return c.SHA[:7]
+	}
+	return c.SHA
+}
+
+// parent is what the commit's changes are diffed against: its first parent,
+// or the empty tree for a root commit.
+func (c Commit) parent() string {
+	if len(c.Parents) > 0 {
+		return c.Parents[0]
+	}
+	return emptyTree
+}
+
+// LogLine is one line of `git log --graph`: the graph drawing and the commit
+// on it, nil on lines that only carry the graph on.
+type LogLine struct {
+	Graph  string
+	Commit *Commit
+}
+
+// ReadLog returns the graph of HEAD against base: up to limit commits only
+// on either side since they forked, and the fork point. With none, or no
+// base, it is HEAD's last limit commits.
+func ReadLog(worktree_path, base string, limit int) ([]LogLine, error) {
+	run := func(revs ...string) ([]LogLine, error) {
+		args := append([]string{"-C", worktree_path, "log", "--graph", "--no-color",
+			"--format=" + logFormat, "-n", strconv.Itoa(limit)}, revs...)
+		out, err := exec.Command("git", args...).Output()
+		if err != nil {
+			return nil, err
+		}
+		return ParseLog(string(out)), nil
+	}
+	if base != "" {
+		lines, err := run("--boundary", "HEAD..."+base)
+		if err != nil || len(lines) > 0 {
+			return lines, err
+		}
+	}
+	return run("HEAD")
+}
+
+// ParseLog parses `git log --graph --format=<logFormat>` output.
+func ParseLog(out string) []LogLine {
+	var lines []LogLine
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\x00")
+		l := LogLine{Graph: strings.TrimRight(fields[0], " ")}
+		if len(fields) >= 9 {
+			c := &Commit{
+				SHA:     fields[1],
+				Parents: strings.Fields(fields[2]),
+				Author:  fields[3],
+				Email:   fields[4],
+				Refs:    fields[6],
+				Side:    fields[7],
+				Subject: fields[8],
+			}
+			if ts, err := strconv.ParseInt(fields[5], 10, 64); err == nil {
+				c.Date = time.Unix(ts, 0)
+			}
+			l.Commit = c
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// CommitMessage returns the full message of a commit.
+func CommitMessage(worktree_path, sha string) (string, error) {
+	out, err := exec.Command("git", "-C", worktree_path, "show", "-s", "--format=%B", sha).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// CommitFiles lists the files a commit changes. Merges are diffed against
+// their first parent.
+func CommitFiles(worktree_path string, c Commit) ([]FileChange, error) {
+	return diff_files(worktree_path, "diff-tree", c.parent(), c.SHA)
+}
+
+// CommitFileDiff returns the unified diff of one file a commit changes.
+func CommitFileDiff(worktree_path string, c Commit, f FileChange) (string, error) {
+	args := []string{"-C", worktree_path, "diff", "--no-color", "-M", c.parent(), c.SHA, "--"}
+	if f.OldPath != "" {
+		args = append(args, f.OldPath)
+	}
+	out, err := exec.Command("git", append(args, f.Path)...).Output()
+	return string(out), err
+}
@@ -0,0 +1,80 @@
+package worktree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLog(t *testing.T) {
+	out := "* \x00abc123\x00p1 p2\x00Sam\x00sam@example.com\x001700000000\x00HEAD -> feat/x\x00<\x00Merge main\n" +
+		"|\\  \n" +
+		"o \x00def456\x00\x00Ana\x00ana@example.com\x001600000000\x00\x00-\x00Initial commit\n"
+	lines := ParseLog(out)
+	if len(lines) != 3 {
+		t.Fatalf("lines: %+v", lines)
+	}
+	c := lines[0].Commit
+	if lines[0].Graph != "*" || c.SHA != "abc123" || len(c.Parents) != 2 || c.Refs != "HEAD -> feat/x" ||
+		c.Side != "<" || c.Subject != "Merge main" || c.Date.Unix() != 1700000000 {
+		t.Errorf("commit: %+v", c)
+	}
+	if lines[1].Commit != nil || lines[1].Graph != `|\` {
+		t.Errorf("graph-only line: %+v", lines[1])
+	}
+	if c := lines[2].Commit; c.Side != "-" || len(c.Parents) != 0 || c.parent() != emptyTree {
+		t.Errorf("root boundary: %+v", c)
+	}
+}
+
+func TestReadLog(t *testing.T) {
+	dir := t.TempDir()
+	git := git_runner(t, dir)
+	git("init", "-q", "-b", "main")
+	write_file(t, dir, "a.txt", "one\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Initial commit")
+	git("checkout", "-q", "-b", "feat/x")
+	write_file(t, dir, "a.txt", "one\ntwo\n")
+	git("commit", "-q", "-am", "Add two\n\nLonger explanation.")
+	git("checkout", "-q", "main")
+	write_file(t, dir, "m.txt", "m\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Main work")
+	git("checkout", "-q", "feat/x")
+
+	lines, err := ReadLog(dir, "main", 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, l := range lines {
+		if l.Commit != nil {
+			got = append(got, l.Commit.Side+" "+l.Commit.Subject)
+		}
+	}
+	if strings.Join(got, ", ") != "> Main work, < Add two, - Initial commit" &&
+		strings.Join(got, ", ") != "< Add two, > Main work, - Initial commit" {
+		t.Fatalf("log = %v", got)
+	}
+
+	// On the base itself there is nothing to compare, so it's plain history
+	lines, err = ReadLog(dir, "feat/x", 50)
+	if err != nil || len(lines) != 2 || lines[1].Commit.Subject != "Initial commit" {
+		t.Fatalf("fallback log (%v): %+v", err, lines)
+	}
+	head, root := *lines[0].Commit, *lines[1].Commit
+
+	if msg, err := CommitMessage(dir, head.SHA); err != nil || msg != "Add two\n\nLonger explanation." {
+		t.Errorf("message (%v): %q", err, msg)
+	}
+	files, err := CommitFiles(dir, head)
+	if err != nil || len(files) != 1 || files[0].Path != "a.txt" || files[0].Added != 1 {
+		t.Fatalf("files (%v): %+v", err, files)
+	}
+	if diff, err := CommitFileDiff(dir, head, files[0]); err != nil || !strings.Contains(diff, "+two\n") {
+		t.Errorf("diff (%v):\n%s", err, diff)
+	}
+	if files, err := CommitFiles(dir, root); err != nil || len(files) != 1 || files[0].Status != "A" {
+		t.Errorf("root commit files (%v): %+v", err, files)
+	}
+}
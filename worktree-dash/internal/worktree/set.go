@@ -0,0 +1,53 @@
+package worktree
+
+import "github.com/elvisnm/wt/internal/config"
+
+// SetMember is a related repo's worktree on the same branch as one of this
+// repo's worktrees; together they form a worktree set.
+type SetMember struct {
+	Repo   string // related repo name from config
+	Path   string
+	Branch string
+}
+
+// AttachSets sets each worktree's set members: the worktrees of the related
+// repos checked out on its branch. A related repo git can't list is skipped.
+func AttachSets(wts []Worktree, related []config.RelatedRepo) []Worktree {
+	for i := range wts {
+		wts[i].Set = nil
+	}
+	for _, r := range related {
+		listed, err := ListGitWorktrees(r.PathAbs)
+		if err != nil {
+			continue
+		}
+		attach_set_members(wts, r.Name, listed)
+	}
+	return wts
+}
+
+// attach_set_members adds repo's worktrees from listed to the worktrees on
+// the same branch. The related repo's main working tree isn't a member.
+func attach_set_members(wts []Worktree, repo string, listed []GitWorktree) {
+	by_branch := map[string]string{}
+	for i, gw := range listed {
+		if i == 0 || gw.Bare || gw.Prunable || gw.Branch == "" {
+			continue
+		}
+		by_branch[gw.Branch] = gw.Path
+	}
+	for i := range wts {
+		if path, ok := by_branch[wts[i].Branch]; ok && wts[i].Branch != "" {
+			wts[i].Set = append(wts[i].Set, SetMember{Repo: repo, Path: path, Branch: wts[i].Branch})
+		}
+	}
+}
+
+// SetNames returns the related repos in wt's set.
+func (wt *Worktree) SetNames() []string {
+	names := make([]string, len(wt.Set))
+	for i, s := range wt.Set {
+		names[i] = s.Repo
+	}
+	return names
+}
@@ -0,0 +1,88 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/config"
+)
+
+// SparseCustom is the profile name of a sparse checkout that matches no
+// profile in config, e.g. after directories were added to it.
+const SparseCustom = "custom"
+
+var sparse_enabled_re = regexp.MustCompile(`(?im)^\s*sparsecheckout\s*=\s*true\s*$`)
+
+// read_sparse returns the directories of a worktree's sparse checkout, nil
+// when it checks out everything. `git sparse-checkout` keeps both halves in
+// the worktree's own git dir: core.sparseCheckout in config.worktree and the
+// cone patterns in info/sparse-checkout.
+func read_sparse(worktree_path string) []string {
+	gitdir := git_dir(worktree_path)
+	if gitdir == "" {
+		return nil
+	}
+	conf, err := os.ReadFile(filepath.Join(gitdir, "config.worktree"))
+	if err != nil || !sparse_enabled_re.Match(conf) {
+		return nil
+	}
+	patterns, err := os.ReadFile(filepath.Join(gitdir, "info", "sparse-checkout"))
+	if err != nil {
+		return nil
+	}
+	return ParseSparseCone(string(patterns))
+}
+
+// ParseSparseCone returns the directories in cone-mode sparse-checkout
+// patterns, sorted. Git adds each directory's parents ("/apps/" for
+// "/apps/api/") and the top-level files ("/*"); those are left out.
+func ParseSparseCone(patterns string) []string {
+	var dirs []string
+	for _, line := range strings.Split(patterns, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "/") || !strings.HasSuffix(line, "/") || strings.Contains(line, "*") {
+			continue
+		}
+		dirs = append(dirs, strings.Trim(line, "/"))
+	}
+	var result []string
+	for _, dir := range dirs {
+		parent := false
+		for _, other := range dirs {
+			if strings.HasPrefix(other, dir+"/") {
+				parent = true
+				break
+			}
+		}
+		if !parent && dir != "" {
+			result = append(result, dir)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// SparseProfile names the profile in cfg checking out exactly dirs,
+// SparseCustom when none does, and "" for a full checkout.
+func SparseProfile(dirs []string, cfg *config.Config) string {
+	if len(dirs) == 0 {
+		return ""
+	}
+	if cfg != nil {
+		want := strings.Join(dirs, "\n")
+		for _, name := range cfg.SparseProfileNames() {
+			profile := make([]string, 0, len(cfg.SparseProfiles[name]))
+			for _, dir := range cfg.SparseProfiles[name] {
+				profile = append(profile, strings.Trim(filepath.ToSlash(dir), "/"))
+			}
+			sort.Strings(profile)
+			if strings.Join(profile, "\n") == want {
+				return name
+			}
+		}
+	}
+	return SparseCustom
+}
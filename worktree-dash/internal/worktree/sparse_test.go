@@ -0,0 +1,66 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/elvisnm/wt/internal/config"
+)
+
+func TestParseSparseCone(t *testing.T) {
+	patterns := "/*\n!/*/\n/packages/\n!/packages/*/\n/apps/\n!/apps/*/\n/apps/api/\n/packages/shared/\n/docs/\n"
+	if got := strings.Join(ParseSparseCone(patterns), ","); got != "apps/api,docs,packages/shared" {
+		t.Errorf("dirs = %q", got)
+	}
+
+	cfg := &config.Config{SparseProfiles: map[string][]string{
+		"api": {"packages/shared/", "apps/api"},
+		"web": {"apps/web", "packages/shared"},
+	}}
+	for _, tc := range []struct {
+		dirs []string
+		want string
+	}{
+		{[]string{"apps/api", "packages/shared"}, "api"},
+		{[]string{"apps/api", "docs", "packages/shared"}, SparseCustom},
+		{nil, ""},
+	} {
+		if got := SparseProfile(tc.dirs, cfg); got != tc.want {
+			t.Errorf("SparseProfile(%v) = %q, want %q", tc.dirs, got, tc.want)
+		}
+	}
+}
+
+func TestReadSparse(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "app")
+	wt := filepath.Join(root, "feat-api")
+	for _, dir := range []string{"apps/api", "apps/web", "packages/shared"} {
+		os.MkdirAll(filepath.Join(repo, dir), 0755)
+		write_file(t, filepath.Join(repo, dir), "index.ts", "x\n")
+	}
+	git := git_runner(t, repo)
+	git("init", "-q", "-b", "main")
+	git("add", ".")
+	git("commit", "-qm", "init")
+	git("worktree", "add", "-q", "--no-checkout", "-b", "feat/api", wt, "main")
+
+	if dirs := read_sparse(wt); dirs != nil {
+		t.Errorf("full checkout read as %v", dirs)
+	}
+	in_wt := git_runner(t, wt)
+	in_wt("sparse-checkout", "set", "--cone", "apps/api", "packages/shared")
+	in_wt("checkout", "-q")
+	if got := strings.Join(read_sparse(wt), ","); got != "apps/api,packages/shared" {
+		t.Errorf("sparse dirs = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(wt, "apps/web")); err == nil {
+		t.Error("apps/web is outside the cone and shouldn't be checked out")
+	}
+	in_wt("sparse-checkout", "disable")
+	if dirs := read_sparse(wt); dirs != nil {
+		t.Errorf("disabled sparse checkout read as %v", dirs)
+	}
+}
@@ -1,5 +1,7 @@
 package worktree
 
+import "time"
+
 // WorktreeType distinguishes docker-based from local worktrees
 type WorktreeType string
 
@@ -29,6 +31,38 @@ type Worktree struct {
 	// Local worktree isolation
 	IsolatedPM2 bool // true when worktree has its own .pm2 directory
 
+	// Git activity, from file times in the worktree's git dir (zero if unknown)
+	Created  time.Time // when the worktree was added
+	LastUsed time.Time // last checkout, commit or index update
+
+	LastStarted time.Time // last start from the dashboard, from the repo state (zero if never)
+
+	// Git worktree state, from git worktree list and the worktree's git dir
+	Outside        bool // registered with git but not under the worktrees dir
+	Detached       bool // HEAD is not on a branch
+	Locked         bool // git worktree lock
+	LockReason     string
+	Prunable       bool // directory is gone; git worktree prune drops it
+	PrunableReason string
+	Operation      string // "rebase", "merge", "cherry-pick", "revert" or "bisect" in progress
+
+	// Sparse checkout, from the worktree's git dir (see read_sparse)
+	Sparse        []string // directories checked out; nil for a full checkout
+	SparseProfile string   // sparseProfiles entry matching Sparse, SparseCustom if none does
+
+	// Dashboard state from ~/.wt/state (see app.apply_repo_state), not discovery
+	Pinned        bool     // always listed first
+	Note          string   // free-text note
+	SyncConflict  string   // base ref the last sync conflicted with, until resolved
+	ConflictFiles []string // files that conflicted
+
+	// Git status from the dashboard's background cache (see app.apply_git_status)
+	Git      GitStatus
+	Overlaps []Overlap // changed files other worktrees change too
+
+	// Worktrees of the related repos on the same branch (see AttachSets)
+	Set []SetMember
+
 	// Runtime state
 	Running         bool
 	ContainerExists bool
@@ -55,3 +89,10 @@ type Service struct {
 	CPU          float64
 	RestartCount int
 }
+
+// Overlap is a file changed in a worktree and in others, a likely merge
+// conflict to come.
+type Overlap struct {
+	Path string
+	With []string // aliases of the other worktrees changing it
+}
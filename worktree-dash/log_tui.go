@@ -0,0 +1,384 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/elvisnm/wt/internal/theme"
+	"github.com/elvisnm/wt/internal/ui"
+	"github.com/elvisnm/wt/internal/worktree"
+)
+
+// logLimit caps the commits read for the graph.
+const logLimit = 500
+
+// logView is the state of the `wt _log` browser: the commit graph, and the
+// commit opened from it with its changed files and their diffs.
+type logView struct {
+	path, base string
+
+	lines   []worktree.LogLine
+	commits []int // indexes of the lines that carry a commit
+	cursor  int   // into commits
+	top     int   // first line shown
+	err     string
+	status  string // outcome of the last copy, shown until the next key
+
+	open    bool // showing the commit at cursor
+	message []string
+	diff    diffView // the open commit's files, and the diff of one of them
+}
+
+// runLog browses a worktree's commits as a graph against its base ref, with
+// each commit's message, changed files and per-file diffs.
+// Args: <path> [--base=<ref>]
+func runLog(args []string) {
+	v := &logView{}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--base=") {
+			v.base = strings.TrimPrefix(arg, "--base=")
+		} else if v.path == "" {
+			v.path = arg
+		}
+	}
+	if v.path == "" {
+		fmt.Fprintln(os.Stderr, "usage: wt _log <path> [--base=<ref>]")
+		os.Exit(1)
+	}
+	if v.base == "" {
+		v.base = worktree.ResolveBaseRef(v.path, nil)
+	}
+	v.base = strings.TrimPrefix(strings.TrimPrefix(v.base, "refs/remotes/"), "refs/heads/")
+	v.load()
+	run_raw_view("_log", v.draw, v.handle_key)
+}
+
+// load reads the graph, keeping the cursor on the same commit when it's
+// still there.
+func (v *logView) load() {
+	selected := ""
+	if c := v.commit(); c != nil {
+		selected = c.SHA
+	}
+	v.err = ""
+	v.commits = nil
+	lines, err := worktree.ReadLog(v.path, v.base, logLimit)
+	if err != nil {
+		v.err = fmt.Sprintf("git log failed: %v", err)
+	}
+	v.lines = lines
+	v.cursor = 0
+	for i, l := range v.lines {
+		if l.Commit == nil {
+			continue
+		}
+		if l.Commit.SHA == selected {
+			v.cursor = len(v.commits)
+		}
+		v.commits = append(v.commits, i)
+	}
+}
+
+// commit returns the commit under the cursor, nil when there are none.
+func (v *logView) commit() *worktree.Commit {
+	if v.cursor < 0 || v.cursor >= len(v.commits) {
+		return nil
+	}
+	return v.lines[v.commits[v.cursor]].Commit
+}
+
+// open_commit reads the commit's message and changed files.
+func (v *logView) open_commit() {
+	c := v.commit()
+	if c == nil {
+		return
+	}
+	v.open = true
+	msg, err := worktree.CommitMessage(v.path, c.SHA)
+	if err != nil {
+		msg = c.Subject
+	}
+	v.message = strings.Split(msg, "\n")
+
+	commit := *c
+	v.diff = diffView{path: v.path}
+	v.diff.file_diff = func(f worktree.FileChange) (string, error) {
+		return worktree.CommitFileDiff(v.path, commit, f)
+	}
+	if v.diff.files, err = worktree.CommitFiles(v.path, commit); err != nil {
+		v.diff.err = fmt.Sprintf("git diff-tree failed: %v", err)
+	}
+}
+
+// copy_sha puts the selected commit's full SHA on the clipboard.
+func (v *logView) copy_sha() {
+	c := v.commit()
+	if c == nil {
+		return
+	}
+	if where, err := copy_to_clipboard(c.SHA); err != nil {
+		v.status = "Copy failed: " + err.Error()
+	} else {
+		v.status = "Copied " + c.Short() + " to the " + where
+	}
+}
+
+// handle_key applies a key press; it returns false to quit.
+func (v *logView) handle_key(k string) bool {
+	v.status = ""
+	if k == "q" || k == "\x03" {
+		return false
+	}
+	if v.open && v.diff.open {
+		return v.diff.handle_key(k)
+	}
+	if v.open {
+		d := &v.diff
+		switch k {
+		case "\x1b[A", "k":
+			d.cursor = max(d.cursor-1, 0)
+		case "\x1b[B", "j":
+			d.cursor = min(d.cursor+1, max(len(d.files)-1, 0))
+		case "\r", "\n", "l", "\x1b[C":
+			d.open_file(d.cursor)
+		case "y":
+			v.copy_sha()
+		case "\x1b", "h", "\x1b[D":
+			v.open = false
+		}
+		return true
+	}
+
+	_, th := termSize()
+	page := max(th-4, 1)
+	last := max(len(v.commits)-1, 0)
+	switch k {
+	case "\x1b[A", "k":
+		v.cursor = max(v.cursor-1, 0)
+	case "\x1b[B", "j":
+		v.cursor = min(v.cursor+1, last)
+	case "\x1b[5~":
+		v.cursor = max(v.cursor-page, 0)
+	case "\x1b[6~":
+		v.cursor = min(v.cursor+page, last)
+	case "g":
+		v.cursor = 0
+	case "G":
+		v.cursor = last
+	case "\r", "\n", "l", "\x1b[C":
+		v.open_commit()
+	case "d":
+		// Straight to the first file's diff
+		v.open_commit()
+		if v.open && len(v.diff.files) > 0 {
+			v.diff.open_file(0)
+		}
+	case "y":
+		v.copy_sha()
+	case "r":
+		v.load()
+	case "\x1b":
+		return false
+	}
+	return true
+}
+
+func (v *logView) draw() {
+	tw, th := termSize()
+	var lines []string
+	switch {
+	case v.open && v.diff.open:
+		lines = v.diff.render_diff(tw, th)
+	case v.open:
+		lines = v.render_commit(tw, th)
+	default:
+		lines = v.render_graph(tw, th)
+	}
+	fmt.Print("\033[2J\033[H\033[?25l")
+	fmt.Print(strings.Join(lines, "\r\n"))
+}
+
+// footer is the key hints, or the outcome of the last copy.
+func (v *logView) footer(hints string) string {
+	if v.status != "" {
+		return " " + theme.FG(theme.Current().Hint) + v.status + ansiReset
+	}
+	return " " + ansiDim + hints + ansiReset
+}
+
+// render_graph renders the header, the graph with one commit per line and
+// the key hints.
+func (v *logView) render_graph(tw, th int) []string {
+	t := theme.Current()
+	header := ansiCyan + "Commits" + ansiReset + " " + pickBold + filepath.Base(v.path) + ansiReset
+	ahead, behind := 0, 0
+	for _, i := range v.commits {
+		switch v.lines[i].Commit.Side {
+		case "<":
+			ahead++
+		case ">":
+			behind++
+		}
+	}
+	if ahead+behind > 0 {
+		header += ansiDim + fmt.Sprintf(" vs %s  %d ahead, %d behind", v.base, ahead, behind) + ansiReset
+	} else if v.base != "" {
+		header += ansiDim + " (nothing apart from " + v.base + ")" + ansiReset
+	}
+	lines := []string{" " + header, ""}
+
+	if v.err != "" {
+		lines = append(lines, " "+theme.FG(t.Error)+v.err+ansiReset)
+	} else if len(v.commits) == 0 {
+		lines = append(lines, " "+pickDim+"No commits"+ansiReset)
+	} else {
+		graph_width := 0
+		for _, l := range v.lines {
+			graph_width = max(graph_width, len([]rune(l.Graph)))
+		}
+		rows := max(th-4, 1)
+		at := v.commits[v.cursor]
+		if at < v.top {
+			v.top = at
+		} else if at >= v.top+rows {
+			v.top = at - rows + 1
+		}
+		for i := v.top; i < len(v.lines) && i < v.top+rows; i++ {
+			lines = append(lines, v.graph_row(v.lines[i], i == at, graph_width, tw))
+		}
+	}
+
+	for len(lines) < th-1 {
+		lines = append(lines, "")
+	}
+	return append(lines, v.footer("↑↓ select  Enter open  d diff  y copy SHA  r refresh  q quit"))
+}
+
+// graph_row renders one graph line: the drawing, then the commit's short
+// SHA, refs, subject, age and author. Commits only on the base are dimmed.
+func (v *logView) graph_row(l worktree.LogLine, selected bool, graph_width, tw int) string {
+	t := theme.Current()
+	prefix := "  "
+	if selected {
+		prefix = ansiCyan + "▸ " + ansiReset
+	}
+	graph := l.Graph + strings.Repeat(" ", graph_width-len([]rune(l.Graph)))
+	c := l.Commit
+	if c == nil {
+		return " " + prefix + ansiDim + graph + ansiReset
+	}
+
+	node := map[string]string{"<": theme.FG(t.Running), ">": ansiDim, "-": theme.FG(t.Hint)}[c.Side]
+	drawn := ansiDim + strings.NewReplacer("*", ansiReset+node+"*"+ansiReset+ansiDim,
+		"o", ansiReset+node+"o"+ansiReset+ansiDim).Replace(graph) + ansiReset
+
+	refs := ""
+	if c.Refs != "" {
+		refs = "(" + c.Refs + ") "
+	}
+	tail := " · " + ui.FormatAge(c.Date) + " · " + c.Author
+	room := tw - 3 - graph_width - 1 - 8 - len([]rune(refs)) - len([]rune(tail))
+	subject := c.Subject
+	if room < 10 {
+		tail, room = "", tw-3-graph_width-1-8-len([]rune(refs))
+	}
+	if r := []rune(subject); room > 1 && len(r) > room {
+		subject = string(r[:room-1]) + "…"
+	}
+
+	text := subject
+	switch {
+	case selected:
+		text = pickBold + subject + ansiReset
+	case c.Side == ">":
+		text = ansiDim + subject + ansiReset
+	}
+	return " " + prefix + drawn + " " + theme.FG(t.Hint) + c.Short() + ansiReset + " " +
+		theme.FG(t.Focus) + refs + ansiReset + text + ansiDim + tail + ansiReset
+}
+
+// render_commit renders the open commit's header and message above its
+// changed files.
+func (v *logView) render_commit(tw, th int) []string {
+	t := theme.Current()
+	c := v.commit()
+	header := " " + ansiCyan + "Commit" + ansiReset + " " + pickBold + c.SHA + ansiReset
+	if c.Refs != "" {
+		header += " " + theme.FG(t.Focus) + "(" + c.Refs + ")" + ansiReset
+	}
+	lines := []string{
+		header,
+		" " + ansiDim + "Author " + ansiReset + c.Author + ansiDim + " <" + c.Email + ">" + ansiReset,
+		" " + ansiDim + "Date   " + ansiReset + c.Date.Format("Mon Jan 2 15:04 2006") + ansiDim + " · " + ui.FormatAge(c.Date) + ansiReset,
+	}
+	if len(c.Parents) > 1 {
+		lines = append(lines, " "+ansiDim+fmt.Sprintf("Merge  changes against its first parent %.7s", c.Parents[0])+ansiReset)
+	}
+	lines = append(lines, "")
+
+	// The message gets up to a third of the screen
+	limit := max(th/3, 3)
+	for i, m := range v.message {
+		if i == limit-1 && len(v.message) > limit {
+			lines = append(lines, "    "+ansiDim+fmt.Sprintf("… %d more lines", len(v.message)-i)+ansiReset)
+			break
+		}
+		lines = append(lines, "    "+truncate_cells(expand_tabs(m), tw-5))
+	}
+	lines = append(lines, "")
+
+	d := &v.diff
+	if d.err != "" {
+		lines = append(lines, " "+theme.FG(t.Error)+d.err+ansiReset)
+	} else if len(d.files) == 0 {
+		lines = append(lines, " "+pickDim+"No file changes"+ansiReset)
+	} else {
+		added, deleted := 0, 0
+		for _, f := range d.files {
+			added += f.Added
+			deleted += f.Deleted
+		}
+		lines = append(lines, " "+pickDim+plural_files(len(d.files))+ansiReset+"  "+
+			theme.FG(t.Running)+fmt.Sprintf("+%d", added)+ansiReset+" "+
+			theme.FG(t.Stopped)+fmt.Sprintf("−%d", deleted)+ansiReset)
+
+		rows := max(th-1-len(lines), 1)
+		if d.cursor < d.list_top {
+			d.list_top = d.cursor
+		} else if d.cursor >= d.list_top+rows {
+			d.list_top = d.cursor - rows + 1
+		}
+		for i := d.list_top; i < len(d.files) && i < d.list_top+rows; i++ {
+			lines = append(lines, d.file_row(d.files[i], i == d.cursor, tw))
+		}
+	}
+
+	for len(lines) < th-1 {
+		lines = append(lines, "")
+	}
+	return append(lines[:th-1], v.footer("↑↓ select  Enter diff  y copy SHA  Esc commits  q quit"))
+}
+
+// copy_to_clipboard puts text on the system clipboard with the first of
+// pbcopy, wl-copy, xclip and xsel found, falling back to the tmux paste
+// buffer. It returns where the text went.
+func copy_to_clipboard(text string) (string, error) {
+	for _, c := range [][]string{{"pbcopy"}, {"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}} {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if cmd.Run() == nil {
+			return "clipboard", nil
+		}
+	}
+	if os.Getenv("TMUX") != "" && exec.Command("tmux", "set-buffer", "--", text).Run() == nil {
+		return "tmux paste buffer", nil
+	}
+	return "", errors.New("no clipboard tool found (pbcopy, wl-copy, xclip or xsel)")
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/elvisnm/wt/internal/sentinel"
 	"github.com/elvisnm/wt/internal/settings"
 	"github.com/elvisnm/wt/internal/terminal"
+	"github.com/elvisnm/wt/internal/theme"
 	"github.com/elvisnm/wt/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -74,6 +75,7 @@ func main() {
 		}
 	}
 	os.Args = filtered
+	applyTheme()
 
 	if len(os.Args) < 2 {
 		launchDashboard()
@@ -99,6 +101,16 @@ func main() {
 		runInput(os.Args[2:])
 	case "_settings":
 		runSettings()
+	case "_diff":
+		runDiff(os.Args[2:])
+	case "_log":
+		runLog(os.Args[2:])
+	case "_checkpoints":
+		runCheckpoints(os.Args[2:])
+	case "_record":
+		runRecord(os.Args[2:])
+	case "_replay":
+		runReplay(os.Args[2:])
 	case "_notify-renderer":
 		runNotifyRenderer(os.Args[2:])
 	case "_heihei":
@@ -174,8 +186,10 @@ func launchDashboardOuter() {
 		os.Exit(1)
 	}
 
-	// Configure key bindings (prefix=Ctrl+], prefix+q, prefix+f, prefix+1-9)
-	pl.ConfigureBindings()
+	// Configure key bindings (prefix=Ctrl+], prefix+q, prefix+f, prefix+1-9, prefix+p),
+	// remapped by the keybindings section of settings.json
+	tmuxKeys, _ := app.ApplyKeybindings(user_settings.Keybindings)
+	pl.ConfigureBindings(tmuxKeys)
 
 	// Disable tmux status bar — hints are rendered in the bubbletea status bar
 	ts.Run("set-option", "-g", "status", "off")
@@ -468,7 +482,7 @@ func showSplash() func() {
 	if label_col < 1 {
 		label_col = 1
 	}
-	fmt.Printf("\033[%d;%dH\033[38;5;214m%s\033[0m", label_row, label_col, display)
+	fmt.Printf("\033[%d;%dH%s%s\033[0m", label_row, label_col, ansiYellow, display)
 
 	// Pick a random quote and render it below the spinner
 	quote := fmt.Sprintf("\"%s\"", splashQuotes[time.Now().UnixNano()%int64(len(splashQuotes))])
@@ -477,7 +491,7 @@ func showSplash() func() {
 	if quote_col < 1 {
 		quote_col = 1
 	}
-	fmt.Printf("\033[%d;%dH\033[3;38;5;245m%s\033[0m", quote_row, quote_col, quote)
+	fmt.Printf("\033[%d;%dH\033[3m%s%s\033[0m", quote_row, quote_col, theme.FG(theme.Current().Muted), quote)
 
 	// Version below the quote
 	ver_label := versionLabel()
@@ -486,7 +500,7 @@ func showSplash() func() {
 	if ver_col < 1 {
 		ver_col = 1
 	}
-	fmt.Printf("\033[%d;%dH\033[38;5;239m%s\033[0m", ver_row, ver_col, ver_label)
+	fmt.Printf("\033[%d;%dH%s%s\033[0m", ver_row, ver_col, ansiDim, ver_label)
 
 	// Animate spinner in a background goroutine
 	stop := make(chan struct{})
@@ -502,7 +516,7 @@ func showSplash() func() {
 				time.Sleep(80 * time.Millisecond)
 				i++
 				frame := ui.SpinFrames[i%len(ui.SpinFrames)]
-				fmt.Printf("\033[%d;%dH\033[38;5;214m%s\033[0m", label_row, label_col, frame)
+				fmt.Printf("\033[%d;%dH%s%s\033[0m", label_row, label_col, ansiYellow, frame)
 			}
 		}
 	}()
@@ -615,7 +629,7 @@ func renderHeiHeiArt(w, h int) heiHeiLayout {
 		if row > h {
 			break
 		}
-		fmt.Fprintf(&sb, "\033[%d;%dH\033[38;5;240m%s\033[0m", row, start_col, line)
+		fmt.Fprintf(&sb, "\033[%d;%dH%s%s\033[0m", row, start_col, ansiDim, line)
 	}
 	return heiHeiLayout{output: sb.String(), startRow: start_row, height: out_h}
 }
@@ -638,7 +652,7 @@ func renderHeiHeiScreen(w, h int) heiHeiScreenLayout {
 	if msg_col < 1 {
 		msg_col = 1
 	}
-	fmt.Printf("\033[%d;%dH\033[38;5;214m%s\033[0m", msg_row, msg_col, msg)
+	fmt.Printf("\033[%d;%dH%s%s\033[0m", msg_row, msg_col, ansiYellow, msg)
 	return heiHeiScreenLayout{msgRow: msg_row, msgCol: msg_col}
 }
 
@@ -679,7 +693,7 @@ func runHeiHei(audioPath string) {
 		case <-tick.C:
 			i++
 			frame := ui.SpinFrames[i%len(ui.SpinFrames)]
-			fmt.Printf("\033[%d;%dH\033[38;5;214m%s\033[0m", sl.msgRow, sl.msgCol, frame)
+			fmt.Printf("\033[%d;%dH%s%s\033[0m", sl.msgRow, sl.msgCol, ansiYellow, frame)
 		}
 	}
 }
@@ -711,15 +725,33 @@ func termSize() (int, int) {
 	return w, h
 }
 
-// ANSI escape codes for guide styling — 256-color to match lipgloss palette.
+// ANSI escape codes for guide styling, set from the theme by applyTheme.
+var (
+	ansiDim    = "\033[38;5;240m"  // BorderColor
+	ansiCyan   = "\033[1;38;5;34m" // FocusBorderColor, bold
+	ansiYellow = "\033[38;5;214m"  // HintColor
+)
+
 const (
-	ansiDim    = "\033[38;5;240m" // matches BorderColor (240)
-	ansiBold   = "\033[1m"
-	ansiCyan   = "\033[1;38;5;34m" // matches FocusBorderColor (34), bold
-	ansiYellow = "\033[38;5;214m"  // matches HintColor (214)
-	ansiReset  = "\033[0m"
+	ansiBold  = "\033[1m"
+	ansiReset = "\033[0m"
 )
 
+// applyTheme loads the theme from settings for every subcommand, so the
+// dashboard, pickers, notifications and help pages share one palette.
+// Problems are reported by the dashboard itself.
+func applyTheme() {
+	s := settings.Load()
+	app.ApplyTheme(s.Theme, s.Colors)
+	t := theme.Current()
+	ansiDim = theme.FG(t.Border)
+	ansiCyan = theme.Bold(t.Focus)
+	ansiYellow = theme.FG(t.Hint)
+	pickOrange = theme.FG(t.Hint)
+	pickBold = theme.Bold(t.Text)
+	pickDim = theme.FG(t.DimText)
+}
+
 var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
 
 // visLen returns the visible (display) length of a string, ignoring ANSI escapes.
@@ -747,6 +779,38 @@ func guideKey(k string) string {
 	return ansiYellow + k + ansiReset
 }
 
+// userTmuxKeys holds the tmux keys from settings, loaded by loadUserKeys.
+var userTmuxKeys = terminal.DefaultTmuxBindings()
+
+// loadUserKeys applies the user's keybindings so the guide and help pages
+// show the keys actually bound.
+func loadUserKeys() {
+	userTmuxKeys, _ = app.ApplyKeybindings(settings.Load().Keybindings)
+}
+
+// helpKeyLine renders one help entry with the key column padded to 12 cells.
+func helpKeyLine(keys, desc string) string {
+	pad := 12 - utf8.RuneCountInString(keys)
+	if pad < 1 {
+		pad = 1
+	}
+	return guideKey(keys) + strings.Repeat(" ", pad) + desc
+}
+
+// helpKeyLines formats a help section's key hints.
+func helpKeyLines(hints []ui.HintPair) []string {
+	var lines []string
+	for _, h := range hints {
+		lines = append(lines, helpKeyLine(h.Key, h.Desc))
+	}
+	return lines
+}
+
+// tmuxPrefixKey formats "prefix+key" for the guide, e.g. "Ctrl+] q".
+func tmuxPrefixKey(k string) string {
+	return app.TmuxKeyHelp(userTmuxKeys.Prefix) + " " + app.TmuxKeyHelp(k)
+}
+
 // helpBox renders a compact box (top padding only, no bottom padding).
 func helpBox(title string, lines []string, width int) []string {
 	// Temporarily wrap guideBox, then remove the second-to-last line (bottom padding)
@@ -824,17 +888,17 @@ func renderGuide() string {
 	leftSecs = append(leftSecs, guideBox("Worktree", []string{
 		"Select a worktree, then press:",
 		"",
-		guideKey("b") + "  bash shell       " + guideKey("c") + "  claude code",
-		guideKey("g") + "  pull latest      " + guideKey("l") + "  logs",
-		guideKey("n") + "  create new",
+		guideKey(app.KeyHelp("worktree.shell")) + "  bash shell       " + guideKey(app.KeyHelp("worktree.claude")) + "  claude code",
+		guideKey(app.KeyHelp("worktree.pull")) + "  pull latest      " + guideKey(app.KeyHelp("worktree.logs")) + "  logs",
+		guideKey(app.KeyHelp("worktree.create")) + "  create new",
 	}, colW))
 
 	leftSecs = append(leftSecs, guideBox("Terminal", []string{
 		"Sessions open in the right pane.",
 		"To get back:",
 		"",
-		guideKey("Ctrl+] q") + "  return to dashboard",
-		guideKey("Ctrl+] f") + "  toggle fullscreen",
+		guideKey(tmuxPrefixKey(userTmuxKeys.Dashboard)) + "  return to dashboard",
+		guideKey(tmuxPrefixKey(userTmuxKeys.Fullscreen)) + "  toggle fullscreen",
 	}, colW))
 
 	leftSecs = append(leftSecs, guideBox("Tabs", []string{
@@ -959,6 +1023,7 @@ func runNotifyRenderer(args []string) {
 
 func runGuide() {
 	disableEcho()
+	loadUserKeys()
 
 	// Initial render
 	fmt.Print("\033[2J\033[H") // clear screen, cursor home
@@ -1026,80 +1091,38 @@ func renderHelp() string {
 	var leftSecs [][]string
 
 	leftSecs = append(leftSecs, helpBox("Navigation", []string{
-		guideKey("j") + " / " + guideKey("k") + "       navigate list",
-		guideKey("<") + " / " + guideKey(">") + "       switch panel",
-		guideKey("a/w/s") + "       jump to panel",
-		guideKey("Tab") + "         next panel",
+		helpKeyLine(app.KeyHelp("down")+" / "+app.KeyHelp("up"), "navigate list"),
+		helpKeyLine(app.KeyHelp("tab_prev")+" / "+app.KeyHelp("tab_next"), "switch panel"),
+		helpKeyLine(app.KeyHelp("focus_tabs")+"/"+app.KeyHelp("focus_worktrees")+"/"+app.KeyHelp("focus_services"), "jump to panel"),
+		helpKeyLine(app.KeyHelp("tab"), "next panel"),
+		helpKeyLine(app.KeyHelp("palette"), "command palette"),
 		guideKey("1") + "-" + guideKey("9") + "         jump to tab N",
-		guideKey("Esc") + "         back / close",
-	}, colW))
-
-	leftSecs = append(leftSecs, helpBox("Active Tabs", []string{
-		guideKey("Enter") + "       focus terminal",
-		guideKey("h") + " / " + guideKey("l") + "       prev / next tab",
-		guideKey("f") + "           fullscreen",
-		guideKey("x") + "           close tab",
+		helpKeyLine(app.KeyHelp("escape"), "back / close"),
 	}, colW))
 
-	leftSecs = append(leftSecs, helpBox("Worktrees", []string{
-		guideKey("Enter") + "       action menu",
-		guideKey("b") + "           bash shell",
-		guideKey("c") + "           claude code",
-		guideKey("g") + "           pull latest",
-		guideKey("z") + "           local shell (zsh)",
-		guideKey("l") + "           logs",
-		guideKey("n") + "           create worktree",
-		guideKey("i") + "           info",
-		guideKey("e") + "           esbuild watch",
-		guideKey("r") + "           restart container",
-		guideKey("u") + "           start container",
-		guideKey("t") + "           stop container",
-	}, colW))
-
-	leftSecs = append(leftSecs, helpBox("Services", []string{
-		guideKey("Enter") + "       preview logs",
-		guideKey("l") + "           pin logs (tab)",
-		guideKey("r") + "           restart service",
-	}, colW))
+	// Panel and operation keys come from the app's key registries so they
+	// can't drift from the handlers
+	keys := app.HelpKeys()
+	leftSecs = append(leftSecs, helpBox("Active Tabs", helpKeyLines(keys.Tabs), colW))
+	leftSecs = append(leftSecs, helpBox("Worktrees", helpKeyLines(keys.Worktrees), colW))
+	leftSecs = append(leftSecs, helpBox("Services", helpKeyLines(keys.Services), colW))
 
 	leftLines := flattenSections(leftSecs)
 
 	// --- Right column: Tasks, Operations, Tmux, Split Panes ---
 	var rightSecs [][]string
 
-	rightSecs = append(rightSecs, helpBox("Tasks (Shift+T)", []string{
-		guideKey("j") + " / " + guideKey("k") + "       navigate tasks",
-		guideKey("Enter") + "       task detail",
-		guideKey("c") + "           close task",
-		guideKey("d") + "           delete task",
-	}, colW))
-
-	rightSecs = append(rightSecs, helpBox("Operations", []string{
-		guideKey("Shift+A") + "     AWS Keys",
-		guideKey("Shift+B") + "     database picker",
-		guideKey("Shift+D") + "     details toggle",
-		guideKey("Shift+K") + "     skip-worktree",
-		guideKey("Shift+L") + "     LAN toggle",
-		guideKey("Shift+M") + "     maintenance",
-		guideKey("Shift+T") + "     tasks",
-		guideKey("Shift+S") + "     settings",
-		guideKey("Shift+U") + "     Claude usage",
-		guideKey("Shift+X") + "     admin toggle",
-	}, colW))
+	rightSecs = append(rightSecs, helpBox("Tasks ("+keys.TasksKey+")", helpKeyLines(keys.Tasks), colW))
+	rightSecs = append(rightSecs, helpBox("Operations", helpKeyLines(keys.Operations), colW))
 
-	rightSecs = append(rightSecs, helpBox("Tmux  (prefix = Ctrl+])", []string{
-		guideKey("prefix+q") + "    return to dashboard",
-		guideKey("prefix+f") + "    toggle fullscreen",
+	rightSecs = append(rightSecs, helpBox("Tmux  (prefix = "+app.TmuxKeyHelp(userTmuxKeys.Prefix)+")", []string{
+		helpKeyLine("prefix+"+app.TmuxKeyHelp(userTmuxKeys.Dashboard), "return to dashboard"),
+		helpKeyLine("prefix+"+app.TmuxKeyHelp(userTmuxKeys.Fullscreen), "toggle fullscreen"),
 		guideKey("prefix+1-9") + "  jump to tab N",
+		helpKeyLine("prefix+"+app.TmuxKeyHelp(userTmuxKeys.Palette), "command palette"),
 	}, colW))
 
-	rightSecs = append(rightSecs, helpBox("Split Panels  (Active Tabs)", []string{
-		guideKey("Shift+\\") + " " + ansiDim + "(|)" + ansiReset + "  split side by side",
-		guideKey("Shift+-") + " " + ansiDim + "(_)" + ansiReset + "  split below",
-		guideKey("m") + "           move tab into group",
-		guideKey("x") + "           close pane / group",
-		guideKey("Enter") + "       focus selected pane",
-	}, colW))
+	rightSecs = append(rightSecs, helpBox("Split Panels  (Active Tabs)", helpKeyLines(keys.Splits), colW))
 
 	rightLines := flattenSections(rightSecs)
 
@@ -1150,6 +1173,7 @@ func renderHelp() string {
 // runHelp renders keybindings and re-renders on resize. Exits when stdin is closed.
 func runHelp() {
 	disableEcho()
+	loadUserKeys()
 
 	fmt.Print("\033[2J\033[H")
 	fmt.Print(renderHelp())
@@ -9,14 +9,15 @@ import (
 	"golang.org/x/term"
 )
 
-// ANSI codes matching the notification box design
-const (
+// ANSI codes matching the notification box design, set from the theme by applyTheme
+var (
 	pickOrange = "\033[38;5;214m"
 	pickBold   = "\033[1;37m"
 	pickDim    = "\033[38;5;250m"
-	pickReset  = "\033[0m"
 )
 
+const pickReset = "\033[0m"
+
 // runConfirm shows a yes/no confirmation dialog in the bordered box.
 // Args: --title "Title" --prompt "Question?" --sentinel "path"
 func runConfirm(args []string) {
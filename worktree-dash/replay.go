@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/elvisnm/wt/internal/cast"
+
+	"golang.org/x/term"
+)
+
+// replayMaxIdle caps pauses between events so long idle stretches don't stall playback.
+const replayMaxIdle = 2 * time.Second
+
+// runRecord is the pipe-pane target used by Session.StartRecording.
+// Reads raw pane output on stdin and writes an asciinema v2 cast file.
+// Args: <path> <width> <height> [title]
+func runRecord(args []string) {
+	if len(args) < 3 {
+		fmt.Fprintln(os.Stderr, "usage: wt _record <path> <width> <height> [title]")
+		os.Exit(1)
+	}
+	width, _ := strconv.Atoi(args[1])
+	height, _ := strconv.Atoi(args[2])
+	hdr := cast.Header{Width: width, Height: height}
+	if len(args) > 3 {
+		hdr.Title = args[3]
+	}
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wt _record: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if err := cast.Record(os.Stdin, f, hdr); err != nil {
+		fmt.Fprintf(os.Stderr, "wt _record: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runReplay plays a cast file back in the current terminal.
+// Space pauses/resumes, +/- change speed, q quits.
+// Args: [--speed N] <path>
+func runReplay(args []string) {
+	speed := 1.0
+	path := ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--speed":
+			if i+1 < len(args) {
+				if v, err := strconv.ParseFloat(args[i+1], 64); err == nil && v > 0 {
+					speed = v
+				}
+				i++
+			}
+		default:
+			path = args[i]
+		}
+	}
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "usage: wt _replay [--speed N] <path>")
+		os.Exit(1)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wt _replay: %v\n", err)
+		os.Exit(1)
+	}
+	hdr, events, err := cast.Read(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wt _replay: %v\n", err)
+		os.Exit(1)
+	}
+
+	if old_state, err := term.MakeRaw(int(os.Stdin.Fd())); err == nil {
+		defer term.Restore(int(os.Stdin.Fd()), old_state)
+	}
+
+	keys := make(chan byte, 8)
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if n, err := os.Stdin.Read(buf); err != nil {
+				close(keys)
+				return
+			} else if n == 1 {
+				keys <- buf[0]
+			}
+		}
+	}()
+
+	fmt.Print("\033[2J\033[H")
+	if hdr.Width > 0 {
+		if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil && (w < hdr.Width || h < hdr.Height) {
+			fmt.Printf("%s(recorded at %dx%d, pane is %dx%d)%s\r\n", pickDim, hdr.Width, hdr.Height, w, h, pickReset)
+		}
+	}
+
+	paused := false
+	prev := 0.0
+	// played is how much of the pause before the next event has passed, in
+	// recording time, so a keypress doesn't restart the pause
+	played := time.Duration(0)
+	for i := 0; i < len(events); {
+		ev := events[i]
+		gap := time.Duration((ev.Time - prev) * float64(time.Second))
+		if gap > replayMaxIdle {
+			gap = replayMaxIdle
+		}
+		wait := time.Duration(float64(gap-played) / speed)
+		if paused {
+			wait = time.Hour
+		}
+
+		started := time.Now()
+		select {
+		case k, ok := <-keys:
+			if !ok || k == 'q' || k == 0x03 {
+				fmt.Print("\033[0m\r\n")
+				return
+			}
+			if !paused {
+				played += time.Duration(float64(time.Since(started)) * speed)
+			}
+			switch k {
+			case ' ':
+				paused = !paused
+			case '+':
+				speed *= 2
+			case '-':
+				speed /= 2
+			}
+			continue
+		case <-time.After(wait):
+		}
+
+		if ev.Type == "o" {
+			os.Stdout.WriteString(ev.Data)
+		}
+		prev = ev.Time
+		played = 0
+		i++
+	}
+
+	fmt.Printf("\033[0m\r\n%s— end of recording — press q to close%s", pickDim, pickReset)
+	for k := range keys {
+		if k == 'q' || k == 0x03 || k == '\r' || k == 0x1b {
+			break
+		}
+	}
+	fmt.Print("\r\n")
+}
@@ -20,6 +20,7 @@ const (
 	itemLeftPane
 	itemMaxPanes
 	itemClaudeAutoMode
+	itemClaudeCheckpoint
 	itemSave
 	itemExit
 	itemCount // sentinel
@@ -125,6 +126,8 @@ func runSettings() {
 				// no-op, use arrows
 			case itemClaudeAutoMode:
 				s.ClaudeAutoMode = !s.ClaudeAutoMode
+			case itemClaudeCheckpoint:
+				s.ClaudeCheckpoint = !s.ClaudeCheckpoint
 			case itemSave:
 				settings.Save(s)
 				settings.ClearDraft()
@@ -155,7 +158,8 @@ func settings_changed(original, current settings.Settings) bool {
 	return original.DefaultPanels != current.DefaultPanels ||
 		original.LeftPanePct != current.LeftPanePct ||
 		original.MaxPanesPerGroup != current.MaxPanesPerGroup ||
-		original.ClaudeAutoMode != current.ClaudeAutoMode
+		original.ClaudeAutoMode != current.ClaudeAutoMode ||
+		original.ClaudeCheckpoint != current.ClaudeCheckpoint
 }
 
 func draw_settings(s settings.Settings, cursor settingsItem, saved bool) {
@@ -205,6 +209,7 @@ func draw_settings(s settings.Settings, cursor settingsItem, saved bool) {
 	// Claude box
 	var claude_lines []string
 	claude_lines = append(claude_lines, settings_toggle(cursor == itemClaudeAutoMode, "Auto mode", "--enable-auto-mode", s.ClaudeAutoMode))
+	claude_lines = append(claude_lines, settings_toggle(cursor == itemClaudeCheckpoint, "Checkpoint", "on open", s.ClaudeCheckpoint))
 	lines = append(lines, guideBox("Claude Code", claude_lines, col_w)...)
 
 	lines = append(lines, "")
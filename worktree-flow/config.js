@@ -22,6 +22,7 @@ const DEFAULTS = {
     worktreesDir: null, // computed from name if not set
     branchPrefixes: ['feat', 'fix', 'ops', 'hotfix', 'release', 'chore'],
     baseRefs: null, // auto-detected from git if not set (e.g. ['origin/main', 'origin/develop'])
+    syncStrategy: 'rebase', // how bulk sync brings in the base ref: 'rebase' or 'merge'
   },
 
   docker: {
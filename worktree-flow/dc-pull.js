@@ -10,9 +10,15 @@
  *
  * Usage:
  *   node dc-pull.js [worktree-name]
+ *   node dc-pull.js --worktree <path> --onto <base-ref> [--merge]
  *
  * If no name is given, operates on the current working directory
  * (must be inside a worktree).
+ *
+ * With --onto, the branch is rebased onto the base ref (merged with --merge)
+ * instead of pulled; the caller fetches it first. A dirty worktree ends with
+ * a "Skipped: <reason>" line, and conflicts are listed as "Conflict: <file>"
+ * lines before the rebase or merge is aborted. Both exit 1.
  */
 
 const { execSync } = require('child_process');
@@ -31,10 +37,12 @@ const {
 // ── Main ─────────────────────────────────────────────────────────────────
 
 function parse_args(argv) {
-  const opts = { repo: null, worktree: null };
+  const opts = { repo: null, worktree: null, onto: null, merge: false };
   for (let i = 0; i < argv.length; i++) {
     if (argv[i] === '--repo' && argv[i + 1]) { opts.repo = argv[++i]; continue; }
     if (argv[i] === '--worktree' && argv[i + 1]) { opts.worktree = argv[++i]; continue; }
+    if (argv[i] === '--onto' && argv[i + 1]) { opts.onto = argv[++i]; continue; }
+    if (argv[i] === '--merge') { opts.merge = true; continue; }
     if (!opts.worktree && !argv[i].startsWith('-')) { opts.worktree = argv[i]; }
   }
   return opts;
@@ -78,6 +86,10 @@ function main() {
       if (status === '??' || managed.has(file)) continue;
       dirty.push(file);
     }
+    if (dirty.length > 0 && opts.onto) {
+      console.error(`Skipped: uncommitted changes (${dirty.length} file${dirty.length === 1 ? '' : 's'})`);
+      process.exit(1);
+    }
     if (dirty.length > 0) {
       console.error('Aborted: you have uncommitted changes that would conflict with pull:\n');
       for (const f of dirty) {
@@ -102,14 +114,18 @@ function main() {
     checkout_files(worktree_path, tracked_copy_files);
   }
 
-  // Step 3: Pull
-  console.log('Pulling...');
+  // Step 3: Pull, or rebase/merge onto the base ref
   try {
-    execSync('git pull', { cwd: worktree_path, stdio: 'inherit' });
+    if (opts.onto) {
+      sync_onto(worktree_path, opts.onto, opts.merge);
+    } else {
+      console.log('Pulling...');
+      execSync('git pull', { cwd: worktree_path, stdio: 'inherit' });
+    }
   } catch {
     // Pull failed — re-copy and re-skip before exiting so the worktree
     // doesn't end up in a broken state
-    console.error('\nPull failed. Restoring setup files...');
+    if (!opts.onto) console.error('\nPull failed. Restoring setup files...');
     if (has_copy_files) {
       copy_setup_files(repo_root, worktree_path);
       apply_skip_worktree(worktree_path);
@@ -125,7 +141,43 @@ function main() {
   // Step 5: Re-skip config skipWorktree paths
   apply_skip_worktree(worktree_path);
 
-  console.log('Done.');
+  if (!opts.onto) console.log('Done.');
+}
+
+// sync_onto rebases the worktree's branch onto ref, or merges ref into it.
+// On conflicts it lists the conflicted files and aborts, leaving the branch
+// as it was. Throws when the worktree wasn't updated.
+function sync_onto(worktree_path, ref, merge) {
+  const git = (cmd) => run(`git ${cmd}`, { cwd: worktree_path });
+  const behind = Number(git(`rev-list --count HEAD..${ref}`));
+  if (behind === 0) {
+    console.log(`Already up to date with ${ref}`);
+    return;
+  }
+
+  const commits = `${behind} new commit${behind === 1 ? '' : 's'}`;
+  console.log(merge ? `Merging ${ref}...` : `Rebasing onto ${ref}...`);
+  try {
+    git(merge ? `merge --no-edit ${ref}` : `rebase ${ref}`);
+  } catch (err) {
+    let conflicts = [];
+    try {
+      conflicts = git('diff --name-only --diff-filter=U').split('\n').filter(Boolean);
+    } catch {}
+    try {
+      git(merge ? 'merge --abort' : 'rebase --abort');
+    } catch {}
+    if (conflicts.length === 0) {
+      console.error(((err.stderr || err.message) + '').trim());
+      throw err;
+    }
+    for (const f of conflicts) {
+      console.error(`Conflict: ${f}`);
+    }
+    console.error(`Aborted the ${merge ? 'merge' : 'rebase'}: ${ref} conflicts in ${conflicts.length} file${conflicts.length === 1 ? '' : 's'}`);
+    throw err;
+  }
+  console.log(merge ? `Merged ${ref} (${commits})` : `Rebased onto ${ref} (${commits})`);
 }
 
 main();
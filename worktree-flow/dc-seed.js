@@ -14,7 +14,7 @@ const MONGO_HOST = config
 const SOURCE_DB = config ? config.database.defaultDb : 'db';
 
 function parse_args(argv) {
-  const options = { name: null, db: null, drop: false, reset: false };
+  const options = { name: null, db: null, from: null, drop: false, reset: false };
   for (let i = 0; i < argv.length; i++) {
     const arg = argv[i];
     if (arg === '--drop') {
@@ -25,6 +25,10 @@ function parse_args(argv) {
       options.db = argv[++i];
     } else if (arg.startsWith('--db=')) {
       options.db = arg.split('=')[1];
+    } else if (arg === '--from') {
+      options.from = argv[++i];
+    } else if (arg.startsWith('--from=')) {
+      options.from = arg.split('=')[1];
     } else if (!arg.startsWith('--') && !options.name) {
       options.name = arg;
     }
@@ -66,13 +70,40 @@ function require_mongo_container() {
   return container;
 }
 
-function seed_database(mongo_container, target_db) {
-  console.log(`Seeding: ${SOURCE_DB} -> ${target_db}`);
+// run_template runs a database.seedCommand / dropCommand template with its
+// {sourceDb} and {targetDb} placeholders filled in.
+function run_template(template, vars) {
+  let cmd = template;
+  for (const [key, value] of Object.entries(vars)) {
+    cmd = cmd.split(`{${key}}`).join(value);
+  }
+  execSync(cmd, { stdio: 'inherit', shell: true });
+}
+
+function seed_database(source_db, target_db) {
+  console.log(`Seeding: ${source_db} -> ${target_db}`);
+
+  const template = config && config.database.seedCommand;
+  if (template) {
+    console.log('');
+    try {
+      run_template(template, { sourceDb: source_db, targetDb: target_db });
+    } catch (error) {
+      console.error('');
+      console.error('Seed failed:', error.message);
+      process.exit(1);
+    }
+    console.log('');
+    console.log(`Seed complete. Database "${target_db}" is ready.`);
+    return;
+  }
+
+  const mongo_container = require_mongo_container();
   console.log(`Mongo container: ${mongo_container}`);
   console.log('');
 
-  const dump_cmd = `docker exec ${mongo_container} mongodump --host ${MONGO_HOST} --db ${SOURCE_DB} --archive`;
-  const restore_cmd = `docker exec -i ${mongo_container} mongorestore --host ${MONGO_HOST} --archive --nsFrom="${SOURCE_DB}.*" --nsTo="${target_db}.*" --drop`;
+  const dump_cmd = `docker exec ${mongo_container} mongodump --host ${MONGO_HOST} --db ${source_db} --archive`;
+  const restore_cmd = `docker exec -i ${mongo_container} mongorestore --host ${MONGO_HOST} --archive --nsFrom="${source_db}.*" --nsTo="${target_db}.*" --drop`;
 
   try {
     execSync(`${dump_cmd} | ${restore_cmd}`, {
@@ -91,13 +122,30 @@ function seed_database(mongo_container, target_db) {
 
 const PROTECTED_DBS = ['db', 'admin', 'local', 'config'];
 
-function drop_database(mongo_container, target_db) {
+function drop_database(target_db) {
   if (PROTECTED_DBS.includes(target_db)) {
     console.error(`REFUSED: Cannot drop "${target_db}" — this is a protected database.`);
     process.exit(1);
   }
 
   console.log(`Dropping database: ${target_db}`);
+
+  const template = config && config.database.dropCommand;
+  if (template) {
+    console.log('');
+    try {
+      run_template(template, { targetDb: target_db });
+    } catch (error) {
+      console.error('');
+      console.error('Drop failed:', error.message);
+      process.exit(1);
+    }
+    console.log('');
+    console.log(`Database "${target_db}" dropped.`);
+    return;
+  }
+
+  const mongo_container = require_mongo_container();
   console.log(`Mongo container: ${mongo_container}`);
   console.log('');
 
@@ -132,6 +180,7 @@ function main() {
     console.log('  pnpm dc:seed <worktree-name> --drop      Drop the worktree db');
     console.log('  pnpm dc:seed <worktree-name> --reset     Drop and re-seed from shared db');
     console.log('  pnpm dc:seed --db=<db_name> --drop       Drop a database directly (worktree not needed)');
+    console.log('  pnpm dc:seed --db=<db_name> --from=<db>  Copy another database into it');
     process.exit(1);
   }
 
@@ -142,16 +191,20 @@ function main() {
     process.exit(1);
   }
 
-  const mongo_container = require_mongo_container();
+  const source_db = options.from || SOURCE_DB;
+  if (!options.drop && source_db === target_db) {
+    console.error(`REFUSED: Cannot copy "${target_db}" onto itself.`);
+    process.exit(1);
+  }
 
   if (options.drop) {
-    drop_database(mongo_container, target_db);
+    drop_database(target_db);
   } else if (options.reset) {
-    drop_database(mongo_container, target_db);
+    drop_database(target_db);
     console.log('');
-    seed_database(mongo_container, target_db);
+    seed_database(source_db, target_db);
   } else {
-    seed_database(mongo_container, target_db);
+    seed_database(source_db, target_db);
   }
 }
 
@@ -38,6 +38,7 @@ function parse_args(argv) {
     no_host_build: false,
     no_docker: false,
     no_traefik: false,
+    skip_git: false,
     mode: config && config.services.defaultMode ? config.services.defaultMode : 'full',
   };
 
@@ -59,6 +60,7 @@ function parse_args(argv) {
     if (arg === '--no-host-build') { options.no_host_build = true; continue; }
     if (arg === '--no-docker') { options.no_docker = true; continue; }
     if (arg === '--no-traefik') { options.no_traefik = true; continue; }
+    if (arg === '--skip-git') { options.skip_git = true; continue; }
 
     if (arg === '--branch') { options.branch = remaining.shift(); continue; }
     if (arg.startsWith('--branch=')) { options.branch = arg.split('=')[1]; continue; }
@@ -577,6 +579,7 @@ function main() {
     console.log('  --host-build     Run frontend build on host instead of in container');
     console.log('  --no-host-build  Disable host-build mode');
     console.log('  --no-docker      Create worktree without Docker');
+    console.log('  --skip-git       Set up a worktree already added with git worktree add');
     process.exit(1);
   }
 
@@ -600,8 +603,14 @@ function main() {
 
   options.mode_explicit = process.argv.some((a) => a === '--mode' || a.startsWith('--mode='));
 
-  // ── Restart existing worktree ───────────────────────────────────────
-  if (fs.existsSync(worktree_path)) {
+  if (options.skip_git) {
+    // ── Set up a worktree the dashboard already added ─────────────────
+    if (!fs.existsSync(worktree_path)) {
+      console.error(`Worktree not found: ${worktree_path}`);
+      process.exit(1);
+    }
+  } else if (fs.existsSync(worktree_path)) {
+    // ── Restart existing worktree ─────────────────────────────────────
     const compose_strategy = config ? config.docker.composeStrategy : 'generate';
     const is_shared_compose = compose_strategy !== 'generate' && config && config.docker._composeFileResolved;
     const worktree_compose = path.join(worktree_path, 'docker-compose.worktree.yml');